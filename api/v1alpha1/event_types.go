@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The CloudEvents types sent by webhook channels. The API version is part of
+// the type, so the data schema of a type never changes incompatibly.
+const (
+	// EventTypeViolationOpened is sent when a report is opened or reopened.
+	EventTypeViolationOpened = "io.bizaikube.watchdog.v1alpha1.violation.opened"
+	// EventTypeViolationResolved is sent when a report is resolved, to
+	// channels sending resolutions.
+	EventTypeViolationResolved = "io.bizaikube.watchdog.v1alpha1.violation.resolved"
+	// EventTypeDigest is sent for a batch of violations or a scheduled digest.
+	EventTypeDigest = "io.bizaikube.watchdog.v1alpha1.digest"
+	// EventTypeNotice is sent about the notifications themselves, e.g. when
+	// they are rate limited.
+	EventTypeNotice = "io.bizaikube.watchdog.v1alpha1.notice"
+)
+
+// EventSource is the source attribute of the CloudEvents.
+const EventSource = "/gokubedog"
+
+// ViolationEventData is the data of the violation events.
+// +kubebuilder:object:generate=false
+type ViolationEventData struct {
+	// Report references the PolicyViolationReport.
+	Report EventReportReference `json:"report"`
+	// Profile is the name of the violated PolicyProfile.
+	Profile          string               `json:"profile"`
+	ProfileNamespace string               `json:"profileNamespace,omitempty"`
+	Resource         ViolatedResourceSpec `json:"resource"`
+	Severity         Severity             `json:"severity"`
+	// Drift describes each drifted key.
+	Drift map[string]string `json:"drift"`
+	Phase ReportPhase       `json:"phase"`
+	// Ownership is who owns the violated resource, if known.
+	Ownership *Ownership `json:"ownership,omitempty"`
+	// Cluster is the name the manager was given with --cluster-name.
+	Cluster    string       `json:"cluster,omitempty"`
+	OpenedAt   *metav1.Time `json:"openedAt,omitempty"`
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+}
+
+// EventReportReference identifies a PolicyViolationReport.
+// +kubebuilder:object:generate=false
+type EventReportReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// DigestEventData is the data of the digest events.
+// +kubebuilder:object:generate=false
+type DigestEventData struct {
+	Title string `json:"title"`
+	// Total is the number of violations summarized.
+	Total int `json:"total"`
+	// Groups counts the violations per profile and namespace, largest first.
+	Groups []DigestEventGroup `json:"groups"`
+	// TopOffenders are the resources violating the most profiles.
+	TopOffenders []DigestEventOffender `json:"topOffenders,omitempty"`
+}
+
+// DigestEventGroup counts the violations of one profile in one namespace.
+// +kubebuilder:object:generate=false
+type DigestEventGroup struct {
+	Profile   string `json:"profile"`
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
+// DigestEventOffender is a resource and the number of its violations.
+// +kubebuilder:object:generate=false
+type DigestEventOffender struct {
+	Resource ViolatedResourceSpec `json:"resource"`
+	Count    int                  `json:"count"`
+}
+
+// NoticeEventData is the data of the notice events.
+// +kubebuilder:object:generate=false
+type NoticeEventData struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
@@ -0,0 +1,445 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ChannelType is the kind of destination a NotificationChannel delivers to.
+// +kubebuilder:validation:Enum=slack;pagerduty;webhook;alertmanager;email
+type ChannelType string
+
+const (
+	// ChannelTypeSlack posts to Slack through an incoming webhook or a bot.
+	ChannelTypeSlack ChannelType = "slack"
+	// ChannelTypePagerDuty triggers PagerDuty incidents through the Events API v2.
+	ChannelTypePagerDuty ChannelType = "pagerduty"
+	// ChannelTypeWebhook posts CloudEvents to an HTTP endpoint.
+	ChannelTypeWebhook ChannelType = "webhook"
+	// ChannelTypeAlertmanager pushes violations as alerts to Alertmanager.
+	ChannelTypeAlertmanager ChannelType = "alertmanager"
+	// ChannelTypeEmail sends emails through an SMTP server.
+	ChannelTypeEmail ChannelType = "email"
+)
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// SecretReference names a Secret.
+type SecretReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// TemplateSource holds a Go text/template either inline or in a ConfigMap.
+// +kubebuilder:validation:XValidation:rule="has(self.inline) != has(self.configMapKeyRef)",message="exactly one of inline and configMapKeyRef is required"
+type TemplateSource struct {
+	// +optional
+	Inline string `json:"inline,omitempty"`
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// ProfileTemplate overrides the message template for the violations of one
+// PolicyProfile.
+type ProfileTemplate struct {
+	// Profile is the name of the PolicyProfile.
+	Profile string `json:"profile"`
+	// ProfileNamespace restricts the override to the profile in this
+	// namespace. Profiles of that name in any namespace match if unset.
+	// +optional
+	ProfileNamespace string         `json:"profileNamespace,omitempty"`
+	Template         TemplateSource `json:"template"`
+}
+
+// MessageTemplateSpec customizes the text of violation messages. Templates
+// see the report, its profile, the severity, the sorted drift entries and the
+// cluster name; they are validated when the channel is loaded.
+type MessageTemplateSpec struct {
+	// Default replaces the built-in template.
+	// +optional
+	Default *TemplateSource `json:"default,omitempty"`
+	// Profiles overrides the template for the violations of some profiles.
+	// +optional
+	Profiles []ProfileTemplate `json:"profiles,omitempty"`
+}
+
+// SlackChannelSpec configures how a channel posts to Slack.
+// +kubebuilder:validation:XValidation:rule="has(self.webhookURLSecretRef) != has(self.botTokenSecretRef)",message="exactly one of webhookURLSecretRef and botTokenSecretRef is required"
+// +kubebuilder:validation:XValidation:rule="!has(self.botTokenSecretRef) || (has(self.channelID) && size(self.channelID) > 0)",message="channelID is required with botTokenSecretRef"
+type SlackChannelSpec struct {
+	// WebhookURLSecretRef selects the Secret key holding the webhook URL.
+	// +optional
+	WebhookURLSecretRef *SecretKeySelector `json:"webhookURLSecretRef,omitempty"`
+	// BotTokenSecretRef selects the Secret key holding a bot token allowed
+	// to chat:write. Unlike webhooks, bots record the timestamp of their
+	// messages, which resolution messages are threaded under.
+	// +optional
+	BotTokenSecretRef *SecretKeySelector `json:"botTokenSecretRef,omitempty"`
+	// ChannelID is the conversation the bot posts to.
+	// +optional
+	ChannelID string `json:"channelID,omitempty"`
+	// Format selects Block Kit layouts or plain text messages.
+	// +kubebuilder:default=Blocks
+	// +optional
+	Format SlackFormat `json:"format,omitempty"`
+	// Actions adds Acknowledge, Create exception and Auto-remediate buttons to
+	// Block Kit messages. The buttons require the manager interactivity
+	// endpoint to be enabled and configured as the Slack app request URL.
+	// +optional
+	Actions bool `json:"actions,omitempty"`
+}
+
+// SlackFormat is the layout of Slack messages.
+// +kubebuilder:validation:Enum=Blocks;Text
+type SlackFormat string
+
+const (
+	// SlackFormatBlocks posts Block Kit messages with the rendered template as
+	// text, a field per drift entry and, optionally, action buttons.
+	SlackFormatBlocks SlackFormat = "Blocks"
+	// SlackFormatText posts the rendered template as plain text.
+	SlackFormatText SlackFormat = "Text"
+)
+
+// PagerDutyChannelSpec configures a PagerDuty Events API v2 integration.
+type PagerDutyChannelSpec struct {
+	// RoutingKeySecretRef selects the Secret key holding the integration key.
+	RoutingKeySecretRef SecretKeySelector `json:"routingKeySecretRef"`
+}
+
+// CloudEventsMode is the CloudEvents HTTP content mode of a webhook.
+// +kubebuilder:validation:Enum=Structured;Binary
+type CloudEventsMode string
+
+const (
+	// CloudEventsStructured sends the whole event as an
+	// application/cloudevents+json body.
+	CloudEventsStructured CloudEventsMode = "Structured"
+	// CloudEventsBinary sends the event attributes as ce- headers and the
+	// event data as an application/json body.
+	CloudEventsBinary CloudEventsMode = "Binary"
+)
+
+// WebhookChannelSpec configures an HTTP endpoint receiving every report state
+// change as a CloudEvents 1.0 event. The events are documented in
+// docs/events.md.
+type WebhookChannelSpec struct {
+	// URL receives the events as POST requests.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+	// +kubebuilder:default=Structured
+	// +optional
+	Mode CloudEventsMode `json:"mode,omitempty"`
+	// SigningSecretRef selects the Secret key the requests are signed with,
+	// in the X-Gokubedog-Signature header.
+	// +optional
+	SigningSecretRef *SecretKeySelector `json:"signingSecretRef,omitempty"`
+	// HeadersSecretRef names a Secret whose keys and values are sent as
+	// request headers, e.g. an Authorization header.
+	// +optional
+	HeadersSecretRef *SecretReference `json:"headersSecretRef,omitempty"`
+	// ClientCertSecretRef names a kubernetes.io/tls Secret whose tls.crt and
+	// tls.key authenticate the manager to the endpoint. Its ca.crt, if set,
+	// replaces the system roots to verify the endpoint.
+	// +optional
+	ClientCertSecretRef *SecretReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// AlertmanagerChannelSpec configures an Alertmanager receiving open
+// violations as alerts through its v2 API. Alerts are resent while their
+// violation is open and expire, or end on resolution with sendResolved, once
+// it is resolved.
+type AlertmanagerChannelSpec struct {
+	// URL is the base URL of Alertmanager, e.g. http://alertmanager.monitoring:9093.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+	// Labels are added to the labels of every alert, e.g. to route them.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// RemediationURL is a message template rendering the remediation_url
+	// annotation, e.g. https://wiki.example.com/policies/{{ .Report.Spec.ProfileName }}.
+	// +optional
+	RemediationURL string `json:"remediationURL,omitempty"`
+	// RefreshInterval is how often the alerts of open violations are resent,
+	// 1m if unset. Alerts end four intervals after they were last sent.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+	// HeadersSecretRef names a Secret whose keys and values are sent as
+	// request headers, e.g. an Authorization header.
+	// +optional
+	HeadersSecretRef *SecretReference `json:"headersSecretRef,omitempty"`
+	// ClientCertSecretRef names a kubernetes.io/tls Secret authenticating the
+	// manager, like for webhooks.
+	// +optional
+	ClientCertSecretRef *SecretReference `json:"clientCertSecretRef,omitempty"`
+}
+
+// SMTPTLSMode is how the connection to an SMTP server is secured.
+// +kubebuilder:validation:Enum=StartTLS;Implicit
+type SMTPTLSMode string
+
+const (
+	// SMTPStartTLS upgrades the connection with STARTTLS and fails if the
+	// server does not offer it.
+	SMTPStartTLS SMTPTLSMode = "StartTLS"
+	// SMTPImplicitTLS connects over TLS, usually on port 465.
+	SMTPImplicitTLS SMTPTLSMode = "Implicit"
+)
+
+// SMTPSpec configures the SMTP server emails are sent through.
+type SMTPSpec struct {
+	Host string `json:"host"`
+	// +kubebuilder:default=587
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// +kubebuilder:default=StartTLS
+	// +optional
+	TLS SMTPTLSMode `json:"tls,omitempty"`
+	// AuthSecretRef names a Secret whose username and password keys
+	// authenticate the manager with PLAIN authentication.
+	// +optional
+	AuthSecretRef *SecretReference `json:"authSecretRef,omitempty"`
+}
+
+// DefaultRecipientAnnotation is the Namespace annotation listing the owners
+// emailed about the violations in the namespace.
+const DefaultRecipientAnnotation = "gokubedog.io/owner-email"
+
+// EmailChannelSpec configures email notifications. Violation emails carry a
+// plain-text body rendered by the message templates of the channel and an
+// HTML body rendered by HTMLTemplate.
+// +kubebuilder:validation:XValidation:rule="(has(self.to) && size(self.to) > 0) || (has(self.recipientAnnotation) && size(self.recipientAnnotation) > 0)",message="to or recipientAnnotation is required"
+type EmailChannelSpec struct {
+	SMTP SMTPSpec `json:"smtp"`
+	// From is the sender address.
+	From string `json:"from"`
+	// To lists the recipients of every email.
+	// +optional
+	To []string `json:"to,omitempty"`
+	// RecipientAnnotation names the annotation of the Namespace of a violated
+	// resource listing, comma separated, more recipients of its violation
+	// emails and of the digests about its violations.
+	// +kubebuilder:default="gokubedog.io/owner-email"
+	// +optional
+	RecipientAnnotation string `json:"recipientAnnotation,omitempty"`
+	// Subject is a message template rendering the subject of violation emails.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// HTMLTemplate is an html/template rendering the HTML body of violation
+	// emails from the same data as message templates.
+	// +optional
+	HTMLTemplate *TemplateSource `json:"htmlTemplate,omitempty"`
+}
+
+// RetrySpec bounds the retries of a failing delivery. Delays grow
+// exponentially from InitialBackoff up to MaxBackoff; a Retry-After sent by
+// the destination takes precedence.
+type RetrySpec struct {
+	// MaxAttempts is the number of attempts before a delivery is dead-lettered.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=8
+	// +optional
+	MaxAttempts int32 `json:"maxAttempts,omitempty"`
+	// InitialBackoff is the delay after the first failed attempt, 10s if unset.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// MaxBackoff caps the delay between attempts, 10m if unset.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// BatchSpec groups the violations reported to a channel within a window into
+// one digest message.
+type BatchSpec struct {
+	// Window is how long new violations are collected, counting from the
+	// first one, before the digest is sent.
+	Window metav1.Duration `json:"window"`
+}
+
+// DigestSpec schedules a compliance digest summarizing all open violations.
+type DigestSpec struct {
+	// Schedule is a standard cron expression evaluated in UTC, e.g.
+	// "0 9 * * *" daily or "0 9 * * 1" weekly on Mondays.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+}
+
+// TokenBucketSpec is a token bucket rate limit.
+type TokenBucketSpec struct {
+	// PerMinute is the sustained number of messages per minute.
+	// +kubebuilder:validation:Minimum=1
+	PerMinute int32 `json:"perMinute"`
+	// Burst is the number of messages that may be sent at once, PerMinute if
+	// unset.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// RateLimitSpec limits the violation messages sent to a channel. Messages
+// over the limit are suppressed rather than delayed. Batching channels are not
+// rate limited, their digests already bound the number of messages.
+type RateLimitSpec struct {
+	// Channel limits all messages sent to the channel.
+	// +optional
+	Channel *TokenBucketSpec `json:"channel,omitempty"`
+	// Profile limits the messages sent to the channel for each profile.
+	// +optional
+	Profile *TokenBucketSpec `json:"profile,omitempty"`
+}
+
+// FlapSuppressionSpec suppresses the violations of resources that keep
+// opening and resolving.
+type FlapSuppressionSpec struct {
+	// Window is the period over which the violations of a resource are counted.
+	Window metav1.Duration `json:"window"`
+	// Threshold is the number of times the violation of a resource may open
+	// within Window; further openings are suppressed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// +optional
+	Threshold int32 `json:"threshold,omitempty"`
+}
+
+// RouteSpec restricts the violations a channel receives to those of some
+// owners.
+type RouteSpec struct {
+	// Teams lists the teams whose violations the channel receives.
+	// +optional
+	Teams []string `json:"teams,omitempty"`
+	// Unowned also sends the violations of resources without a team.
+	// +optional
+	Unowned bool `json:"unowned,omitempty"`
+}
+
+// Matches reports whether a violation with the given ownership is routed to
+// the channel.
+func (r *RouteSpec) Matches(o *Ownership) bool {
+	if r == nil {
+		return true
+	}
+	if o == nil || o.Team == "" {
+		return r.Unowned
+	}
+	return slices.Contains(r.Teams, o.Team)
+}
+
+// NotificationChannelSpec defines the desired state of NotificationChannel.
+// +kubebuilder:validation:XValidation:rule="self.type != 'slack' || has(self.slack)",message="slack is required for slack channels"
+// +kubebuilder:validation:XValidation:rule="self.type != 'pagerduty' || has(self.pagerduty)",message="pagerduty is required for pagerduty channels"
+// +kubebuilder:validation:XValidation:rule="self.type != 'webhook' || has(self.webhook)",message="webhook is required for webhook channels"
+// +kubebuilder:validation:XValidation:rule="self.type != 'alertmanager' || has(self.alertmanager)",message="alertmanager is required for alertmanager channels"
+// +kubebuilder:validation:XValidation:rule="self.type != 'email' || has(self.email)",message="email is required for email channels"
+// +kubebuilder:validation:XValidation:rule="self.type != 'alertmanager' || !(has(self.batch) || has(self.digest))",message="alertmanager channels cannot batch or send digests"
+type NotificationChannelSpec struct {
+	Type ChannelType `json:"type"`
+	// +optional
+	Slack *SlackChannelSpec `json:"slack,omitempty"`
+	// +optional
+	PagerDuty *PagerDutyChannelSpec `json:"pagerduty,omitempty"`
+	// +optional
+	Webhook *WebhookChannelSpec `json:"webhook,omitempty"`
+	// +optional
+	Alertmanager *AlertmanagerChannelSpec `json:"alertmanager,omitempty"`
+	// +optional
+	Email *EmailChannelSpec `json:"email,omitempty"`
+	// Timeout bounds a single delivery attempt, 10s if unset.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +optional
+	Retry *RetrySpec `json:"retry,omitempty"`
+	// +optional
+	Template *MessageTemplateSpec `json:"template,omitempty"`
+	// Batch sends new violations as digests instead of one message each.
+	// +optional
+	Batch *BatchSpec `json:"batch,omitempty"`
+	// Digest additionally sends a scheduled compliance digest.
+	// +optional
+	Digest *DigestSpec `json:"digest,omitempty"`
+	// +optional
+	RateLimit *RateLimitSpec `json:"rateLimit,omitempty"`
+	// +optional
+	FlapSuppression *FlapSuppressionSpec `json:"flapSuppression,omitempty"`
+	// Route restricts the channel to the violations of some teams, see
+	// PolicyViolationReportSpec.Ownership. Scheduled digests only summarize
+	// the routed violations.
+	// +optional
+	Route *RouteSpec `json:"route,omitempty"`
+	// SendResolved sends a resolution once a report notified to the channel
+	// is resolved: a reply threaded under the original Slack message, a
+	// resolve event closing the PagerDuty incident, or a resolved CloudEvent.
+	// Channels that batch violations never send resolutions.
+	// +optional
+	SendResolved bool `json:"sendResolved,omitempty"`
+}
+
+// NotificationChannelStatus defines the observed state of NotificationChannel.
+type NotificationChannelStatus struct {
+	// LastDigestAt is when the last scheduled compliance digest was sent.
+	// +optional
+	LastDigestAt *metav1.Time `json:"lastDigestAt,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Last Digest",type=date,JSONPath=`.status.lastDigestAt`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NotificationChannel is the Schema for the notificationchannels API. Every
+// channel receives the violations of every profile, unless it sets a route.
+type NotificationChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationChannelSpec   `json:"spec,omitempty"`
+	Status NotificationChannelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationChannelList contains a list of NotificationChannel.
+type NotificationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationChannel `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationChannel{}, &NotificationChannelList{})
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ExceptionResourceSpec selects the resources an exception applies to.
+type ExceptionResourceSpec struct {
+	Kind string `json:"kind"`
+	// Name of the resource, "*" matches every resource of the kind.
+	Name string `json:"name"`
+}
+
+// PolicyExceptionSpec defines the desired state of PolicyException.
+type PolicyExceptionSpec struct {
+	// ProfileName is the PolicyProfile the exception applies to.
+	ProfileName string `json:"profileName"`
+	// ProfileNamespace is the namespace of the PolicyProfile, the namespace of
+	// the exception when unset.
+	// +optional
+	ProfileNamespace string `json:"profileNamespace,omitempty"`
+	// Resource selects the exempted resources in the namespace of the exception.
+	Resource ExceptionResourceSpec `json:"resource"`
+	// Reason explains why the drift is accepted.
+	Reason string `json:"reason"`
+	// ExpiresAt ends the exception, it never expires when unset.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// CreatedBy records who requested the exception.
+	// +optional
+	CreatedBy string `json:"createdBy,omitempty"`
+}
+
+// PolicyExceptionStatus defines the observed state of PolicyException.
+type PolicyExceptionStatus struct {
+}
+
+// Profile returns the namespaced name of the PolicyProfile the exception applies to.
+func (e *PolicyException) Profile() types.NamespacedName {
+	namespace := e.Spec.ProfileNamespace
+	if namespace == "" {
+		namespace = e.Namespace
+	}
+	return types.NamespacedName{Namespace: namespace, Name: e.Spec.ProfileName}
+}
+
+// Covers reports whether the exception exempts the resource from the profile at the given time.
+func (e *PolicyException) Covers(profile types.NamespacedName, kind, name string, now metav1.Time) bool {
+	if e.Spec.ExpiresAt != nil && !now.Before(e.Spec.ExpiresAt) {
+		return false
+	}
+	if e.Profile() != profile || e.Spec.Resource.Kind != kind {
+		return false
+	}
+	return e.Spec.Resource.Name == "*" || e.Spec.Resource.Name == name
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Profile",type=string,JSONPath=`.spec.profileName`
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.resource.kind`
+// +kubebuilder:printcolumn:name="Resource",type=string,JSONPath=`.spec.resource.name`
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.spec.expiresAt`
+
+// PolicyException is the Schema for the policyexceptions API.
+type PolicyException struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PolicyExceptionSpec   `json:"spec,omitempty"`
+	Status PolicyExceptionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PolicyExceptionList contains a list of PolicyException.
+type PolicyExceptionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyException `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyException{}, &PolicyExceptionList{})
+}
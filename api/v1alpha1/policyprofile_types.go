@@ -17,31 +17,260 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+// ReportCleanupFinalizer is added to a PolicyProfile once it has produced a
+// report outside its own namespace, where owner references cannot be used.
+// The finalizer deletes those reports before the profile goes away.
+const ReportCleanupFinalizer = "watchdog.bizaikube.io/report-cleanup"
+
+// EvaluateNowAnnotation requests an immediate evaluation of a PolicyProfile,
+// whatever its schedule. The controller removes it once the evaluation starts.
+const EvaluateNowAnnotation = "watchdog.bizaikube.io/evaluate-now"
+
+const (
+	// PackLabel is set on the profiles and templates installed from a
+	// built-in policy pack to the name of the pack.
+	PackLabel = "watchdog.bizaikube.io/pack"
+	// PackVersionLabel is set next to PackLabel to the version of the pack.
+	PackVersionLabel = "watchdog.bizaikube.io/pack-version"
+)
+
+// Check is a built-in check inspecting the whole resource rather than its
+// labels. Pod checks apply to Pods and to the pod templates of workloads.
+// +kubebuilder:validation:Enum=PodSecurityBaseline;PodSecurityRestricted;ImageTagNotLatest;ResourceLimits;NoHostPath;DefaultDenyIngress
+type Check string
+
+const (
+	// CheckPodSecurityBaseline enforces the Pod Security Standards baseline
+	// profile: no privileged containers, host namespaces, host ports,
+	// hostPath volumes or capabilities beyond the default set.
+	CheckPodSecurityBaseline Check = "PodSecurityBaseline"
+	// CheckPodSecurityRestricted enforces the Pod Security Standards
+	// restricted profile on top of the baseline one: non-root containers
+	// without privilege escalation, dropping all capabilities, under the
+	// runtime default seccomp profile.
+	CheckPodSecurityRestricted Check = "PodSecurityRestricted"
+	// CheckImageTagNotLatest rejects container images without a tag or
+	// digest, or tagged latest.
+	CheckImageTagNotLatest Check = "ImageTagNotLatest"
+	// CheckResourceLimits requires CPU and memory limits on every container.
+	CheckResourceLimits Check = "ResourceLimits"
+	// CheckNoHostPath rejects hostPath volumes.
+	CheckNoHostPath Check = "NoHostPath"
+	// CheckDefaultDenyIngress applies to Namespaces and requires a
+	// NetworkPolicy denying ingress to all their pods by default.
+	CheckDefaultDenyIngress Check = "DefaultDenyIngress"
+)
+
+// Severity ranks how serious a violation of a profile is.
+// +kubebuilder:validation:Enum=low;medium;high;critical
+type Severity string
+
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
 // MatchSpec defines the match criteria for a policy profile.
 type MatchSpec struct {
 	Kind      string `json:"kind"`
 	Namespace string `json:"namespace"`
 }
 
+// RetentionSpec controls how long the reports of a profile are kept.
+// Unset fields fall back to the manager-wide defaults, except for the cap of
+// the profile, which applies on top of the manager-wide cap of all reports in
+// a namespace.
+type RetentionSpec struct {
+	// ResolvedReportTTL is how long a Resolved report is kept before it is deleted.
+	// +optional
+	ResolvedReportTTL *metav1.Duration `json:"resolvedReportTTL,omitempty"`
+	// MaxReportsPerNamespace caps the number of reports the profile keeps in a
+	// single namespace, on top of the manager-wide cap of the reports of all
+	// profiles there. The oldest Resolved reports are deleted first; Open
+	// reports are never deleted to satisfy the cap.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxReportsPerNamespace *int32 `json:"maxReportsPerNamespace,omitempty"`
+}
+
+// ScheduleMode selects what triggers the evaluation of a profile.
+// +kubebuilder:validation:Enum=Continuous;Interval;Cron
+type ScheduleMode string
+
+const (
+	// ScheduleContinuous evaluates the profile whenever a matched resource changes.
+	ScheduleContinuous ScheduleMode = "Continuous"
+	// ScheduleInterval evaluates the profile at a fixed interval.
+	ScheduleInterval ScheduleMode = "Interval"
+	// ScheduleCron evaluates the profile on a cron schedule.
+	ScheduleCron ScheduleMode = "Cron"
+)
+
+// DefaultScheduleInterval is used by Interval profiles without an interval
+// and by profiles without a schedule.
+const DefaultScheduleInterval = time.Hour
+
+// ScheduleSpec controls when a profile is evaluated. Profiles are also
+// evaluated whenever their spec or one of their exceptions changes.
+// +kubebuilder:validation:XValidation:rule="self.mode != 'Cron' || has(self.cron)",message="cron is required in Cron mode"
+type ScheduleSpec struct {
+	// +kubebuilder:default=Interval
+	// +optional
+	Mode ScheduleMode `json:"mode,omitempty"`
+	// Interval between two evaluations in Interval mode, one hour if unset.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Cron is a five field cron expression used in Cron mode, evaluated in UTC.
+	// Descriptors such as @daily are accepted.
+	// +kubebuilder:validation:Pattern=`^(@(yearly|annually|monthly|weekly|daily|midnight|hourly)|@every ([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+|[0-9A-Za-z*,/?-]+( +[0-9A-Za-z*,/?-]+){4})$`
+	// +optional
+	Cron string `json:"cron,omitempty"`
+}
+
+// ProfileMode selects what a profile does with the violations it finds.
+// +kubebuilder:validation:Enum=Enforce;Preview
+type ProfileMode string
+
+const (
+	// ProfileModeEnforce reports every violation.
+	ProfileModeEnforce ProfileMode = "Enforce"
+	// ProfileModePreview only summarizes the violations the profile would
+	// report in its status, so that its blast radius is known before it is
+	// enforced. No report is created, refreshed or resolved.
+	ProfileModePreview ProfileMode = "Preview"
+)
+
+// MaxPreviewSamples caps the sample resources kept in a preview summary.
+const MaxPreviewSamples = 10
+
+// TemplateReference points a profile to a PolicyTemplate.
+type TemplateReference struct {
+	// Name of the PolicyTemplate.
+	Name string `json:"name"`
+	// Parameters are the values of the parameters of the template, strings
+	// or lists of strings depending on their type.
+	// +optional
+	Parameters map[string]apiextensionsv1.JSON `json:"parameters,omitempty"`
+}
+
 // PolicyProfileSpec defines the desired state of PolicyProfile.
 type PolicyProfileSpec struct {
 	Match  MatchSpec         `json:"match"`
 	Policy map[string]string `json:"policy,omitempty"`
+	// RequiredLabels lists labels the resources must carry, with any value.
+	// +optional
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// Checks are built-in checks run on the whole resource. Their findings
+	// are reported as drift under the name of the check.
+	// +optional
+	Checks []Check `json:"checks,omitempty"`
+	// Remediation tells how to fix a violation. It is copied onto the
+	// reports.
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+	// Template adds the label rules of a PolicyTemplate to the policy. The
+	// policy of the profile wins where both set a label.
+	// +optional
+	Template *TemplateReference `json:"template,omitempty"`
+	// Mode is Enforce to report violations, or Preview to only summarize
+	// them in status.preview.
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode ProfileMode `json:"mode,omitempty"`
+	// Severity is copied onto every report of the profile.
+	// +kubebuilder:default=medium
+	// +optional
+	Severity Severity `json:"severity,omitempty"`
+	// Retention overrides the manager-wide report retention policy for this profile.
+	// +optional
+	Retention *RetentionSpec `json:"retention,omitempty"`
+	// Schedule controls when the profile is evaluated, hourly if unset.
+	// +optional
+	Schedule *ScheduleSpec `json:"schedule,omitempty"`
+}
+
+// NamespaceSummary counts the resources a profile evaluated in one namespace.
+type NamespaceSummary struct {
+	Namespace string `json:"namespace"`
+	// Matched is the number of resources the profile evaluated.
+	Matched int32 `json:"matched"`
+	// Violating is the number of matched resources that drift from the policy.
+	Violating int32 `json:"violating"`
+}
+
+// PreviewSample is a resource a previewed profile would report.
+type PreviewSample struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Drift     map[string]string `json:"drift"`
+}
+
+// PreviewSummary is what a profile in Preview mode would report.
+type PreviewSummary struct {
+	// Matched is the number of resources the profile evaluated.
+	Matched int32 `json:"matched"`
+	// WouldViolate is the number of resources the profile would report.
+	WouldViolate int32 `json:"wouldViolate"`
+	// Excepted is the number of drifting resources covered by a
+	// PolicyException, which would not be reported.
+	Excepted int32 `json:"excepted"`
+	// Samples lists the first resources the profile would report.
+	// +optional
+	Samples []PreviewSample `json:"samples,omitempty"`
 }
 
 // PolicyProfileStatus defines the observed state of PolicyProfile.
 type PolicyProfileStatus struct {
 	LastChecked metav1.Time `json:"lastChecked,omitempty"`
+	// Namespaces summarizes the last evaluation per namespace.
+	// +optional
+	Namespaces []NamespaceSummary `json:"namespaces,omitempty"`
+	// NextRun is when the next scheduled evaluation is due. It is unset for
+	// Continuous profiles.
+	// +optional
+	NextRun *metav1.Time `json:"nextRun,omitempty"`
+	// Preview summarizes the last evaluation in Preview mode. It is unset in
+	// Enforce mode.
+	// +optional
+	Preview *PreviewSummary `json:"preview,omitempty"`
+	// Conditions report whether the template of the profile resolves and
+	// whether its cron schedule is valid.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// ConditionTemplateResolved is the condition type reporting whether the
+// template of a profile exists and accepts its parameters. Profiles whose
+// template does not resolve are not evaluated.
+const ConditionTemplateResolved = "TemplateResolved"
+
+// ConditionScheduleValid is the condition type reporting whether the cron
+// schedule of a profile parses. Profiles with an invalid schedule are
+// evaluated at the default interval instead.
+const ConditionScheduleValid = "ScheduleValid"
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.match.kind`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Would Violate",type=integer,JSONPath=`.status.preview.wouldViolate`,priority=1
+// +kubebuilder:printcolumn:name="Last Checked",type=date,JSONPath=`.status.lastChecked`
+// +kubebuilder:printcolumn:name="Next Run",type=date,JSONPath=`.status.nextRun`
 
 // PolicyProfile is the Schema for the policyprofiles API.
 type PolicyProfile struct {
@@ -61,6 +290,56 @@ type PolicyProfileList struct {
 	Items           []PolicyProfile `json:"items"`
 }
 
+// Previewing reports whether the profile only previews its violations.
+func (p *PolicyProfile) Previewing() bool {
+	return p.Spec.Mode == ProfileModePreview
+}
+
+// Record counts a drifting resource the profile would report, keeping it as
+// a sample while there is room.
+func (s *PreviewSummary) Record(namespace, name string, drift map[string]string) {
+	s.WouldViolate++
+	if len(s.Samples) < MaxPreviewSamples {
+		s.Samples = append(s.Samples, PreviewSample{Namespace: namespace, Name: name, Drift: drift})
+	}
+}
+
+// MatchesNamespace reports whether the profile evaluates the resources of
+// namespace. A trailing * in the match namespace matches any suffix.
+func (p *PolicyProfile) MatchesNamespace(namespace string) bool {
+	pattern := p.Spec.Match.Namespace
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(namespace, prefix)
+	}
+	return namespace == pattern
+}
+
+// AnyValue stands for the expected value of required labels in drifts.
+const AnyValue = "any value"
+
+// Drift compares the labels of a resource with the policy and the required
+// labels, and describes each key whose value differs, nil if none does.
+func (p *PolicyProfile) Drift(labels map[string]string) map[string]string {
+	var drift map[string]string
+	add := func(k, expected, actual string) {
+		if drift == nil {
+			drift = map[string]string{}
+		}
+		drift[k] = fmt.Sprintf("Expected: %s, Got: %s", expected, actual)
+	}
+	for k, v := range p.Spec.Policy {
+		if actual, ok := labels[k]; !ok || actual != v {
+			add(k, v, actual)
+		}
+	}
+	for _, k := range p.Spec.RequiredLabels {
+		if _, ok := labels[k]; !ok {
+			add(k, AnyValue, "")
+		}
+	}
+	return drift
+}
+
 func init() {
 	SchemeBuilder.Register(&PolicyProfile{}, &PolicyProfileList{})
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParameterType is the type of a template parameter.
+// +kubebuilder:validation:Enum=String;StringList
+type ParameterType string
+
+const (
+	// ParameterString is a single string.
+	ParameterString ParameterType = "String"
+	// ParameterStringList is a list of strings.
+	ParameterStringList ParameterType = "StringList"
+)
+
+// TemplateParameter declares a parameter of a PolicyTemplate. The values
+// profiles pass are checked against it.
+type TemplateParameter struct {
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+	// +kubebuilder:default=String
+	// +optional
+	Type ParameterType `json:"type,omitempty"`
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Required parameters must be passed by every profile of the template.
+	// +optional
+	Required bool `json:"required,omitempty"`
+	// Default is used when a profile does not pass the parameter.
+	// +optional
+	Default *apiextensionsv1.JSON `json:"default,omitempty"`
+	// Enum restricts the value of a String parameter, or the items of a
+	// StringList one.
+	// +optional
+	Enum []string `json:"enum,omitempty"`
+	// Pattern is a regular expression the value of a String parameter, or
+	// every item of a StringList one, must match.
+	// +optional
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// LabelRule requires a label on the matched resources. Rules referring to an
+// optional parameter that is not passed are skipped.
+// +kubebuilder:validation:XValidation:rule="has(self.key) != has(self.keysFrom)",message="exactly one of key and keysFrom is required"
+// +kubebuilder:validation:XValidation:rule="!(has(self.value) && has(self.valueFrom))",message="value and valueFrom are mutually exclusive"
+type LabelRule struct {
+	// Key is the label key.
+	// +optional
+	Key string `json:"key,omitempty"`
+	// KeysFrom names a StringList parameter, the rule applies to each of its
+	// items as a label key.
+	// +optional
+	KeysFrom string `json:"keysFrom,omitempty"`
+	// Value is the value the label must have. Without Value nor ValueFrom
+	// the label only has to be present.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// ValueFrom names a String parameter holding the value the label must
+	// have.
+	// +optional
+	ValueFrom string `json:"valueFrom,omitempty"`
+}
+
+// PolicyTemplateSpec defines the desired state of PolicyTemplate.
+// +kubebuilder:validation:XValidation:rule="has(self.labels) || has(self.checks)",message="labels or checks are required"
+type PolicyTemplateSpec struct {
+	// Description tells what the template enforces.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Parameters declares the parameters profiles pass to the template.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+	// Labels are the label rules the profiles of the template enforce.
+	// +optional
+	Labels []LabelRule `json:"labels,omitempty"`
+	// Checks are built-in checks added to the checks of the profiles.
+	// +optional
+	Checks []Check `json:"checks,omitempty"`
+	// Remediation is used by the profiles without a remediation of their own.
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Description",type=string,JSONPath=`.spec.description`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PolicyTemplate is the Schema for the policytemplates API. It is a reusable,
+// parameterized policy that PolicyProfiles of any namespace refer to.
+type PolicyTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolicyTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PolicyTemplateList contains a list of PolicyTemplate.
+type PolicyTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PolicyTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PolicyTemplate{}, &PolicyTemplateList{})
+}
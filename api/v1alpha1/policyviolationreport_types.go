@@ -17,12 +17,24 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
+const (
+	// ProfileNameLabel is set on every report to the name of the PolicyProfile that produced it.
+	ProfileNameLabel = "watchdog.bizaikube.io/profile-name"
+	// ProfileNamespaceLabel is set on every report to the namespace of the PolicyProfile that produced it.
+	ProfileNamespaceLabel = "watchdog.bizaikube.io/profile-namespace"
+	// TeamLabel is set on reports to the team owning the violated resource,
+	// when it is a valid label value.
+	TeamLabel = "watchdog.bizaikube.io/team"
+)
+
 type ViolatedResourceSpec struct {
 	Kind      string `json:"kind"`
 	Name      string `json:"name"`
@@ -33,18 +45,212 @@ type ViolatedResourceSpec struct {
 type PolicyViolationReportSpec struct {
 	ViolatedResource ViolatedResourceSpec `json:"violatedResource"`
 	ProfileName      string               `json:"profileName"`
-	Drift            map[string]string    `json:"drift"`
+	// ProfileNamespace is the namespace of the PolicyProfile. Reports living in
+	// the same namespace as their profile are also owned by it.
+	ProfileNamespace string            `json:"profileNamespace,omitempty"`
+	Drift            map[string]string `json:"drift"`
+	// Severity is inherited from the profile.
+	// +optional
+	Severity Severity `json:"severity,omitempty"`
+	// Remediation tells how to fix the violation, copied from the profile.
+	// +optional
+	Remediation string `json:"remediation,omitempty"`
+	// Acknowledgement records that someone took note of the violation.
+	// +optional
+	Acknowledgement *Acknowledgement `json:"acknowledgement,omitempty"`
+	// Ownership is who owns the violated resource, resolved from the
+	// metadata of the resource, of its owners and of its namespace.
+	// +optional
+	Ownership *Ownership `json:"ownership,omitempty"`
+}
+
+// Ownership identifies the owner of a resource.
+type Ownership struct {
+	// Team is the team owning the resource.
+	// +optional
+	Team string `json:"team,omitempty"`
+	// Contact is how to reach the owner, e.g. an email address or a chat
+	// channel.
+	// +optional
+	Contact string `json:"contact,omitempty"`
+	// OnCall identifies the on-call rotation of the owner, e.g. a PagerDuty
+	// service.
+	// +optional
+	OnCall string `json:"onCall,omitempty"`
+}
+
+// Acknowledgement records who acknowledged a violation and when. An
+// acknowledged violation is not notified again until it is resolved, or
+// until SnoozeUntil if set.
+type Acknowledgement struct {
+	// By identifies who acknowledged the violation.
+	By string      `json:"by"`
+	At metav1.Time `json:"at"`
+	// Reason explains the acknowledgement, e.g. "fixing next sprint".
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// SnoozeUntil silences the violation until then, even if it is resolved
+	// and reopened in the meantime. Notifications resume once it passes.
+	// +optional
+	SnoozeUntil *metav1.Time `json:"snoozeUntil,omitempty"`
+}
+
+// Active reports whether the acknowledgement silences notifications at now.
+func (a *Acknowledgement) Active(now time.Time) bool {
+	return a != nil && (a.SnoozeUntil == nil || now.Before(a.SnoozeUntil.Time))
+}
+
+// ReportPhase describes where a PolicyViolationReport is in its lifecycle.
+// +kubebuilder:validation:Enum=Open;Resolved
+type ReportPhase string
+
+const (
+	// ReportPhaseOpen means the violated resource still drifts from its profile.
+	ReportPhaseOpen ReportPhase = "Open"
+	// ReportPhaseResolved means the drift is gone or the resource no longer exists.
+	ReportPhaseResolved ReportPhase = "Resolved"
+)
+
+// DeliveryState is the state of the notification of a report to one channel.
+// +kubebuilder:validation:Enum=Batched;Pending;Sending;Sent;DeadLetter;Suppressed
+type DeliveryState string
+
+const (
+	// DeliveryBatched means the report waits in the batch of a batching
+	// channel, which is sent as one digest at NextAttemptAt.
+	DeliveryBatched DeliveryState = "Batched"
+	// DeliveryPending means the notification has not been delivered yet and
+	// will be attempted again at NextAttemptAt.
+	DeliveryPending DeliveryState = "Pending"
+	// DeliverySending means an attempt was started at LastAttemptAt. A
+	// delivery left in this state, e.g. by a restart, is resent with the same
+	// idempotency key once the attempt has timed out.
+	DeliverySending DeliveryState = "Sending"
+	// DeliverySent means the destination accepted the notification.
+	DeliverySent DeliveryState = "Sent"
+	// DeliveryDeadLetter means the delivery failed permanently or ran out of
+	// attempts and is not retried anymore.
+	DeliveryDeadLetter DeliveryState = "DeadLetter"
+	// DeliverySuppressed means the notification was dropped by the rate limits
+	// or flap suppression of the channel.
+	DeliverySuppressed DeliveryState = "Suppressed"
+)
+
+// DeliveryStatus tracks the notification of a report to one channel.
+type DeliveryStatus struct {
+	// Channel is the name of the NotificationChannel.
+	Channel string        `json:"channel"`
+	State   DeliveryState `json:"state"`
+	// Attempts counts the delivery attempts made so far.
+	Attempts int32 `json:"attempts"`
+	// LastError is the error of the last failed attempt.
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// Reason explains why a delivery was suppressed.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	LastAttemptAt *metav1.Time `json:"lastAttemptAt,omitempty"`
+	// SentAt is when the destination accepted the notification.
+	// +optional
+	SentAt *metav1.Time `json:"sentAt,omitempty"`
+	// MessageID identifies the notification at the destination, for
+	// destinations that return one.
+	// +optional
+	MessageID string `json:"messageID,omitempty"`
+	// NextAttemptAt is when a Pending delivery is retried.
+	// +optional
+	NextAttemptAt *metav1.Time `json:"nextAttemptAt,omitempty"`
+}
+
+// PolicyViolationReportStatus defines the observed state of PolicyViolationReport.
+type PolicyViolationReportStatus struct {
+	// Phase is Open while the drift persists and Resolved once it is gone.
+	// An empty phase is treated as Open.
+	Phase ReportPhase `json:"phase,omitempty"`
+	// OpenedAt is the time the report was last opened or reopened.
+	// +optional
+	OpenedAt *metav1.Time `json:"openedAt,omitempty"`
+	// ResolvedAt is the time the report moved to Resolved.
+	ResolvedAt *metav1.Time `json:"resolvedAt,omitempty"`
+	// Deliveries records the notification of the report per channel since it
+	// was last opened.
+	// +optional
+	// +listType=map
+	// +listMapKey=channel
+	Deliveries []DeliveryStatus `json:"deliveries,omitempty"`
+	// Resolutions records the notification of the resolution of the report
+	// per channel, for channels sending resolutions.
+	// +optional
+	// +listType=map
+	// +listMapKey=channel
+	Resolutions []DeliveryStatus `json:"resolutions,omitempty"`
+}
+
+// Terminal reports whether the delivery is settled and no longer attempted.
+func (d *DeliveryStatus) Terminal() bool {
+	switch d.State {
+	case DeliverySent, DeliveryDeadLetter, DeliverySuppressed:
+		return true
+	}
+	return false
+}
+
+// Delivery returns the delivery status of the named channel, nil if the
+// channel has not been attempted yet.
+func (s *PolicyViolationReportStatus) Delivery(channel string) *DeliveryStatus {
+	for i := range s.Deliveries {
+		if s.Deliveries[i].Channel == channel {
+			return &s.Deliveries[i]
+		}
+	}
+	return nil
+}
+
+// Resolution returns the status of the resolution notification of the named
+// channel, nil if none has been attempted yet.
+func (s *PolicyViolationReportStatus) Resolution(channel string) *DeliveryStatus {
+	for i := range s.Resolutions {
+		if s.Resolutions[i].Channel == channel {
+			return &s.Resolutions[i]
+		}
+	}
+	return nil
+}
+
+// Opened returns the time the report was last opened, its creation time for
+// reports opened before OpenedAt was recorded.
+func (r *PolicyViolationReport) Opened() time.Time {
+	if r.Status.OpenedAt != nil {
+		return r.Status.OpenedAt.Time
+	}
+	return r.CreationTimestamp.Time
+}
+
+// IsResolved reports whether the violation has been resolved.
+func (s *PolicyViolationReportStatus) IsResolved() bool {
+	return s.Phase == ReportPhaseResolved
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Profile",type=string,JSONPath=`.spec.profileName`
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.violatedResource.kind`
+// +kubebuilder:printcolumn:name="Resource",type=string,JSONPath=`.spec.violatedResource.name`
+// +kubebuilder:printcolumn:name="Severity",type=string,JSONPath=`.spec.severity`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Team",type=string,JSONPath=`.spec.ownership.team`,priority=1
+// +kubebuilder:printcolumn:name="Acked By",type=string,JSONPath=`.spec.acknowledgement.by`,priority=1
+// +kubebuilder:printcolumn:name="Snoozed Until",type=date,JSONPath=`.spec.acknowledgement.snoozeUntil`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // PolicyViolationReport is the Schema for the policyviolationreports API.
 type PolicyViolationReport struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec PolicyViolationReportSpec `json:"spec,omitempty"`
+	Spec   PolicyViolationReportSpec   `json:"spec,omitempty"`
+	Status PolicyViolationReportStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -19,8 +19,12 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -40,6 +44,15 @@ import (
 	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
 	"github.com/madmmas/gokubedog/internal/controller"
 	watchdogcontroller "github.com/madmmas/gokubedog/internal/controller/watchdog"
+	"github.com/madmmas/gokubedog/internal/dashboard"
+	"github.com/madmmas/gokubedog/internal/history"
+	"github.com/madmmas/gokubedog/internal/httpapi"
+	"github.com/madmmas/gokubedog/internal/notify"
+	"github.com/madmmas/gokubedog/internal/ownership"
+	"github.com/madmmas/gokubedog/internal/packs"
+	"github.com/madmmas/gokubedog/internal/retention"
+	"github.com/madmmas/gokubedog/internal/slackapp"
+	"github.com/madmmas/gokubedog/internal/target"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -64,6 +77,17 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var resolvedReportTTL, retentionInterval time.Duration
+	var maxReportsPerNamespace int
+	var historySinkURL string
+	var apiAddr string
+	var enableDashboard bool
+	var enableSlackActions, allowRemediation bool
+	var clusterName string
+	var ownerTeamKeys, ownerContactKeys, ownerOnCallKeys string
+	var targetPageSize int64
+	var profileWorkers, objectWorkers int
+	var policyPacks, policyPackNamespace string
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -82,6 +106,48 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&resolvedReportTTL, "resolved-report-ttl", 0,
+		"How long Resolved PolicyViolationReports are kept before they are deleted. "+
+			"0, the default, keeps them forever. Profiles can override this in spec.retention.")
+	flag.IntVar(&maxReportsPerNamespace, "max-reports-per-namespace", 0,
+		"The maximum number of PolicyViolationReports kept in one namespace, whatever the profile. "+
+			"The oldest Resolved reports are deleted first. Use 0 for no limit. "+
+			"Profiles can cap their own reports per namespace in spec.retention.")
+	flag.DurationVar(&retentionInterval, "retention-interval", 10*time.Minute,
+		"How often the report retention policy is enforced.")
+	flag.StringVar(&historySinkURL, "history-sink", "",
+		"Where report state transitions are archived, e.g. file:///data/history.jsonl, "+
+			"sqlite:///data/history.db or s3://bucket/prefix?endpoint=minio:9000. Leave empty to disable.")
+	flag.StringVar(&apiAddr, "api-bind-address", "0", "The address the read-only compliance API binds to. "+
+		"It is protected like the metrics endpoint and uses the same TLS settings. Leave as 0 to disable it.")
+	flag.Int64Var(&targetPageSize, "target-page-size", target.DefaultPageSize,
+		"The number of objects fetched per list call when a profile enumerates its target resources.")
+	flag.IntVar(&profileWorkers, "profile-workers", 1,
+		"The number of PolicyProfiles evaluated concurrently.")
+	flag.IntVar(&objectWorkers, "object-workers", 4,
+		"The number of changed objects re-evaluated concurrently against continuous profiles.")
+	flag.BoolVar(&enableDashboard, "enable-dashboard", false,
+		"If set, the web dashboard is served under /dashboard/ on the compliance API address.")
+	flag.BoolVar(&enableSlackActions, "enable-slack-actions", false,
+		"If set, the Slack interactivity endpoint is served under "+slackapp.Path+" on the compliance API address. "+
+			"Requests are verified with the signing secret read from SLACK_SIGNING_SECRET.")
+	flag.BoolVar(&allowRemediation, "slack-allow-remediation", false,
+		"If set, the Auto-remediate button of Slack messages resets the drifted labels of the violating resource.")
+	flag.StringVar(&clusterName, "cluster-name", "",
+		"The name of the cluster, available to notification message templates as .Cluster.")
+	ownerKeys := ownership.DefaultKeys()
+	flag.StringVar(&ownerTeamKeys, "owner-team-keys", strings.Join(ownerKeys.Team, ","),
+		"Comma-separated annotation or label keys naming the team owning a violated resource, looked up on "+
+			"the resource, its controllers and its namespace. Leave empty to not resolve teams.")
+	flag.StringVar(&ownerContactKeys, "owner-contact-keys", strings.Join(ownerKeys.Contact, ","),
+		"Comma-separated annotation or label keys holding the contact of the owner of a violated resource.")
+	flag.StringVar(&ownerOnCallKeys, "owner-oncall-keys", strings.Join(ownerKeys.OnCall, ","),
+		"Comma-separated annotation or label keys naming the on-call rotation of the owner of a violated resource.")
+	flag.StringVar(&policyPacks, "policy-packs", "",
+		"Comma-separated built-in policy packs to install, among "+packNames()+". "+
+			"Installed packs are upgraded when the manager ships a newer version of them.")
+	flag.StringVar(&policyPackNamespace, "policy-pack-namespace", "gokubedog-system",
+		"The namespace the PolicyProfiles of the policy packs are installed in.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -203,22 +269,160 @@ func main() {
 		os.Exit(1)
 	}
 
+	var historySink history.Sink
+	if historySinkURL != "" {
+		historySink, err = history.Open(historySinkURL)
+		if err != nil {
+			setupLog.Error(err, "unable to open history sink")
+			os.Exit(1)
+		}
+		defer func() {
+			if err := historySink.Close(); err != nil {
+				setupLog.Error(err, "unable to close history sink")
+			}
+		}()
+	}
+
+	targets, err := target.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create target lister")
+		os.Exit(1)
+	}
+	targets.PageSize = targetPageSize
 	if err := (&controller.PolicyProfileReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:  mgr.GetClient(),
+		Scheme:  mgr.GetScheme(),
+		History: historySink,
+		Targets: targets,
+		Ownership: &ownership.Resolver{
+			Reader: mgr.GetClient(),
+			Keys: ownership.Keys{
+				Team:    splitKeys(ownerTeamKeys),
+				Contact: splitKeys(ownerContactKeys),
+				OnCall:  splitKeys(ownerOnCallKeys),
+			},
+		},
+
+		MaxConcurrentReconciles: profileWorkers,
+		ObjectWorkers:           objectWorkers,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PolicyProfile")
 		os.Exit(1)
 	}
+	// The notification controller and runnables share the built channels.
+	channels := notify.NewChannelCache()
 	if err := (&watchdogcontroller.PolicyViolationReportReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		APIReader:   mgr.GetAPIReader(),
+		ClusterName: clusterName,
+		Channels:    channels,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PolicyViolationReport")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	if enableDashboard && apiAddr == "0" {
+		setupLog.Info("--enable-dashboard has no effect without --api-bind-address")
+	}
+	if enableSlackActions && apiAddr == "0" {
+		setupLog.Info("--enable-slack-actions has no effect without --api-bind-address")
+	}
+	if apiAddr != "0" {
+		handler := &httpapi.Handler{Reader: mgr.GetClient()}
+		if reader, ok := historySink.(history.Reader); ok {
+			handler.History = reader
+		}
+		apiOptions := httpapi.Options{
+			BindAddress:    apiAddr,
+			SecureServing:  secureMetrics,
+			TLSOpts:        metricsServerOptions.TLSOpts,
+			FilterProvider: metricsServerOptions.FilterProvider,
+		}
+		if enableDashboard {
+			ui := &dashboard.Handler{
+				Client:   mgr.GetClient(),
+				Reviewer: &dashboard.APIReviewer{Client: mgr.GetClient()},
+			}
+			apiOptions.ExtraHandlers = ui.Routes()
+		}
+		if enableSlackActions {
+			secret := os.Getenv("SLACK_SIGNING_SECRET")
+			if secret == "" {
+				setupLog.Error(nil, "--enable-slack-actions requires SLACK_SIGNING_SECRET")
+				os.Exit(1)
+			}
+			actions := &slackapp.Handler{Client: mgr.GetClient(), SigningSecret: []byte(secret)}
+			if allowRemediation {
+				actions.Remediator = &slackapp.LabelRemediator{Targets: targets}
+			}
+			if apiOptions.ExtraHandlers == nil {
+				apiOptions.ExtraHandlers = map[string]http.Handler{}
+			}
+			maps.Copy(apiOptions.ExtraHandlers, actions.Routes())
+		}
+		apiServer, err := httpapi.NewServer(handler, apiOptions, mgr.GetConfig(), mgr.GetHTTPClient())
+		if err != nil {
+			setupLog.Error(err, "unable to create compliance API server")
+			os.Exit(1)
+		}
+		if err := mgr.Add(apiServer); err != nil {
+			setupLog.Error(err, "unable to add compliance API server to manager")
+			os.Exit(1)
+		}
+	}
+
+	if err := mgr.Add(&retention.Janitor{
+		Client:   mgr.GetClient(),
+		Recorder: mgr.GetEventRecorderFor("gokubedog-retention"),
+		Defaults: retention.Policy{
+			ResolvedTTL:            resolvedReportTTL,
+			MaxReportsPerNamespace: maxReportsPerNamespace,
+		},
+		Interval: retentionInterval,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up report retention")
+		os.Exit(1)
+	}
+
+	if names := splitKeys(policyPacks); len(names) > 0 {
+		selected, err := packs.Lookup(names)
+		if err != nil {
+			setupLog.Error(err, "invalid --policy-packs")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&packs.Installer{
+			Client:    mgr.GetClient(),
+			APIReader: mgr.GetAPIReader(),
+			Packs:     selected,
+			Namespace: policyPackNamespace,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up policy packs")
+			os.Exit(1)
+		}
+	}
+
+	if err := mgr.Add(&notify.Digester{
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Interval:  time.Minute,
+		Channels:  channels,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up compliance digests")
+		os.Exit(1)
+	}
+	if err := mgr.Add(&notify.Refresher{
+		Client:      mgr.GetClient(),
+		APIReader:   mgr.GetAPIReader(),
+		ClusterName: clusterName,
+		Interval:    15 * time.Second,
+		Channels:    channels,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up notification refreshes")
+		os.Exit(1)
+	}
+
 	if metricsCertWatcher != nil {
 		setupLog.Info("Adding metrics certificate watcher to manager")
 		if err := mgr.Add(metricsCertWatcher); err != nil {
@@ -250,3 +454,23 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// packNames lists the names of the built-in policy packs.
+func packNames() string {
+	var names []string
+	for _, p := range packs.All() {
+		names = append(names, p.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// splitKeys splits a comma-separated flag value, dropping empty keys.
+func splitKeys(v string) []string {
+	var keys []string
+	for _, k := range strings.Split(v, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/madmmas/gokubedog/internal/packs"
+)
+
+// listPacks lists the built-in policy packs, or prints the manifests of some
+// of them for installation without the manager flag.
+func listPacks(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("packs", stderr)
+	export := fs.String("export", "", "Comma-separated packs whose manifests are printed, all for every pack.")
+	namespace := fs.String("namespace", "gokubedog-system", "Namespace of the exported PolicyProfiles.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *export == "" {
+		w := tabwriter.NewWriter(stdout, 0, 4, 3, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tVERSION\tSEVERITY\tKINDS\tDESCRIPTION")
+		for _, p := range packs.All() {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Name, p.Version, p.Severity, strings.Join(p.Kinds, ","), p.Description)
+		}
+		if err := w.Flush(); err != nil {
+			_, _ = fmt.Fprintf(stderr, "gokubedog packs: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	selected := packs.All()
+	if *export != "all" {
+		var err error
+		if selected, err = packs.Lookup(strings.Split(*export, ",")); err != nil {
+			_, _ = fmt.Fprintf(stderr, "gokubedog packs: %v\n", err)
+			return 2
+		}
+	}
+	var objects []any
+	for _, p := range selected {
+		objects = append(objects, p.Template())
+		for _, profile := range p.Profiles(*namespace) {
+			objects = append(objects, profile)
+		}
+	}
+	for i, obj := range objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "gokubedog packs: %v\n", err)
+			return 1
+		}
+		if i > 0 {
+			_, _ = fmt.Fprintln(stdout, "---")
+		}
+		_, _ = stdout.Write(data)
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("packs", func() {
+	var stdout, stderr *bytes.Buffer
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("lists the built-in packs", func() {
+		Expect(Main([]string{"packs"}, stdout, stderr)).To(Equal(0))
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		Expect(lines[0]).To(MatchRegexp(`^NAME\s+VERSION\s+SEVERITY\s+KINDS\s+DESCRIPTION$`))
+		Expect(lines).To(ContainElement(MatchRegexp(`^network-default-deny\s+1\.0\.0\s+high\s+Namespace\s+`)))
+	})
+
+	It("exports the manifests of the selected packs", func() {
+		Expect(Main([]string{"packs", "-export", "network-default-deny", "-namespace", "policies"}, stdout, stderr)).
+			To(Equal(0), stderr.String())
+		docs := strings.Split(stdout.String(), "---\n")
+		Expect(docs).To(HaveLen(2))
+
+		var template v1alpha1.PolicyTemplate
+		Expect(yaml.UnmarshalStrict([]byte(docs[0]), &template)).To(Succeed())
+		Expect(template.Name).To(Equal("network-default-deny"))
+		Expect(template.Spec.Checks).To(Equal([]v1alpha1.Check{v1alpha1.CheckDefaultDenyIngress}))
+		Expect(template.Spec.Remediation).NotTo(BeEmpty())
+
+		var profile v1alpha1.PolicyProfile
+		Expect(yaml.UnmarshalStrict([]byte(docs[1]), &profile)).To(Succeed())
+		Expect(profile.Namespace).To(Equal("policies"))
+		Expect(profile.Spec.Template.Name).To(Equal("network-default-deny"))
+	})
+
+	It("rejects unknown packs", func() {
+		Expect(Main([]string{"packs", "-export", "everything"}, stdout, stderr)).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring(`unknown policy pack "everything"`))
+	})
+})
@@ -0,0 +1,307 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+// policyTestSuffix names the policy test files found in directories.
+const policyTestSuffix = "_test.yaml"
+
+// policyTest is a policy test file: a profile, the resources it is evaluated
+// against and the verdict expected on each of them.
+type policyTest struct {
+	// Profile is the path of the PolicyProfile manifest, relative to the
+	// test file.
+	Profile string `json:"profile"`
+	// Template is the path of the PolicyTemplate manifest the profile refers
+	// to, if any, relative to the test file.
+	Template string `json:"template,omitempty"`
+	// Exceptions are paths of PolicyException manifests, relative to the test
+	// file.
+	Exceptions []string `json:"exceptions,omitempty"`
+	// Now is the time exceptions are evaluated at, the current time if unset.
+	Now *metav1.Time `json:"now,omitempty"`
+	// Resources are the input manifests. Only their kind and metadata are
+	// read, unless the profile runs checks. The checks reading other objects
+	// find them among the resources.
+	Resources []unstructured.Unstructured `json:"resources"`
+	// Expect lists the expected verdicts. Resources without one must not
+	// violate the profile.
+	Expect []expectation `json:"expect,omitempty"`
+}
+
+// expectation is the verdict expected on one resource.
+type expectation struct {
+	// Resource is the namespace/name of an input resource, or its name if it
+	// is cluster-scoped.
+	Resource string `json:"resource"`
+	// Violations lists the policy keys the resource drifts on, none if it
+	// passes.
+	Violations []string `json:"violations,omitempty"`
+	// Excepted expects the drift to be covered by an exception.
+	Excepted bool `json:"excepted,omitempty"`
+}
+
+// verdict is the outcome of a profile on a resource, as compared by tests.
+type verdict struct {
+	matched  bool
+	drift    []string
+	excepted bool
+}
+
+func (v verdict) String() string {
+	switch {
+	case !v.matched:
+		return "not evaluated"
+	case len(v.drift) == 0:
+		return "passes"
+	case v.excepted:
+		return "excepted drift on " + strings.Join(v.drift, ", ")
+	default:
+		return "violates " + strings.Join(v.drift, ", ")
+	}
+}
+
+func (v verdict) equal(o verdict) bool {
+	return v.matched == o.matched && slices.Equal(v.drift, o.drift) && (len(v.drift) == 0 || v.excepted == o.excepted)
+}
+
+// testPolicies runs the policy test files found in the given files and
+// directories and reports their results as go test does.
+func testPolicies(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("test", stderr)
+	verbose := fs.Bool("v", false, "Print the verdict on every resource.")
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(stderr, "Usage: gokubedog test [flags] [path ...]\n\n"+
+			"Runs the policy tests of the given files, and of the *%s files of the given\n"+
+			"directories, the current one by default.\n\n", policyTestSuffix)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	files, err := findPolicyTests(paths)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "gokubedog test: %v\n", err)
+		return 1
+	}
+	if len(files) == 0 {
+		_, _ = fmt.Fprintf(stderr, "gokubedog test: no *%s files found\n", policyTestSuffix)
+		return 1
+	}
+
+	failed := false
+	for _, file := range files {
+		results, failures, err := runPolicyTest(file)
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+		if len(failures) > 0 {
+			failed = true
+			_, _ = fmt.Fprintf(stdout, "--- FAIL: %s\n", file)
+			for _, f := range failures {
+				_, _ = fmt.Fprintf(stdout, "    %s\n", f)
+			}
+		} else if *verbose {
+			_, _ = fmt.Fprintf(stdout, "--- PASS: %s\n", file)
+		}
+		if *verbose {
+			for _, r := range results {
+				_, _ = fmt.Fprintf(stdout, "    %s\n", r)
+			}
+		}
+		if len(failures) == 0 {
+			_, _ = fmt.Fprintf(stdout, "ok\t%s\n", file)
+		}
+	}
+	if failed {
+		_, _ = fmt.Fprintln(stdout, "FAIL")
+		return 1
+	}
+	return 0
+}
+
+// findPolicyTests expands directories into the policy test files they
+// contain, recursively.
+func findPolicyTests(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(p, policyTestSuffix) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// runPolicyTest evaluates the profile of a test file over its resources. It
+// returns the verdict on every resource and the failed expectations.
+func runPolicyTest(file string) (results, failures []string, err error) {
+	var test policyTest
+	if err := readManifest(file, &test); err != nil {
+		return nil, nil, err
+	}
+	if test.Profile == "" {
+		return nil, nil, fmt.Errorf("%s: profile is required", file)
+	}
+	dir := filepath.Dir(file)
+	profile := &v1alpha1.PolicyProfile{}
+	if err := readManifest(filepath.Join(dir, test.Profile), profile); err != nil {
+		return nil, nil, err
+	}
+	if profile.Spec.Template != nil {
+		if test.Template == "" {
+			return nil, nil, fmt.Errorf("%s: profile refers to template %s, template is required", file, profile.Spec.Template.Name)
+		}
+		template := &v1alpha1.PolicyTemplate{}
+		if err := readManifest(filepath.Join(dir, test.Template), template); err != nil {
+			return nil, nil, err
+		}
+		if profile, err = policy.Resolve(profile, template); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	exceptions := make([]v1alpha1.PolicyException, len(test.Exceptions))
+	for i, path := range test.Exceptions {
+		if err := readManifest(filepath.Join(dir, path), &exceptions[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	now := metav1.Now()
+	if test.Now != nil {
+		now = *test.Now
+	}
+
+	verdicts := map[string]verdict{}
+	names := make([]string, len(test.Resources))
+	for i := range test.Resources {
+		obj := &test.Resources[i]
+		names[i] = resourceKey(obj.GetNamespace(), obj.GetName())
+		if _, ok := verdicts[names[i]]; ok {
+			return nil, nil, fmt.Errorf("%s: resource %s is defined twice", file, names[i])
+		}
+		verdicts[names[i]] = verdict{}
+	}
+	cluster, err := fixtureCluster(profile, test.Resources)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+	for i, name := range names {
+		obj := &test.Resources[i]
+		var v verdict
+		if policy.MatchesKind(profile, obj.GroupVersionKind()) {
+			outcome, err := policy.Evaluate(context.Background(), cluster, profile, obj, exceptions, now)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: %s: %w", file, name, err)
+			}
+			v = verdict{matched: outcome.Matched, drift: sortedKeys(outcome.Drift), excepted: outcome.Exception != nil}
+		}
+		verdicts[name] = v
+		results = append(results, fmt.Sprintf("%s: %s", name, v))
+	}
+
+	expected := map[string]bool{}
+	for _, e := range test.Expect {
+		got, ok := verdicts[e.Resource]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: no such resource in the test", e.Resource))
+			continue
+		}
+		expected[e.Resource] = true
+		want := verdict{matched: true, drift: slices.Sorted(slices.Values(e.Violations)), excepted: e.Excepted}
+		if !want.equal(got) {
+			failures = append(failures, fmt.Sprintf("%s: expected %s, got %s", e.Resource, want, got))
+		}
+	}
+	for _, name := range names {
+		if got := verdicts[name]; !expected[name] && len(got.drift) > 0 && !got.excepted {
+			failures = append(failures, fmt.Sprintf("%s: unexpectedly %s", name, got))
+		}
+	}
+	return results, failures, nil
+}
+
+// fixtureCluster returns a client serving resources to the checks of the
+// profile reading other objects. Resources of unknown kinds are left out.
+func fixtureCluster(profile *v1alpha1.PolicyProfile, resources []unstructured.Unstructured) (client.Reader, error) {
+	cluster := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	if len(profile.Spec.Checks) == 0 {
+		return cluster, nil
+	}
+	for i := range resources {
+		obj := resources[i].DeepCopy()
+		if err := cluster.Create(context.Background(), obj); err != nil && !meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("resource %s: %w", resourceKey(obj.GetNamespace(), obj.GetName()), err)
+		}
+	}
+	return cluster, nil
+}
+
+func resourceKey(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("test", func() {
+	var (
+		dir            string
+		stdout, stderr *bytes.Buffer
+	)
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+	run := func(args ...string) int {
+		return Main(append([]string{"test"}, args...), stdout, stderr)
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+		write("profiles/require-team.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyProfile
+metadata:
+  name: require-team
+  namespace: gokubedog-system
+spec:
+  match: {kind: NetworkPolicy, namespace: team-*}
+  policy: {team: payments, tier: backend}
+`)
+		write("tests/legacy.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyException
+metadata:
+  name: legacy
+  namespace: team-a
+spec:
+  profileName: require-team
+  profileNamespace: gokubedog-system
+  resource: {kind: NetworkPolicy, name: legacy}
+  reason: being decommissioned
+  expiresAt: "2025-07-01T00:00:00Z"
+`)
+	})
+
+	testFile := func(expect string) string {
+		return write("tests/require-team_test.yaml", `profile: ../profiles/require-team.yaml
+exceptions: [legacy.yaml]
+now: "2025-06-01T00:00:00Z"
+resources:
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata: {name: web, namespace: team-a, labels: {team: payments, tier: backend}}
+  spec: {podSelector: {}}
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata: {name: api, namespace: team-a, labels: {team: payments}}
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata: {name: legacy, namespace: team-a}
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata: {name: web, namespace: default}
+- apiVersion: v1
+  kind: Pod
+  metadata: {name: db, namespace: team-a}
+expect:
+`+expect)
+	}
+
+	It("passes when every verdict is as expected", func() {
+		testFile(`- resource: team-a/web
+- resource: team-a/api
+  violations: [tier]
+- resource: team-a/legacy
+  violations: [tier, team]
+  excepted: true
+`)
+		Expect(run("-v", dir)).To(Equal(0), stderr.String())
+		Expect(stdout.String()).To(Equal(`--- PASS: ` + filepath.Join(dir, "tests/require-team_test.yaml") + `
+    team-a/web: passes
+    team-a/api: violates tier
+    team-a/legacy: excepted drift on team, tier
+    default/web: not evaluated
+    team-a/db: not evaluated
+ok	` + filepath.Join(dir, "tests/require-team_test.yaml") + `
+`))
+	})
+
+	It("fails on wrong and missing expectations", func() {
+		path := testFile(`- resource: team-a/web
+  violations: [team]
+- resource: team-a/legacy
+  violations: [team, tier]
+- resource: team-a/gone
+- resource: default/web
+`)
+		Expect(run(path)).To(Equal(1))
+		Expect(stdout.String()).To(Equal(`--- FAIL: ` + path + `
+    team-a/web: expected violates team, got passes
+    team-a/legacy: expected violates team, tier, got excepted drift on team, tier
+    team-a/gone: no such resource in the test
+    default/web: expected passes, got not evaluated
+    team-a/api: unexpectedly violates tier
+FAIL
+`))
+	})
+
+	It("resolves the template of the profile", func() {
+		write("templates/required-labels.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyTemplate
+metadata:
+  name: required-labels
+spec:
+  parameters:
+  - {name: labels, type: StringList, required: true}
+  labels:
+  - keysFrom: labels
+`)
+		write("profiles/templated.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyProfile
+metadata:
+  name: templated
+  namespace: gokubedog-system
+spec:
+  match: {kind: Deployment, namespace: team-a}
+  template:
+    name: required-labels
+    parameters: {labels: [app, owner]}
+`)
+		path := write("tests/templated_test.yaml", `profile: ../profiles/templated.yaml
+template: ../templates/required-labels.yaml
+resources:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata: {name: web, namespace: team-a, labels: {app: web, owner: me}}
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata: {name: api, namespace: team-a, labels: {app: api}}
+expect:
+- resource: team-a/api
+  violations: [owner]
+`)
+		Expect(run(path)).To(Equal(0), stdout.String())
+
+		Expect(os.Remove(filepath.Join(dir, "templates/required-labels.yaml"))).To(Succeed())
+		Expect(run(path)).To(Equal(1))
+		Expect(stdout.String()).To(ContainSubstring("required-labels.yaml: no such file or directory"))
+	})
+
+	It("runs the checks of the profile on the whole resources", func() {
+		write("profiles/default-deny.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyProfile
+metadata: {name: default-deny, namespace: gokubedog-system}
+spec:
+  match: {kind: Namespace, namespace: team-*}
+  checks: [DefaultDenyIngress]
+`)
+		path := write("default-deny_test.yaml", `profile: profiles/default-deny.yaml
+resources:
+- apiVersion: v1
+  kind: Namespace
+  metadata: {name: team-a}
+- apiVersion: v1
+  kind: Namespace
+  metadata: {name: team-b}
+- apiVersion: networking.k8s.io/v1
+  kind: NetworkPolicy
+  metadata: {name: default-deny, namespace: team-a}
+  spec: {podSelector: {}, policyTypes: [Ingress]}
+expect:
+- resource: team-b
+  violations: [DefaultDenyIngress]
+`)
+		Expect(run("-v", path)).To(Equal(0), stdout.String())
+		Expect(stdout.String()).To(ContainSubstring("    team-a: passes\n    team-b: violates DefaultDenyIngress\n"))
+	})
+
+	It("fails on invalid test files", func() {
+		write("broken_test.yaml", "profile: missing.yaml\nresources: []\n")
+		Expect(run(dir)).To(Equal(1))
+		Expect(stdout.String()).To(ContainSubstring("--- FAIL: " + filepath.Join(dir, "broken_test.yaml")))
+		Expect(stdout.String()).To(ContainSubstring("missing.yaml: no such file or directory"))
+
+		Expect(run(GinkgoT().TempDir())).To(Equal(1))
+		Expect(stderr.String()).To(ContainSubstring("no *_test.yaml files found"))
+	})
+})
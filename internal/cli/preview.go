@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/notify"
+)
+
+// previewMessage renders the message a channel would send for a report,
+// validating the templates of the channel on the way.
+func previewMessage(args []string, stdout, stderr io.Writer) int {
+	fs := newFlagSet("preview-message", stderr)
+	channelPath := fs.String("channel", "", "NotificationChannel manifest whose templates are used. "+
+		"The built-in template is used if unset.")
+	templatePath := fs.String("template", "", "File holding a template that replaces the default template of the channel.")
+	reportPath := fs.String("report", "", "PolicyViolationReport manifest to render. A sample report is used if unset.")
+	profilePath := fs.String("profile", "", "PolicyProfile manifest of the report.")
+	cluster := fs.String("cluster", "", "Cluster name available to templates as .Cluster.")
+	var configMaps stringList
+	fs.Var(&configMaps, "configmap", "ConfigMap manifest resolving configMapKeyRef templates. May be repeated.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	fail := func(err error) int {
+		_, _ = fmt.Fprintf(stderr, "gokubedog preview-message: %v\n", err)
+		return 1
+	}
+
+	var spec *v1alpha1.MessageTemplateSpec
+	def := notify.DefaultTemplate
+	if *channelPath != "" {
+		var nc v1alpha1.NotificationChannel
+		if err := readManifest(*channelPath, &nc); err != nil {
+			return fail(err)
+		}
+		spec = nc.Spec.Template
+		def = notify.DefaultChannelTemplate(&nc.Spec)
+	}
+	if *templatePath != "" {
+		text, err := os.ReadFile(*templatePath)
+		if err != nil {
+			return fail(err)
+		}
+		if spec == nil {
+			spec = &v1alpha1.MessageTemplateSpec{}
+		} else {
+			spec = spec.DeepCopy()
+		}
+		spec.Default = &v1alpha1.TemplateSource{Inline: string(text)}
+	}
+
+	cms := map[string]*corev1.ConfigMap{}
+	for _, path := range configMaps {
+		cm := &corev1.ConfigMap{}
+		if err := readManifest(path, cm); err != nil {
+			return fail(err)
+		}
+		cms[cm.Namespace+"/"+cm.Name] = cm
+	}
+	tmpl, err := notify.NewTemplate(spec, def, func(ref v1alpha1.ConfigMapKeySelector) (string, error) {
+		cm, ok := cms[ref.Namespace+"/"+ref.Name]
+		if !ok {
+			return "", fmt.Errorf("configmap %s/%s not given, pass it with -configmap", ref.Namespace, ref.Name)
+		}
+		v, ok := cm.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("configmap %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+		}
+		return v, nil
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	msg := notify.SampleMessage(*cluster)
+	if *reportPath != "" {
+		rep := &v1alpha1.PolicyViolationReport{}
+		if err := readManifest(*reportPath, rep); err != nil {
+			return fail(err)
+		}
+		var profile *v1alpha1.PolicyProfile
+		if *profilePath != "" {
+			profile = &v1alpha1.PolicyProfile{}
+			if err := readManifest(*profilePath, profile); err != nil {
+				return fail(err)
+			}
+		}
+		msg = notify.NewMessage(rep, profile, *cluster)
+	}
+
+	text, err := tmpl.Render(msg)
+	if err != nil {
+		return fail(err)
+	}
+	_, _ = fmt.Fprintln(stdout, text)
+	return 0
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("preview-message", func() {
+	var (
+		dir            string
+		stdout, stderr *bytes.Buffer
+	)
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+	run := func(args ...string) int {
+		return Main(append([]string{"preview-message"}, args...), stdout, stderr)
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("renders the sample report with the built-in template", func() {
+		Expect(run("-cluster", "prod")).To(Equal(0))
+		Expect(stdout.String()).To(HavePrefix("*🚨 Policy Violation Detected* in prod\n"))
+	})
+
+	It("renders a report with the templates of a channel", func() {
+		channel := write("channel.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: NotificationChannel
+metadata:
+  name: ops
+spec:
+  type: slack
+  template:
+    profiles:
+    - profile: deny-all
+      template:
+        configMapKeyRef: {namespace: gokubedog-system, name: templates, key: deny-all}
+`)
+		cm := write("cm.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: templates
+  namespace: gokubedog-system
+data:
+  deny-all: "{{ .Resource.Kind }} {{ .Resource.Name }} violates {{ .Report.Spec.ProfileName }}{{ range .Drift }} [{{ .Key }}]{{ end }}"
+`)
+		report := write("report.yaml", `apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyViolationReport
+metadata:
+  name: violation-1
+  namespace: team-b
+spec:
+  profileName: deny-all
+  violatedResource: {kind: NetworkPolicy, name: np-1, namespace: team-b}
+  drift: {team: missing}
+`)
+		Expect(run("-channel", channel, "-configmap", cm, "-report", report)).To(Equal(0), stderr.String())
+		Expect(stdout.String()).To(Equal("NetworkPolicy np-1 violates deny-all [team]\n"))
+	})
+
+	It("reports invalid templates", func() {
+		tmpl := write("bad.tmpl", "{{ .Nope }}")
+		Expect(run("-template", tmpl)).To(Equal(1))
+		Expect(stderr.String()).To(ContainSubstring("invalid default template"))
+	})
+
+	It("asks for ConfigMaps it cannot resolve", func() {
+		channel := write("channel.yaml", `spec:
+  type: slack
+  template:
+    default:
+      configMapKeyRef: {namespace: ns, name: missing, key: k}
+`)
+		Expect(run("-channel", channel)).To(Equal(1))
+		Expect(stderr.String()).To(ContainSubstring("pass it with -configmap"))
+	})
+
+	It("rejects unknown commands", func() {
+		Expect(Main([]string{"nope"}, stdout, stderr)).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("preview-message"))
+	})
+})
@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/history"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+// evaluation holds the state of one pass of a profile over its resources,
+// either all of them on a full run or a single changed object.
+type evaluation struct {
+	r       *PolicyProfileReconciler
+	profile *watchdogv1alpha1.PolicyProfile
+	// gvk is the kind of the resources.
+	gvk schema.GroupVersionKind
+	// resolved is the profile with the rules of its template, resources are
+	// compared with its policy.
+	resolved   *watchdogv1alpha1.PolicyProfile
+	severity   watchdogv1alpha1.Severity
+	existing   map[string]*watchdogv1alpha1.PolicyViolationReport
+	exceptions []watchdogv1alpha1.PolicyException
+
+	violating map[string]bool
+	summaries map[string]*watchdogv1alpha1.NamespaceSummary
+	// preview collects what the profile would report in Preview mode, it is
+	// nil in Enforce mode.
+	preview *watchdogv1alpha1.PreviewSummary
+}
+
+func (r *PolicyProfileReconciler) newEvaluation(
+	profile, resolved *watchdogv1alpha1.PolicyProfile, gvk schema.GroupVersionKind,
+	existing map[string]*watchdogv1alpha1.PolicyViolationReport,
+	exceptions []watchdogv1alpha1.PolicyException,
+) *evaluation {
+	severity := profile.Spec.Severity
+	if severity == "" {
+		severity = watchdogv1alpha1.SeverityMedium
+	}
+	ev := &evaluation{
+		r:          r,
+		profile:    profile,
+		gvk:        gvk,
+		resolved:   resolved,
+		severity:   severity,
+		existing:   existing,
+		exceptions: exceptions,
+		violating:  map[string]bool{},
+		summaries:  map[string]*watchdogv1alpha1.NamespaceSummary{},
+	}
+	if profile.Previewing() {
+		ev.preview = &watchdogv1alpha1.PreviewSummary{}
+	}
+	return ev
+}
+
+// needsObjects reports whether the resources are evaluated whole rather than
+// by their metadata, as the checks look past it.
+func (ev *evaluation) needsObjects() bool {
+	return len(ev.resolved.Spec.Checks) > 0
+}
+
+// evaluateObject is evaluate for a whole resource.
+func (ev *evaluation) evaluateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	meta, _ := obj.Object["metadata"].(map[string]any)
+	item := &metav1.PartialObjectMetadata{}
+	item.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(meta, &item.ObjectMeta); err != nil {
+		return fmt.Errorf("invalid metadata of %s %s: %w", obj.GetKind(), client.ObjectKeyFromObject(obj), err)
+	}
+	return ev.evaluate(ctx, item, obj)
+}
+
+// fetch returns the resource of item the way it is evaluated: item itself,
+// or the whole resource if the checks need it and its namespace matches. It
+// returns nil if the resource is gone.
+func (ev *evaluation) fetch(ctx context.Context, item *metav1.PartialObjectMetadata) (client.Object, error) {
+	namespace := policy.HomeNamespace(ev.profile.Spec.Match.Kind, item)
+	if !ev.needsObjects() || !ev.resolved.MatchesNamespace(namespace) {
+		return item, nil
+	}
+	full := &unstructured.Unstructured{}
+	full.SetGroupVersionKind(ev.gvk)
+	if err := ev.r.Get(ctx, client.ObjectKeyFromObject(item), full); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return full, nil
+}
+
+// evaluate compares a single resource with the policy and opens, refreshes or
+// suppresses its report. In Preview mode it only records the outcome. The
+// policy is evaluated on obj, which is item itself or its whole resource when
+// the checks need it.
+func (ev *evaluation) evaluate(ctx context.Context, item *metav1.PartialObjectMetadata, obj client.Object) error {
+	l := logf.FromContext(ctx)
+	r, profile := ev.r, ev.profile
+	kind := profile.Spec.Match.Kind
+	// Namespaces are matched and reported in themselves
+	namespace := policy.HomeNamespace(kind, item)
+	if !ev.resolved.MatchesNamespace(namespace) {
+		return nil
+	}
+
+	outcome, err := policy.Evaluate(ctx, r, ev.resolved, obj, ev.exceptions, metav1.Now())
+	if err != nil {
+		return fmt.Errorf("failed evaluating %s %s: %w", kind, client.ObjectKeyFromObject(item), err)
+	}
+	if !outcome.Matched {
+		return nil
+	}
+	summary, ok := ev.summaries[namespace]
+	if !ok {
+		summary = &watchdogv1alpha1.NamespaceSummary{Namespace: namespace}
+		ev.summaries[namespace] = summary
+	}
+	summary.Matched++
+	if ev.preview != nil {
+		ev.preview.Matched++
+	}
+
+	drift := outcome.Drift
+	if len(drift) == 0 {
+		return nil
+	}
+	key := reportKey(namespace, item.GetName())
+	if exc := outcome.Exception; exc != nil {
+		if ev.preview != nil {
+			ev.preview.Excepted++
+			return nil
+		}
+		l.Info("Policy drift excepted", "resource", item.GetName(), "namespace", namespace, "exception", exc.Name)
+		if rep, ok := ev.existing[key]; ok && !rep.Status.IsResolved() {
+			r.suppressReport(ctx, rep)
+		}
+		ev.violating[key] = true // handled, keep resolveStale from resolving it again
+		return nil
+	}
+	l.Info("Policy drift detected", "resource", item.GetName(), "namespace", namespace, "drift", drift)
+
+	ev.violating[key] = true
+	summary.Violating++
+	if ev.preview != nil {
+		ev.preview.Record(namespace, item.GetName(), drift)
+		return nil
+	}
+
+	owner, err := r.resolveOwnership(ctx, item)
+	if err != nil {
+		l.Error(err, "unable to resolve ownership", "resource", item.GetName(), "namespace", namespace)
+	}
+
+	// Deduplication: keep a single report per resource/profile and refresh it in place
+	if rep, ok := ev.existing[key]; ok {
+		if err != nil {
+			owner = rep.Spec.Ownership
+		}
+		if err := r.refreshReport(ctx, rep, drift, ev.severity, ev.resolved.Spec.Remediation, owner); err != nil {
+			l.Error(err, "unable to update PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+		}
+		return nil
+	}
+
+	// Emit PolicyViolationReport
+	report := &watchdogv1alpha1.PolicyViolationReport{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "violation-",
+			Namespace:    namespace,
+			Labels: map[string]string{
+				watchdogv1alpha1.ProfileNameLabel:      profile.Name,
+				watchdogv1alpha1.ProfileNamespaceLabel: profile.Namespace,
+			},
+		},
+		Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+			ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{
+				Kind:      kind,
+				Name:      item.GetName(),
+				Namespace: namespace,
+			},
+			ProfileName:      profile.Name,
+			ProfileNamespace: profile.Namespace,
+			Drift:            drift,
+			Severity:         ev.severity,
+			Remediation:      ev.resolved.Spec.Remediation,
+			Ownership:        owner,
+		},
+	}
+	setTeamLabel(report, owner)
+
+	// Owner references cannot cross namespaces, so reports elsewhere are
+	// tracked through a finalizer on the profile instead.
+	if report.Namespace == profile.Namespace {
+		if err := controllerutil.SetControllerReference(profile, report, r.Scheme); err != nil {
+			return fmt.Errorf("failed setting owner reference on report: %w", err)
+		}
+	} else if !controllerutil.ContainsFinalizer(profile, watchdogv1alpha1.ReportCleanupFinalizer) {
+		controllerutil.AddFinalizer(profile, watchdogv1alpha1.ReportCleanupFinalizer)
+		if err := r.Update(ctx, profile); err != nil {
+			return fmt.Errorf("failed adding finalizer to PolicyProfile: %w", err)
+		}
+	}
+
+	l.Info("Creating PolicyViolationReport", "resource", item.GetName(), "namespace", namespace)
+	if err := r.Create(ctx, report); err != nil {
+		l.Error(err, "unable to create PolicyViolationReport")
+		return nil
+	}
+	l.Info("Created PolicyViolationReport", "name", report.Name, "namespace", report.Namespace)
+	ev.existing[key] = report
+
+	r.recordHistory(ctx, history.EventOpened, report)
+
+	report.Status.Phase = watchdogv1alpha1.ReportPhaseOpen
+	report.Status.OpenedAt = &metav1.Time{Time: report.CreationTimestamp.Time}
+	if err := r.Status().Update(ctx, report); err != nil {
+		l.Error(err, "unable to set PolicyViolationReport phase", "name", report.Name)
+	}
+	return nil
+}
+
+// resolveStale resolves the known reports whose resource was not found
+// violating, because its drift was fixed or it is gone. Previews leave the
+// reports alone.
+func (ev *evaluation) resolveStale(ctx context.Context) {
+	if ev.preview != nil {
+		return
+	}
+	l := logf.FromContext(ctx)
+	for key, rep := range ev.existing {
+		if ev.violating[key] || rep.Status.IsResolved() {
+			continue
+		}
+		now := metav1.Now()
+		rep.Status.Phase = watchdogv1alpha1.ReportPhaseResolved
+		rep.Status.ResolvedAt = &now
+		if err := ev.r.Status().Update(ctx, rep); err != nil {
+			l.Error(err, "unable to resolve PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+			continue
+		}
+		l.Info("Resolved PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+		ev.r.recordHistory(ctx, history.EventResolved, rep)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ProfileIndex maps a group kind and namespace to the watch-driven profiles
+// matching it, so that a change to one object only re-evaluates that object
+// against the profiles it is subject to.
+type ProfileIndex struct {
+	mu       sync.RWMutex
+	profiles map[types.NamespacedName]indexEntry
+	kinds    map[schema.GroupKind]*kindIndex
+}
+
+type indexEntry struct {
+	kind    schema.GroupKind
+	pattern string
+}
+
+// kindIndex holds the profiles of one group kind, keyed by exact namespace or,
+// for wildcard patterns, by namespace prefix.
+type kindIndex struct {
+	exact    map[string]map[types.NamespacedName]struct{}
+	prefixes map[string]map[types.NamespacedName]struct{}
+}
+
+// NewProfileIndex returns an empty index.
+func NewProfileIndex() *ProfileIndex {
+	return &ProfileIndex{
+		profiles: map[types.NamespacedName]indexEntry{},
+		kinds:    map[schema.GroupKind]*kindIndex{},
+	}
+}
+
+// Set indexes the profile under kind and namespace pattern, replacing any
+// previous entry of the profile.
+func (x *ProfileIndex) Set(profile types.NamespacedName, kind schema.GroupKind, pattern string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	entry := indexEntry{kind: kind, pattern: pattern}
+	if old, ok := x.profiles[profile]; ok {
+		if old == entry {
+			return
+		}
+		x.remove(profile, old)
+	}
+	x.profiles[profile] = entry
+
+	ki, ok := x.kinds[kind]
+	if !ok {
+		ki = &kindIndex{
+			exact:    map[string]map[types.NamespacedName]struct{}{},
+			prefixes: map[string]map[types.NamespacedName]struct{}{},
+		}
+		x.kinds[kind] = ki
+	}
+	bucket, key := ki.exact, pattern
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		bucket, key = ki.prefixes, prefix
+	}
+	if bucket[key] == nil {
+		bucket[key] = map[types.NamespacedName]struct{}{}
+	}
+	bucket[key][profile] = struct{}{}
+}
+
+// Remove drops the profile from the index.
+func (x *ProfileIndex) Remove(profile types.NamespacedName) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if entry, ok := x.profiles[profile]; ok {
+		x.remove(profile, entry)
+	}
+}
+
+func (x *ProfileIndex) remove(profile types.NamespacedName, entry indexEntry) {
+	delete(x.profiles, profile)
+	ki := x.kinds[entry.kind]
+	bucket, key := ki.exact, entry.pattern
+	if prefix, ok := strings.CutSuffix(entry.pattern, "*"); ok {
+		bucket, key = ki.prefixes, prefix
+	}
+	delete(bucket[key], profile)
+	if len(bucket[key]) == 0 {
+		delete(bucket, key)
+	}
+	if len(ki.exact) == 0 && len(ki.prefixes) == 0 {
+		delete(x.kinds, entry.kind)
+	}
+}
+
+// Lookup returns the profiles applying to an object of kind in namespace,
+// sorted by namespace and name.
+func (x *ProfileIndex) Lookup(kind schema.GroupKind, namespace string) []types.NamespacedName {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	ki, ok := x.kinds[kind]
+	if !ok {
+		return nil
+	}
+	var out []types.NamespacedName
+	for profile := range ki.exact[namespace] {
+		out = append(out, profile)
+	}
+	for prefix, profiles := range ki.prefixes {
+		if !strings.HasPrefix(namespace, prefix) {
+			continue
+		}
+		for profile := range profiles {
+			out = append(out, profile)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
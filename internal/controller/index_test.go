@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("ProfileIndex", func() {
+	networkPolicy := schema.GroupKind{Group: "networking.k8s.io", Kind: "NetworkPolicy"}
+	pod := schema.GroupKind{Kind: "Pod"}
+	exact := types.NamespacedName{Namespace: "ops", Name: "exact"}
+	prefix := types.NamespacedName{Namespace: "ops", Name: "prefix"}
+	all := types.NamespacedName{Namespace: "ops", Name: "all"}
+
+	It("should look up profiles by kind, namespace and namespace prefix", func() {
+		x := NewProfileIndex()
+		x.Set(exact, networkPolicy, "prod-a")
+		x.Set(prefix, networkPolicy, "prod-*")
+		x.Set(all, pod, "*")
+
+		Expect(x.Lookup(networkPolicy, "prod-a")).To(Equal([]types.NamespacedName{exact, prefix}))
+		Expect(x.Lookup(networkPolicy, "prod-b")).To(Equal([]types.NamespacedName{prefix}))
+		Expect(x.Lookup(networkPolicy, "dev")).To(BeEmpty())
+		Expect(x.Lookup(pod, "dev")).To(Equal([]types.NamespacedName{all}))
+	})
+
+	It("should replace and remove entries", func() {
+		x := NewProfileIndex()
+		x.Set(exact, networkPolicy, "prod-a")
+		x.Set(exact, pod, "prod-a")
+		Expect(x.Lookup(networkPolicy, "prod-a")).To(BeEmpty())
+		Expect(x.Lookup(pod, "prod-a")).To(Equal([]types.NamespacedName{exact}))
+
+		x.Remove(exact)
+		x.Remove(exact)
+		Expect(x.Lookup(pod, "prod-a")).To(BeEmpty())
+		Expect(x.kinds).To(BeEmpty())
+	})
+})
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+// ObjectRequest asks for the evaluation of a single object against a single
+// continuous profile.
+type ObjectRequest struct {
+	Profile types.NamespacedName
+	Kind    schema.GroupVersionKind
+	Object  types.NamespacedName
+}
+
+// reconcileObject re-evaluates one changed object. Namespace summaries are
+// left to the full evaluations triggered by profile changes.
+func (r *PolicyProfileReconciler) reconcileObject(ctx context.Context, req ObjectRequest) (ctrl.Result, error) {
+	l := logf.FromContext(ctx).WithValues("profile", req.Profile, "object", req.Object, "kind", req.Kind.Kind)
+	ctx = logf.IntoContext(ctx, l)
+
+	var profile watchdogv1alpha1.PolicyProfile
+	if err := r.Get(ctx, req.Profile, &profile); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !profile.DeletionTimestamp.IsZero() || !isContinuous(&profile) {
+		return ctrl.Result{}, nil
+	}
+
+	unlock := r.lockProfile(req.Profile)
+	defer unlock()
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(req.Kind)
+	found := true
+	if err := r.Get(ctx, req.Object, obj); apierrors.IsNotFound(err) {
+		found = false
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed fetching %s %s: %w", req.Kind.Kind, req.Object, err)
+	}
+
+	reports, err := r.listProfileReports(ctx, &profile)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	namespace := policy.HomeNamespace(profile.Spec.Match.Kind, &metav1.ObjectMeta{
+		Namespace: req.Object.Namespace, Name: req.Object.Name,
+	})
+	key := reportKey(namespace, req.Object.Name)
+	existing := map[string]*watchdogv1alpha1.PolicyViolationReport{}
+	if rep, ok := reports[key]; ok {
+		existing[key] = rep
+	}
+	exceptions := &watchdogv1alpha1.PolicyExceptionList{}
+	if err := r.List(ctx, exceptions, client.InNamespace(namespace)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed listing PolicyExceptions: %w", err)
+	}
+
+	resolved, err := r.resolveTemplate(ctx, &profile)
+	if err != nil || resolved == nil {
+		return ctrl.Result{}, err
+	}
+	ev := r.newEvaluation(&profile, resolved, req.Kind, existing, exceptions.Items)
+	if found && obj.DeletionTimestamp.IsZero() {
+		whole, err := ev.fetch(ctx, obj)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed fetching %s %s: %w", req.Kind.Kind, req.Object, err)
+		}
+		if whole != nil {
+			if err := ev.evaluate(ctx, obj, whole); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+	ev.resolveStale(ctx)
+	return ctrl.Result{}, nil
+}
+
+// indexProfile keeps the index and the object watches in line with the
+// schedule of the profile. Only continuous profiles are indexed.
+func (r *PolicyProfileReconciler) indexProfile(
+	ctx context.Context, profile *watchdogv1alpha1.PolicyProfile, gvr schema.GroupVersionResource,
+) {
+	if r.index == nil {
+		return
+	}
+	key := client.ObjectKeyFromObject(profile)
+	if !isContinuous(profile) {
+		r.index.Remove(key)
+		return
+	}
+	gvk := targetKind(profile, gvr)
+	r.index.Set(key, gvk.GroupKind(), profile.Spec.Match.Namespace)
+	if err := r.ensureWatch(gvk); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to watch target resources", "kind", gvk.String())
+	}
+}
+
+// ensureWatch starts a metadata-only watch on gvk the first time a continuous
+// profile targets it. Watches are kept for the life of the manager.
+func (r *PolicyProfileReconciler) ensureWatch(gvk schema.GroupVersionKind) error {
+	r.watchMu.Lock()
+	defer r.watchMu.Unlock()
+	if r.watched[gvk.GroupKind()] {
+		return nil
+	}
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	src := source.TypedKind(r.cache, client.Object(obj),
+		handler.TypedEnqueueRequestsFromMapFunc(func(_ context.Context, o client.Object) []ObjectRequest {
+			var requests []ObjectRequest
+			for _, profile := range r.index.Lookup(gvk.GroupKind(), policy.HomeNamespace(gvk.Kind, o)) {
+				requests = append(requests, ObjectRequest{Profile: profile, Kind: gvk, Object: client.ObjectKeyFromObject(o)})
+			}
+			return requests
+		}))
+	if err := r.objects.Watch(src); err != nil {
+		return err
+	}
+	r.watched[gvk.GroupKind()] = true
+	return nil
+}
+
+// lockProfile serializes the evaluations of a profile, so that full runs and
+// object runs never race on the same reports.
+func (r *PolicyProfileReconciler) lockProfile(key types.NamespacedName) func() {
+	mu, _ := r.locks.LoadOrStore(key, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
@@ -19,30 +19,66 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
-	"time"
+	"sync"
 
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/client-go/dynamic"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/history"
+	"github.com/madmmas/gokubedog/internal/ownership"
+	"github.com/madmmas/gokubedog/internal/target"
 )
 
 // PolicyProfileReconciler reconciles a PolicyProfile object
 type PolicyProfileReconciler struct {
 	client.Client
-	Scheme  *runtime.Scheme
-	DynClnt dynamic.Interface
+	Scheme *runtime.Scheme
+	// Targets enumerates the resources matched by a profile.
+	Targets *target.Lister
+	// MaxConcurrentReconciles bounds the full profile evaluations running at
+	// once, ObjectWorkers the single object evaluations. Both default to 1.
+	MaxConcurrentReconciles int
+	ObjectWorkers           int
+
+	index   *ProfileIndex
+	locks   sync.Map
+	cache   cache.Cache
+	objects controller.TypedController[ObjectRequest]
+	watchMu sync.Mutex
+	watched map[schema.GroupKind]bool
+	// History receives report state transitions, nil disables archiving.
+	History history.Sink
+	// Ownership resolves the owners recorded on reports, nil disables it.
+	Ownership *ownership.Resolver
 }
 
 // +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyexceptions,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods;configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -58,136 +94,434 @@ func (r *PolicyProfileReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	var profile watchdogv1alpha1.PolicyProfile
 	if err := r.Get(ctx, req.NamespacedName, &profile); err != nil {
+		if apierrors.IsNotFound(err) && r.index != nil {
+			r.index.Remove(req.NamespacedName)
+		}
 		l.Error(err, "unable to fetch PolicyProfile")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if !profile.DeletionTimestamp.IsZero() {
+		if r.index != nil {
+			r.index.Remove(req.NamespacedName)
+		}
+		return ctrl.Result{}, r.finalizeProfile(ctx, &profile)
+	}
+
+	if requested, err := r.clearEvaluateNow(ctx, &profile); err != nil {
+		return ctrl.Result{}, err
+	} else if requested {
+		l.Info("On-demand evaluation requested")
+	}
+
+	resolved, err := r.resolveTemplate(ctx, &profile)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if resolved == nil {
+		// Evaluated again once the template or the profile is fixed
+		if err := r.Status().Update(ctx, &profile); err != nil {
+			l.Error(err, "unable to update PolicyProfile status")
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Step 1: Derive GroupVersionResource for resource kind
-	kind := profile.Spec.Match.Kind
-	nsPattern := profile.Spec.Match.Namespace
-	desiredPolicy := profile.Spec.Policy
+	gvr, err := r.Targets.Resolve(profile.Spec.Match.Kind)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.indexProfile(ctx, &profile, gvr)
 
-	gvr := schema.GroupVersionResource{
-		Group:    "networking.k8s.io",
-		Version:  "v1",
-		Resource: "networkpolicies", // TODO: map kind → resource name dynamically
+	unlock := r.lockProfile(req.NamespacedName)
+	defer unlock()
+
+	// Existing reports of this profile, keyed by the resource they cover
+	existing, err := r.listProfileReports(ctx, &profile)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
+	exceptions := &watchdogv1alpha1.PolicyExceptionList{}
+	if err := r.List(ctx, exceptions); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed listing PolicyExceptions: %w", err)
+	}
+	ev := r.newEvaluation(&profile, resolved, targetKind(&profile, gvr), existing, exceptions.Items)
 
-	// Step 2: List all matching resources
-	resList, err := r.DynClnt.Resource(gvr).Namespace("").List(ctx, metav1.ListOptions{})
+	// Step 2 and 3: Detect drift on every matched resource. Labels only need
+	// the metadata, the checks need whole resources; either is paged through
+	// rather than listed at once.
+	if ev.needsObjects() {
+		err = r.Targets.EachObject(ctx, gvr, listNamespace(&profile), func(obj *unstructured.Unstructured) error {
+			return ev.evaluateObject(ctx, obj)
+		})
+	} else {
+		err = r.Targets.Each(ctx, gvr, listNamespace(&profile), func(item *metav1.PartialObjectMetadata) error {
+			return ev.evaluate(ctx, item, item)
+		})
+	}
 	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed listing target resources: %w", err)
+		return ctrl.Result{}, err
 	}
 
-	for _, item := range resList.Items {
-		if !matchNamespace(item.GetNamespace(), nsPattern) {
-			continue
-		}
+	// Step 4: Resolve reports whose resource no longer drifts or is gone
+	ev.resolveStale(ctx)
 
-		// Step 3: Drift detection
-		labels := item.GetLabels()
-		if drift := detectDrift(labels, desiredPolicy); len(drift) > 0 {
-			l.Info("Policy drift detected", "resource", item.GetName(), "namespace", item.GetNamespace(), "drift", drift)
-
-			// Deduplication: check if a report for this resource/profile/drift already exists
-			reports := &watchdogv1alpha1.PolicyViolationReportList{}
-			err := r.List(ctx, reports, client.InNamespace(item.GetNamespace()))
-			if err == nil {
-				found := false
-				for _, rep := range reports.Items {
-					if rep.Spec.ViolatedResource.Name == item.GetName() &&
-						rep.Spec.ProfileName == profile.Name {
-						// Compare drift maps
-						if len(rep.Spec.Drift) == len(drift) {
-							match := true
-							for k, v := range drift {
-								if rep.Spec.Drift[k] != v {
-									match = false
-									break
-								}
-							}
-							if match {
-								found = true
-								break
-							}
-						}
-					}
-				}
-				if found {
-					l.Info("Duplicate report found, skipping creation")
-					continue // Skip creating duplicate report
-				}
-			}
+	// Step 5: Update status and schedule the next evaluation
+	now := metav1.Now()
+	next, err := nextRun(profile.Spec.Schedule, now.Time)
+	setScheduleCondition(&profile, err)
+	if err != nil {
+		// Evaluated again at the default interval until the schedule is fixed
+		l.Error(err, "unable to schedule next evaluation")
+		retry := now.Add(watchdogv1alpha1.DefaultScheduleInterval)
+		next = &retry
+	}
+	profile.Status.LastChecked = now
+	profile.Status.Namespaces = sortedSummaries(ev.summaries)
+	profile.Status.Preview = ev.preview
+	profile.Status.NextRun = nil
+	if next != nil {
+		profile.Status.NextRun = &metav1.Time{Time: *next}
+	}
+	if err := r.Status().Update(ctx, &profile); err != nil {
+		l.Error(err, "unable to update PolicyProfile status")
+	}
 
-			// Emit PolicyViolationReport
-			report := &watchdogv1alpha1.PolicyViolationReport{
-				ObjectMeta: metav1.ObjectMeta{
-					GenerateName: "violation-",
-					Namespace:    item.GetNamespace(),
-				},
-				Spec: watchdogv1alpha1.PolicyViolationReportSpec{
-					ViolatedResource: struct {
-						Kind      string `json:"kind"`
-						Name      string `json:"name"`
-						Namespace string `json:"namespace"`
-					}{
-						Kind:      kind,
-						Name:      item.GetName(),
-						Namespace: item.GetNamespace(),
-					},
-					ProfileName: profile.Name,
-					Drift:       drift,
-				},
-			}
+	if next == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now.Time)}, nil
+}
 
-			l.Info("Creating PolicyViolationReport", "resource", item.GetName(), "namespace", item.GetNamespace())
-			if err := r.Create(ctx, report); err != nil {
-				l.Error(err, "unable to create PolicyViolationReport")
-			} else {
-				l.Info("Created PolicyViolationReport", "name", report.Name, "namespace", report.Namespace)
+// listProfileReports returns the reports produced by the profile keyed by
+// the namespace and name of the violated resource.
+func (r *PolicyProfileReconciler) listProfileReports(
+	ctx context.Context, profile *watchdogv1alpha1.PolicyProfile,
+) (map[string]*watchdogv1alpha1.PolicyViolationReport, error) {
+	reports, err := r.listLabeledReports(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	byResource := make(map[string]*watchdogv1alpha1.PolicyViolationReport, len(reports.Items))
+	for i := range reports.Items {
+		rep := &reports.Items[i]
+		key := reportKey(rep.Spec.ViolatedResource.Namespace, rep.Spec.ViolatedResource.Name)
+		// A report created just before may be missing from the cache, in which
+		// case a second one gets created. Keep the oldest and drop the others.
+		if other, ok := byResource[key]; ok {
+			if rep.CreationTimestamp.Before(&other.CreationTimestamp) {
+				rep, other = other, rep
+			}
+			byResource[key] = other
+			logf.FromContext(ctx).Info("Deleting duplicate PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+			if err := r.Delete(ctx, rep); client.IgnoreNotFound(err) != nil {
+				return nil, fmt.Errorf("failed deleting duplicate PolicyViolationReport: %w", err)
 			}
+			continue
 		}
+		byResource[key] = rep
 	}
+	return byResource, nil
+}
 
-	// Step 4: Update status
-	profile.Status.LastChecked = metav1.Now()
-	if err := r.Status().Update(ctx, &profile); err != nil {
-		l.Error(err, "unable to update PolicyProfile status")
+// refreshReport records the current drift and ownership on an existing report
+// and reopens it if it had been resolved. The acknowledgement of a reopened
+// report is dropped unless it snoozes the violation for longer: it covered
+// the previous occurrence only.
+func (r *PolicyProfileReconciler) refreshReport(
+	ctx context.Context, rep *watchdogv1alpha1.PolicyViolationReport, drift map[string]string,
+	severity watchdogv1alpha1.Severity, remediation string, owner *watchdogv1alpha1.Ownership,
+) error {
+	now := metav1.Now()
+	driftChanged := !maps.Equal(rep.Spec.Drift, drift)
+	ownerChanged := !equality.Semantic.DeepEqual(rep.Spec.Ownership, owner)
+	ack := rep.Spec.Acknowledgement
+	ackExpired := rep.Status.IsResolved() && ack != nil && (ack.SnoozeUntil == nil || !ack.Active(now.Time))
+	if driftChanged || ownerChanged || ackExpired || rep.Spec.Severity != severity || rep.Spec.Remediation != remediation {
+		rep.Spec.Drift = drift
+		rep.Spec.Severity = severity
+		rep.Spec.Remediation = remediation
+		rep.Spec.Ownership = owner
+		if ackExpired {
+			rep.Spec.Acknowledgement = nil
+		}
+		setTeamLabel(rep, owner)
+		if err := r.Update(ctx, rep); err != nil {
+			return err
+		}
+	}
+
+	if rep.Status.Phase == watchdogv1alpha1.ReportPhaseOpen {
+		if driftChanged {
+			r.recordHistory(ctx, history.EventUpdated, rep)
+		}
+		return nil
+	}
+	// A reopened violation is notified afresh.
+	rep.Status.Phase = watchdogv1alpha1.ReportPhaseOpen
+	rep.Status.OpenedAt = &now
+	rep.Status.ResolvedAt = nil
+	rep.Status.Deliveries = nil
+	rep.Status.Resolutions = nil
+	if err := r.Status().Update(ctx, rep); err != nil {
+		return err
+	}
+	r.recordHistory(ctx, history.EventOpened, rep)
+	return nil
+}
+
+// resolveOwnership returns the ownership of item, nil if it is unknown or
+// resolution is disabled.
+func (r *PolicyProfileReconciler) resolveOwnership(
+	ctx context.Context, item *metav1.PartialObjectMetadata,
+) (*watchdogv1alpha1.Ownership, error) {
+	if r.Ownership == nil {
+		return nil, nil
+	}
+	return r.Ownership.Resolve(ctx, item)
+}
+
+// setTeamLabel labels rep with the team of owner, so that reports can be
+// selected by team. Teams that are not valid label values are not labelled.
+func setTeamLabel(rep *watchdogv1alpha1.PolicyViolationReport, owner *watchdogv1alpha1.Ownership) {
+	if owner == nil || owner.Team == "" || len(validation.IsValidLabelValue(owner.Team)) > 0 {
+		delete(rep.Labels, watchdogv1alpha1.TeamLabel)
+		return
+	}
+	if rep.Labels == nil {
+		rep.Labels = map[string]string{}
+	}
+	rep.Labels[watchdogv1alpha1.TeamLabel] = owner.Team
+}
+
+// suppressReport resolves an open report whose drift is covered by a PolicyException.
+func (r *PolicyProfileReconciler) suppressReport(ctx context.Context, rep *watchdogv1alpha1.PolicyViolationReport) {
+	now := metav1.Now()
+	rep.Status.Phase = watchdogv1alpha1.ReportPhaseResolved
+	rep.Status.ResolvedAt = &now
+	if err := r.Status().Update(ctx, rep); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to suppress PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+		return
+	}
+	r.recordHistory(ctx, history.EventSuppressed, rep)
+}
+
+// recordHistory archives a report state transition. Failures are logged and
+// never block reconciliation.
+func (r *PolicyProfileReconciler) recordHistory(
+	ctx context.Context, eventType history.EventType, rep *watchdogv1alpha1.PolicyViolationReport,
+) {
+	if r.History == nil {
+		return
 	}
+	if err := r.History.Append(ctx, history.NewEvent(eventType, rep)); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to record report history", "event", eventType, "name", rep.Name)
+	}
+}
+
+func sortedSummaries(summaries map[string]*watchdogv1alpha1.NamespaceSummary) []watchdogv1alpha1.NamespaceSummary {
+	out := make([]watchdogv1alpha1.NamespaceSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		out = append(out, *summary)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Namespace < out[j].Namespace })
+	return out
+}
 
-	return ctrl.Result{RequeueAfter: 1 * time.Hour}, nil
+func reportKey(namespace, name string) string {
+	return namespace + "/" + name
 }
 
-// Simple string glob match (basic wildcard support)
-func matchNamespace(actual, pattern string) bool {
-	if strings.HasSuffix(pattern, "*") {
-		return strings.HasPrefix(actual, strings.TrimSuffix(pattern, "*"))
+// finalizeProfile deletes the reports a profile produced outside its own
+// namespace and then releases the profile. Reports in the profile namespace
+// are owned by the profile and left to the garbage collector.
+func (r *PolicyProfileReconciler) finalizeProfile(ctx context.Context, profile *watchdogv1alpha1.PolicyProfile) error {
+	if !controllerutil.ContainsFinalizer(profile, watchdogv1alpha1.ReportCleanupFinalizer) {
+		return nil
+	}
+
+	l := logf.FromContext(ctx)
+
+	reports, err := r.listLabeledReports(ctx, profile)
+	if err != nil {
+		return err
+	}
+
+	for i := range reports.Items {
+		rep := &reports.Items[i]
+		if rep.Namespace == profile.Namespace {
+			continue // owned by the profile
+		}
+		if err := r.Delete(ctx, rep); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed deleting PolicyViolationReport %s/%s: %w", rep.Namespace, rep.Name, err)
+		}
+		l.Info("Deleted PolicyViolationReport of removed profile", "name", rep.Name, "namespace", rep.Namespace)
 	}
-	return actual == pattern
+
+	controllerutil.RemoveFinalizer(profile, watchdogv1alpha1.ReportCleanupFinalizer)
+	return r.Update(ctx, profile)
 }
 
-// Compare desired policy with live labels (can expand later)
-func detectDrift(actualLabels map[string]string, desired map[string]string) map[string]string {
-	drift := map[string]string{}
-	for k, v := range desired {
-		actualVal, exists := actualLabels[k]
-		if !exists || actualVal != v {
-			drift[k] = fmt.Sprintf("Expected: %s, Got: %s", v, actualVal)
+// listLabeledReports lists the reports labeled with the profile, after
+// labeling those created before reports carried the labels.
+func (r *PolicyProfileReconciler) listLabeledReports(
+	ctx context.Context, profile *watchdogv1alpha1.PolicyProfile,
+) (*watchdogv1alpha1.PolicyViolationReportList, error) {
+	adopted, err := r.adoptLegacyReports(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	reports := &watchdogv1alpha1.PolicyViolationReportList{}
+	if err := r.List(ctx, reports, client.MatchingLabels{
+		watchdogv1alpha1.ProfileNameLabel:      profile.Name,
+		watchdogv1alpha1.ProfileNamespaceLabel: profile.Namespace,
+	}); err != nil {
+		return nil, fmt.Errorf("failed listing reports of PolicyProfile: %w", err)
+	}
+	// The cache may not have seen the new labels yet
+	for _, rep := range adopted {
+		if !slices.ContainsFunc(reports.Items, func(item watchdogv1alpha1.PolicyViolationReport) bool {
+			return item.UID == rep.UID
+		}) {
+			reports.Items = append(reports.Items, rep)
 		}
 	}
-	return drift
+	return reports, nil
+}
+
+// adoptLegacyReports labels the reports of the profile that carry no profile
+// label, found by their spec, and makes those in the profile namespace owned
+// by it. Reports recording no profile namespace are taken to be of the
+// profile if it matches the namespace of their resource.
+func (r *PolicyProfileReconciler) adoptLegacyReports(
+	ctx context.Context, profile *watchdogv1alpha1.PolicyProfile,
+) ([]watchdogv1alpha1.PolicyViolationReport, error) {
+	unlabeled, err := labels.NewRequirement(watchdogv1alpha1.ProfileNameLabel, selection.DoesNotExist, nil)
+	if err != nil {
+		return nil, err
+	}
+	reports := &watchdogv1alpha1.PolicyViolationReportList{}
+	if err := r.List(ctx, reports, client.MatchingLabelsSelector{Selector: labels.NewSelector().Add(*unlabeled)}); err != nil {
+		return nil, fmt.Errorf("failed listing unlabeled reports: %w", err)
+	}
+
+	var adopted []watchdogv1alpha1.PolicyViolationReport
+	for i := range reports.Items {
+		rep := &reports.Items[i]
+		if rep.Spec.ProfileName != profile.Name {
+			continue
+		}
+		switch rep.Spec.ProfileNamespace {
+		case profile.Namespace:
+		case "":
+			if !profile.MatchesNamespace(rep.Spec.ViolatedResource.Namespace) {
+				continue
+			}
+		default:
+			continue
+		}
+
+		base := rep.DeepCopy()
+		rep.Spec.ProfileNamespace = profile.Namespace
+		if rep.Labels == nil {
+			rep.Labels = map[string]string{}
+		}
+		rep.Labels[watchdogv1alpha1.ProfileNameLabel] = profile.Name
+		rep.Labels[watchdogv1alpha1.ProfileNamespaceLabel] = profile.Namespace
+		if rep.Namespace == profile.Namespace {
+			if err := controllerutil.SetControllerReference(profile, rep, r.Scheme); err != nil {
+				return nil, fmt.Errorf("failed setting owner reference on report: %w", err)
+			}
+		} else if !controllerutil.ContainsFinalizer(profile, watchdogv1alpha1.ReportCleanupFinalizer) {
+			controllerutil.AddFinalizer(profile, watchdogv1alpha1.ReportCleanupFinalizer)
+			if err := r.Update(ctx, profile); err != nil {
+				return nil, fmt.Errorf("failed adding finalizer to PolicyProfile: %w", err)
+			}
+		}
+		if err := r.Patch(ctx, rep, client.MergeFrom(base)); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed labeling PolicyViolationReport %s/%s: %w", rep.Namespace, rep.Name, err)
+		}
+		logf.FromContext(ctx).Info("Labeled legacy PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+		adopted = append(adopted, *rep)
+	}
+	return adopted, nil
+}
+
+// listNamespace narrows the list call to a single namespace when the pattern
+// of the profile has no wildcard. Namespaces themselves are cluster-scoped.
+func listNamespace(profile *watchdogv1alpha1.PolicyProfile) string {
+	pattern := profile.Spec.Match.Namespace
+	if strings.HasSuffix(pattern, "*") || profile.Spec.Match.Kind == "Namespace" {
+		return ""
+	}
+	return pattern
+}
+
+// targetKind returns the kind of the resources the profile matches, served
+// as gvr.
+func targetKind(profile *watchdogv1alpha1.PolicyProfile, gvr schema.GroupVersionResource) schema.GroupVersionKind {
+	kind, _, _ := strings.Cut(profile.Spec.Match.Kind, ".")
+	return gvr.GroupVersion().WithKind(kind)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *PolicyProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
-	r.DynClnt = dynamic.NewForConfigOrDie(mgr.GetConfig())
+	if r.Targets == nil {
+		targets, err := target.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			return err
+		}
+		r.Targets = targets
+	}
 	// return ctrl.NewControllerManagedBy(mgr).
 	//     For(&watchdogv1alpha1.PolicyProfile{}).
 	//     Complete(r)
 
+	// Continuous profiles are evaluated object by object through watches
+	// added on demand, see ensureWatch.
+	r.index = NewProfileIndex()
+	r.cache = mgr.GetCache()
+	r.watched = map[schema.GroupKind]bool{}
+	objects, err := controller.NewTyped("policyprofile-object", mgr, controller.TypedOptions[ObjectRequest]{
+		Reconciler:              reconcile.TypedFunc[ObjectRequest](r.reconcileObject),
+		MaxConcurrentReconciles: r.ObjectWorkers,
+	})
+	if err != nil {
+		return err
+	}
+	r.objects = objects
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&watchdogv1alpha1.PolicyProfile{}).
+		For(&watchdogv1alpha1.PolicyProfile{}, builder.WithPredicates(profileTriggers())).
+		Watches(&watchdogv1alpha1.PolicyException{}, handler.EnqueueRequestsFromMapFunc(r.profilesForException)).
+		Watches(&watchdogv1alpha1.PolicyTemplate{}, handler.EnqueueRequestsFromMapFunc(r.profilesForTemplate)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Named("policyprofile").
 		Complete(r)
 }
+
+// profilesForException re-evaluates the profiles an exception refers to, so
+// that creating or removing it takes effect without waiting for the next run.
+func (r *PolicyProfileReconciler) profilesForException(ctx context.Context, obj client.Object) []reconcile.Request {
+	exc, ok := obj.(*watchdogv1alpha1.PolicyException)
+	if !ok {
+		return nil
+	}
+	profiles := &watchdogv1alpha1.PolicyProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list PolicyProfiles for PolicyException", "name", exc.Name)
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, p := range profiles.Items {
+		if key := client.ObjectKeyFromObject(&p); key == exc.Profile() {
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
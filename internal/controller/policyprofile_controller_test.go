@@ -22,6 +22,10 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -31,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/target"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -66,14 +71,14 @@ var _ = Describe("PolicyProfile Controller", func() {
 	}
 
 	// Helper to create NetworkPolicy
-	createNetworkPolicy := func(name string, labels map[string]string) {
+	createNetworkPolicyIn := func(namespace, name string, labels map[string]string) {
 		np := &unstructured.Unstructured{
 			Object: map[string]interface{}{
 				"apiVersion": "networking.k8s.io/v1",
 				"kind":       "NetworkPolicy",
 				"metadata": map[string]interface{}{
 					"name":      name,
-					"namespace": ns,
+					"namespace": namespace,
 					"labels":    labels,
 				},
 				"spec": map[string]interface{}{
@@ -87,9 +92,12 @@ var _ = Describe("PolicyProfile Controller", func() {
 			Version:  "v1",
 			Resource: "networkpolicies",
 		}
-		_, err := dynamic.NewForConfigOrDie(cfg).Resource(gvr).Namespace(ns).Create(ctx, np, metav1.CreateOptions{})
+		_, err := dynamic.NewForConfigOrDie(cfg).Resource(gvr).Namespace(namespace).Create(ctx, np, metav1.CreateOptions{})
 		Expect(err).NotTo(HaveOccurred())
 	}
+	createNetworkPolicy := func(name string, labels map[string]string) {
+		createNetworkPolicyIn(ns, name, labels)
+	}
 
 	// Helper to get violation reports
 	getReports := func() []watchdogv1alpha1.PolicyViolationReport {
@@ -101,7 +109,10 @@ var _ = Describe("PolicyProfile Controller", func() {
 	AfterEach(func() {
 		// Cleanup PolicyProfile
 		profile := &watchdogv1alpha1.PolicyProfile{}
-		_ = k8sClient.Get(ctx, typeNamespacedName, profile)
+		if err := k8sClient.Get(ctx, typeNamespacedName, profile); err == nil && len(profile.Finalizers) > 0 {
+			profile.Finalizers = nil
+			_ = k8sClient.Update(ctx, profile)
+		}
 		_ = k8sClient.Delete(ctx, profile)
 		// Cleanup PolicyViolationReports
 		var reports watchdogv1alpha1.PolicyViolationReportList
@@ -125,7 +136,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
 		createNetworkPolicy("np-drift", map[string]string{"foo": "not-bar"})
@@ -143,7 +154,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
 		createNetworkPolicy("np-match", map[string]string{"foo": "bar"})
@@ -156,7 +167,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
 		// No NetworkPolicy created
@@ -169,7 +180,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
 		createNetworkPolicy("np-drift", map[string]string{"foo": "not-bar"})
@@ -186,7 +197,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
 		createNetworkPolicy("np-drift1", map[string]string{"foo": "not-bar"})
@@ -201,7 +212,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		// Create a profile with namespace pattern "def*"
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", "def*")
@@ -215,7 +226,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{}, "NetworkPolicy", ns)
 		createNetworkPolicy("np-any", map[string]string{"foo": "bar"})
@@ -228,7 +239,7 @@ var _ = Describe("PolicyProfile Controller", func() {
 		controllerReconciler := &PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  k8sClient.Scheme(),
-			DynClnt: dynamic.NewForConfigOrDie(cfg),
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
 		createNetworkPolicy("np-empty-labels", map[string]string{})
@@ -236,4 +247,436 @@ var _ = Describe("PolicyProfile Controller", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Eventually(func() int { return len(getReports()) }, 5*time.Second).Should(Equal(1))
 	})
+
+	It("should resolve the report once the drift is fixed and reopen it when it returns", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		gvr := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+		setLabel := func(value string) {
+			np, err := dynamic.NewForConfigOrDie(cfg).Resource(gvr).Namespace(ns).Get(ctx, "np-flip", metav1.GetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			np.SetLabels(map[string]string{"foo": value})
+			_, err = dynamic.NewForConfigOrDie(cfg).Resource(gvr).Namespace(ns).Update(ctx, np, metav1.UpdateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		createNetworkPolicy("np-flip", map[string]string{"foo": "not-bar"})
+		reconcileOnce()
+		Expect(getReports()).To(HaveLen(1))
+		opened := getReports()[0]
+		Expect(opened.Status.Phase).To(Equal(watchdogv1alpha1.ReportPhaseOpen))
+		Expect(opened.Status.OpenedAt).NotTo(BeNil())
+		opened.Status.Deliveries = []watchdogv1alpha1.DeliveryStatus{{
+			Channel: "ops", State: watchdogv1alpha1.DeliverySent, Attempts: 1,
+		}}
+		Expect(k8sClient.Status().Update(ctx, &opened)).To(Succeed())
+
+		setLabel("bar")
+		reconcileOnce()
+		reports := getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Status.IsResolved()).To(BeTrue())
+		Expect(reports[0].Status.ResolvedAt).NotTo(BeNil())
+		resolved := reports[0]
+		resolved.Status.Resolutions = []watchdogv1alpha1.DeliveryStatus{{
+			Channel: "ops", State: watchdogv1alpha1.DeliverySent, Attempts: 1,
+		}}
+		Expect(k8sClient.Status().Update(ctx, &resolved)).To(Succeed())
+		resolved.Spec.Acknowledgement = &watchdogv1alpha1.Acknowledgement{By: "alice", At: metav1.Now()}
+		Expect(k8sClient.Update(ctx, &resolved)).To(Succeed())
+
+		setLabel("baz")
+		reconcileOnce()
+		reports = getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Status.Phase).To(Equal(watchdogv1alpha1.ReportPhaseOpen))
+		Expect(reports[0].Spec.Drift["foo"]).To(ContainSubstring("baz"))
+		Expect(reports[0].Status.Deliveries).To(BeEmpty(), "a reopened violation is notified afresh")
+		Expect(reports[0].Status.Resolutions).To(BeEmpty())
+		Expect(reports[0].Spec.Acknowledgement).To(BeNil(), "the acknowledgement covered the previous occurrence")
+		Expect(reports[0].Status.OpenedAt.Time).NotTo(BeTemporally("<", opened.Status.OpenedAt.Time))
+	})
+
+	It("should set the profile as controller owner of reports in its namespace", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		createNetworkPolicy("np-owned", map[string]string{"foo": "not-bar"})
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() int { return len(getReports()) }, 5*time.Second).Should(Equal(1))
+		report := getReports()[0]
+		Expect(report.Labels).To(HaveKeyWithValue(watchdogv1alpha1.ProfileNameLabel, resourceName))
+		Expect(report.Spec.ProfileNamespace).To(Equal(ns))
+		Expect(report.OwnerReferences).To(HaveLen(1))
+		Expect(report.OwnerReferences[0].UID).To(Equal(profile.UID))
+	})
+
+	It("should label and own the reports created before reports were labeled", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		createNetworkPolicy("np-legacy", map[string]string{"foo": "not-bar"})
+		legacy := &watchdogv1alpha1.PolicyViolationReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy-report", Namespace: ns},
+			Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+				ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{Kind: "NetworkPolicy", Name: "np-legacy", Namespace: ns},
+				ProfileName:      resourceName,
+				Drift:            map[string]string{"foo": "Expected: bar, Got: not-bar"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, legacy)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Consistently(func() int { return len(getReports()) }, time.Second).Should(Equal(1), "the legacy report is reused")
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(legacy), legacy)).To(Succeed())
+		Expect(legacy.Labels).To(HaveKeyWithValue(watchdogv1alpha1.ProfileNameLabel, resourceName))
+		Expect(legacy.Labels).To(HaveKeyWithValue(watchdogv1alpha1.ProfileNamespaceLabel, ns))
+		Expect(legacy.Spec.ProfileNamespace).To(Equal(ns))
+		Expect(legacy.OwnerReferences).To(HaveLen(1))
+		Expect(legacy.OwnerReferences[0].UID).To(Equal(profile.UID))
+	})
+
+	It("should delete cross-namespace reports when the profile is deleted", func() {
+		const otherNS = "policy-other"
+		_ = k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: otherNS}})
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", otherNS)
+		createNetworkPolicyIn(otherNS, "np-remote", map[string]string{"foo": "not-bar"})
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		remoteReports := func() int {
+			var reports watchdogv1alpha1.PolicyViolationReportList
+			_ = k8sClient.List(ctx, &reports, client.InNamespace(otherNS))
+			return len(reports.Items)
+		}
+		Eventually(remoteReports, 5*time.Second).Should(Equal(1))
+
+		profile := &watchdogv1alpha1.PolicyProfile{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Finalizers).To(ContainElement(watchdogv1alpha1.ReportCleanupFinalizer))
+
+		local := &watchdogv1alpha1.PolicyViolationReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "local-report", Namespace: ns, Labels: map[string]string{
+				watchdogv1alpha1.ProfileNameLabel:      resourceName,
+				watchdogv1alpha1.ProfileNamespaceLabel: ns,
+			}},
+			Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+				ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{Kind: "NetworkPolicy", Name: "np-local", Namespace: ns},
+				ProfileName:      resourceName,
+				ProfileNamespace: ns,
+				Drift:            map[string]string{"foo": "Expected: bar, Got: "},
+			},
+		}
+		Expect(k8sClient.Create(ctx, local)).To(Succeed())
+		DeferCleanup(func() { _ = k8sClient.Delete(ctx, local) })
+
+		Expect(k8sClient.Delete(ctx, profile)).To(Succeed())
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(remoteReports, 5*time.Second).Should(Equal(0))
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(local), local)).To(Succeed(),
+			"reports in the profile namespace are left to the garbage collector")
+		Eventually(func() bool {
+			return apierrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &watchdogv1alpha1.PolicyProfile{}))
+		}, 5*time.Second).Should(BeTrue())
+
+		gvr := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+		_ = dynamic.NewForConfigOrDie(cfg).Resource(gvr).Namespace(otherNS).Delete(ctx, "np-remote", metav1.DeleteOptions{})
+	})
+
+	It("should suppress violations covered by a PolicyException and copy the severity", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		profile.Spec.Severity = watchdogv1alpha1.SeverityHigh
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		createNetworkPolicy("np-excepted", map[string]string{"foo": "not-bar"})
+		reconcileOnce()
+		Expect(getReports()).To(HaveLen(1))
+		Expect(getReports()[0].Spec.Severity).To(Equal(watchdogv1alpha1.SeverityHigh))
+
+		exception := &watchdogv1alpha1.PolicyException{
+			ObjectMeta: metav1.ObjectMeta{Name: "np-excepted", Namespace: ns},
+			Spec: watchdogv1alpha1.PolicyExceptionSpec{
+				ProfileName: resourceName,
+				Resource:    watchdogv1alpha1.ExceptionResourceSpec{Kind: "NetworkPolicy", Name: "np-excepted"},
+				Reason:      "legacy workload",
+			},
+		}
+		Expect(k8sClient.Create(ctx, exception)).To(Succeed())
+		DeferCleanup(func() { _ = k8sClient.Delete(ctx, exception) })
+
+		reconcileOnce()
+		reports := getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Status.IsResolved()).To(BeTrue())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Status.Namespaces).To(ConsistOf(
+			watchdogv1alpha1.NamespaceSummary{Namespace: ns, Matched: 1, Violating: 0},
+		))
+	})
+
+	It("should only summarize the violations of a previewed profile", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		profile.Spec.Mode = watchdogv1alpha1.ProfileModePreview
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		createNetworkPolicy("np-ok", map[string]string{"foo": "bar"})
+		createNetworkPolicy("np-drift", map[string]string{"foo": "not-bar"})
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Consistently(func() int { return len(getReports()) }, 2*time.Second).Should(Equal(0))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Status.Preview).To(Equal(&watchdogv1alpha1.PreviewSummary{
+			Matched:      2,
+			WouldViolate: 1,
+			Samples: []watchdogv1alpha1.PreviewSample{
+				{Namespace: ns, Name: "np-drift", Drift: map[string]string{"foo": "Expected: bar, Got: not-bar"}},
+			},
+		}))
+
+		By("enforcing the profile")
+		profile.Spec.Mode = watchdogv1alpha1.ProfileModeEnforce
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(getReports()).To(HaveLen(1))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Status.Preview).To(BeNil())
+	})
+
+	It("should enforce the rules of the template of a profile", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		reconcileOnce := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		templateResolved := func() *metav1.Condition {
+			profile := &watchdogv1alpha1.PolicyProfile{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+			return meta.FindStatusCondition(profile.Status.Conditions, watchdogv1alpha1.ConditionTemplateResolved)
+		}
+
+		profile := createPolicyProfile(nil, "NetworkPolicy", ns)
+		profile.Spec.Template = &watchdogv1alpha1.TemplateReference{
+			Name:       "required-labels",
+			Parameters: map[string]apiextensionsv1.JSON{"labels": {Raw: []byte(`["owner"]`)}},
+		}
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		createNetworkPolicy("np-unowned", map[string]string{"app": "web"})
+
+		By("waiting for the template")
+		reconcileOnce()
+		Expect(getReports()).To(BeEmpty())
+		Expect(templateResolved()).To(HaveField("Reason", "TemplateNotFound"))
+
+		template := &watchdogv1alpha1.PolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "required-labels"},
+			Spec: watchdogv1alpha1.PolicyTemplateSpec{
+				Parameters: []watchdogv1alpha1.TemplateParameter{
+					{Name: "labels", Type: watchdogv1alpha1.ParameterStringList, Required: true},
+				},
+				Labels: []watchdogv1alpha1.LabelRule{{KeysFrom: "labels"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, template)).To(Succeed())
+		DeferCleanup(func() { _ = k8sClient.Delete(ctx, template) })
+
+		reconcileOnce()
+		Expect(templateResolved()).To(HaveField("Status", metav1.ConditionTrue))
+		reports := getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Spec.Drift).To(Equal(map[string]string{"owner": "Expected: any value, Got: "}))
+
+		By("rejecting parameters the template does not declare")
+		template.Spec.Parameters[0].Name = "keys"
+		template.Spec.Labels[0].KeysFrom = "keys"
+		Expect(k8sClient.Update(ctx, template)).To(Succeed())
+		reconcileOnce()
+		Expect(templateResolved()).To(HaveField("Reason", "InvalidParameters"))
+	})
+
+	It("should run the checks of a profile and copy its remediation", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(nil, "Pod", ns)
+		profile.Spec.Checks = []watchdogv1alpha1.Check{watchdogv1alpha1.CheckNoHostPath}
+		profile.Spec.Remediation = "Use a persistent volume instead."
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod-host-path", Namespace: ns},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "agent", Image: "agent:1.0"}},
+				Volumes: []corev1.Volume{{
+					Name:         "logs",
+					VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		DeferCleanup(func() { _ = k8sClient.Delete(ctx, pod) })
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		reports := getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Spec.Drift).To(Equal(map[string]string{"NoHostPath": "volume logs mounts host path /var/log"}))
+		Expect(reports[0].Spec.Remediation).To(Equal("Use a persistent volume instead."))
+	})
+
+	It("should report the next run of scheduled profiles and clear on-demand requests", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		profile.Annotations = map[string]string{watchdogv1alpha1.EvaluateNowAnnotation: "true"}
+		profile.Spec.Schedule = &watchdogv1alpha1.ScheduleSpec{
+			Mode:     watchdogv1alpha1.ScheduleInterval,
+			Interval: &metav1.Duration{Duration: 10 * time.Minute},
+		}
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+
+		result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", 10*time.Minute, time.Second))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Annotations).NotTo(HaveKey(watchdogv1alpha1.EvaluateNowAnnotation))
+		Expect(profile.Status.NextRun).NotTo(BeNil())
+
+		profile.Spec.Schedule = &watchdogv1alpha1.ScheduleSpec{Mode: watchdogv1alpha1.ScheduleContinuous}
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		result, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Status.NextRun).To(BeNil())
+	})
+
+	It("should flag an invalid cron schedule and fall back to the default interval", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		profile.Spec.Schedule = &watchdogv1alpha1.ScheduleSpec{Mode: watchdogv1alpha1.ScheduleCron, Cron: "every day"}
+		Expect(k8sClient.Update(ctx, profile)).NotTo(Succeed(), "the schedule is checked at admission")
+
+		profile.Spec.Schedule.Cron = "99 * * * *"
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically("~", watchdogv1alpha1.DefaultScheduleInterval, time.Second))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(profile.Status.NextRun).NotTo(BeNil())
+		condition := meta.FindStatusCondition(profile.Status.Conditions, watchdogv1alpha1.ConditionScheduleValid)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("InvalidCron"))
+
+		profile.Spec.Schedule.Cron = "0 2 * * *"
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, profile)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(profile.Status.Conditions, watchdogv1alpha1.ConditionScheduleValid)).To(BeTrue())
+	})
+
+	It("should compute the next run for every schedule mode", func() {
+		now := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+
+		next, err := nextRun(nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*next).To(Equal(now.Add(time.Hour)))
+
+		next, err = nextRun(&watchdogv1alpha1.ScheduleSpec{Mode: watchdogv1alpha1.ScheduleCron, Cron: "0 2 * * *"}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*next).To(Equal(time.Date(2025, 3, 2, 2, 0, 0, 0, time.UTC)))
+
+		next, err = nextRun(&watchdogv1alpha1.ScheduleSpec{Mode: watchdogv1alpha1.ScheduleContinuous}, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(next).To(BeNil())
+
+		_, err = nextRun(&watchdogv1alpha1.ScheduleSpec{Mode: watchdogv1alpha1.ScheduleCron, Cron: "every day"}, now)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should re-evaluate a single object against a continuous profile", func() {
+		controllerReconciler := &PolicyProfileReconciler{
+			Client:  k8sClient,
+			Scheme:  k8sClient.Scheme(),
+			Targets: target.NewForConfigOrDie(cfg),
+		}
+		profile := createPolicyProfile(map[string]string{"foo": "bar"}, "NetworkPolicy", ns)
+		profile.Spec.Schedule = &watchdogv1alpha1.ScheduleSpec{Mode: watchdogv1alpha1.ScheduleContinuous}
+		Expect(k8sClient.Update(ctx, profile)).To(Succeed())
+
+		gvr := schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+		req := ObjectRequest{
+			Profile: typeNamespacedName,
+			Kind:    gvr.GroupVersion().WithKind("NetworkPolicy"),
+			Object:  types.NamespacedName{Namespace: ns, Name: "np-single"},
+		}
+		reconcileObject := func() {
+			_, err := controllerReconciler.reconcileObject(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		createNetworkPolicy("np-single", map[string]string{"foo": "not-bar"})
+		createNetworkPolicy("np-untouched", map[string]string{"foo": "not-bar"})
+		reconcileObject()
+		reports := getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Spec.ViolatedResource.Name).To(Equal("np-single"))
+
+		Expect(dynamic.NewForConfigOrDie(cfg).Resource(gvr).Namespace(ns).
+			Delete(ctx, "np-single", metav1.DeleteOptions{})).To(Succeed())
+		reconcileObject()
+		reports = getReports()
+		Expect(reports).To(HaveLen(1))
+		Expect(reports[0].Status.IsResolved()).To(BeTrue())
+	})
 })
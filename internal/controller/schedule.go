@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// nextRun returns when the profile is due after now, or nil for continuous
+// profiles which only run when a matched resource changes.
+func nextRun(schedule *watchdogv1alpha1.ScheduleSpec, now time.Time) (*time.Time, error) {
+	if schedule == nil {
+		next := now.Add(watchdogv1alpha1.DefaultScheduleInterval)
+		return &next, nil
+	}
+
+	switch schedule.Mode {
+	case watchdogv1alpha1.ScheduleContinuous:
+		return nil, nil
+	case watchdogv1alpha1.ScheduleCron:
+		sched, err := cron.ParseStandard(schedule.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule %q: %w", schedule.Cron, err)
+		}
+		next := sched.Next(now.UTC())
+		return &next, nil
+	default:
+		interval := watchdogv1alpha1.DefaultScheduleInterval
+		if schedule.Interval != nil && schedule.Interval.Duration > 0 {
+			interval = schedule.Interval.Duration
+		}
+		next := now.Add(interval)
+		return &next, nil
+	}
+}
+
+// setScheduleCondition records in the ScheduleValid condition of a Cron
+// profile whether its schedule parsed, err being the failure of nextRun.
+func setScheduleCondition(profile *watchdogv1alpha1.PolicyProfile, err error) {
+	if profile.Spec.Schedule == nil || profile.Spec.Schedule.Mode != watchdogv1alpha1.ScheduleCron {
+		meta.RemoveStatusCondition(&profile.Status.Conditions, watchdogv1alpha1.ConditionScheduleValid)
+		return
+	}
+	condition := metav1.Condition{
+		Type:               watchdogv1alpha1.ConditionScheduleValid,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Valid",
+		ObservedGeneration: profile.Generation,
+	}
+	if err != nil {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "InvalidCron", err.Error()
+	}
+	meta.SetStatusCondition(&profile.Status.Conditions, condition)
+}
+
+// isContinuous reports whether the profile is evaluated on every change of a
+// matched resource. Previews need a full pass to be summarized, so previewed
+// profiles are only evaluated when they change or are asked to.
+func isContinuous(profile *watchdogv1alpha1.PolicyProfile) bool {
+	return profile.Spec.Schedule != nil && profile.Spec.Schedule.Mode == watchdogv1alpha1.ScheduleContinuous &&
+		!profile.Previewing()
+}
+
+// clearEvaluateNow removes the on-demand trigger, reporting whether it was set.
+func (r *PolicyProfileReconciler) clearEvaluateNow(ctx context.Context, profile *watchdogv1alpha1.PolicyProfile) (bool, error) {
+	if _, ok := profile.Annotations[watchdogv1alpha1.EvaluateNowAnnotation]; !ok {
+		return false, nil
+	}
+	patch := client.MergeFrom(profile.DeepCopy())
+	delete(profile.Annotations, watchdogv1alpha1.EvaluateNowAnnotation)
+	if err := r.Patch(ctx, profile, patch); err != nil {
+		return true, fmt.Errorf("failed removing %s annotation: %w", watchdogv1alpha1.EvaluateNowAnnotation, err)
+	}
+	return true, nil
+}
+
+// profileTriggers lets spec changes and on-demand requests through, but not
+// the status updates the controller makes after every evaluation.
+func profileTriggers() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				_, ok := e.ObjectNew.GetAnnotations()[watchdogv1alpha1.EvaluateNowAnnotation]
+				return ok
+			},
+		},
+	)
+}
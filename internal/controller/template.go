@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policytemplates,verbs=get;list;watch
+
+// resolveTemplate returns the profile with the rules of its template, and
+// records in the TemplateResolved condition whether the template resolved.
+// It returns nil when the template is missing or rejects the parameters.
+func (r *PolicyProfileReconciler) resolveTemplate(
+	ctx context.Context, profile *watchdogv1alpha1.PolicyProfile,
+) (*watchdogv1alpha1.PolicyProfile, error) {
+	if profile.Spec.Template == nil {
+		meta.RemoveStatusCondition(&profile.Status.Conditions, watchdogv1alpha1.ConditionTemplateResolved)
+		return profile, nil
+	}
+	condition := metav1.Condition{
+		Type:               watchdogv1alpha1.ConditionTemplateResolved,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Resolved",
+		ObservedGeneration: profile.Generation,
+	}
+	defer func() { meta.SetStatusCondition(&profile.Status.Conditions, condition) }()
+
+	template := &watchdogv1alpha1.PolicyTemplate{}
+	if err := r.Get(ctx, types.NamespacedName{Name: profile.Spec.Template.Name}, template); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed fetching PolicyTemplate: %w", err)
+		}
+		condition.Status, condition.Reason = metav1.ConditionFalse, "TemplateNotFound"
+		condition.Message = fmt.Sprintf("PolicyTemplate %s not found", profile.Spec.Template.Name)
+		return nil, nil
+	}
+	resolved, err := policy.Resolve(profile, template)
+	if err != nil {
+		logf.FromContext(ctx).Info("PolicyTemplate does not resolve", "template", template.Name, "reason", err.Error())
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "InvalidParameters", err.Error()
+		return nil, nil
+	}
+	return resolved, nil
+}
+
+// profilesForTemplate re-evaluates the profiles of a template when it
+// changes, so that they enforce its new rules right away.
+func (r *PolicyProfileReconciler) profilesForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	profiles := &watchdogv1alpha1.PolicyProfileList{}
+	if err := r.List(ctx, profiles); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list PolicyProfiles for PolicyTemplate", "name", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, p := range profiles.Items {
+		if p.Spec.Template != nil && p.Spec.Template.Name == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&p)})
+		}
+	}
+	return requests
+}
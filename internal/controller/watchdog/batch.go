@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/notify"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// flushBatch sends every report waiting in the batch of t as one digest and
+// returns the time until the batch is retried, 0 if it is not. current is the
+// report being reconciled, which is fresher than its cached copy.
+func (r *PolicyViolationReportReconciler) flushBatch(ctx context.Context, t notify.Target,
+	current *v1alpha1.PolicyViolationReport, now time.Time) (time.Duration, error) {
+	log := logf.FromContext(ctx)
+	name := t.Channel.Name()
+
+	var list v1alpha1.PolicyViolationReportList
+	if err := r.List(ctx, &list); err != nil {
+		return 0, fmt.Errorf("failed listing reports: %w", err)
+	}
+
+	var batch []*v1alpha1.PolicyViolationReport
+	var claims []v1alpha1.DeliveryStatus
+	for i := range list.Items {
+		rep := &list.Items[i]
+		if client.ObjectKeyFromObject(rep) == client.ObjectKeyFromObject(current) {
+			rep = current
+		}
+		if !inBatch(rep, name, t.Policy, now) {
+			continue
+		}
+		base := rep.DeepCopy()
+		st := notify.Claim(rep.Status.Delivery(name), name, now)
+		setDelivery(&rep.Status.Deliveries, st)
+		if err := r.Status().Patch(ctx, rep, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+			if apierrors.IsConflict(err) || apierrors.IsNotFound(err) {
+				// Changed since it was cached; it goes with the next batch.
+				continue
+			}
+			return 0, err
+		}
+		batch = append(batch, rep)
+		claims = append(claims, st)
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	d := notify.NewDigest("New policy violations", batch)
+	results := notify.DeliverBatch(ctx, t.Channel, t.Policy, d, notify.BatchKey(name, batch), claims, r.clock())
+	log.Info("Notification batch attempted", "channel", name, "reports", len(batch))
+	for i, rep := range batch {
+		if err := r.recordDeliveries(ctx, rep, deliveries, results[i:i+1]); client.IgnoreNotFound(err) != nil {
+			return 0, err
+		}
+	}
+	return logDelivery(log, results[0], now), nil
+}
+
+// inBatch reports whether rep waits in the batch of the named channel.
+func inBatch(rep *v1alpha1.PolicyViolationReport, channel string, p notify.Policy, now time.Time) bool {
+	if rep.Status.IsResolved() || rep.Spec.Acknowledgement.Active(now) {
+		return false
+	}
+	st := rep.Status.Delivery(channel)
+	if st == nil {
+		return false
+	}
+	switch st.State {
+	case v1alpha1.DeliveryBatched, v1alpha1.DeliveryPending:
+		return true
+	case v1alpha1.DeliverySending:
+		return !now.Before(st.LastAttemptAt.Add(p.Timeout))
+	default:
+		return false
+	}
+}
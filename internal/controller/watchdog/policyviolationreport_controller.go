@@ -17,15 +17,18 @@ limitations under the License.
 package watchdog
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
+	"sync"
+	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/notify"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -33,22 +36,55 @@ import (
 
 var SlackWebhookURL = os.Getenv("SLACK_WEBHOOK_URL")
 
+// DefaultChannelName names the Slack channel configured by SLACK_WEBHOOK_URL
+// in the report status.
+const DefaultChannelName = "default"
+
+// notifiedAnnotation marks the reports notified to the default channel
+// before deliveries were tracked on the status.
+const notifiedAnnotation = "notified"
+
 // PolicyViolationReportReconciler reconciles a PolicyViolationReport object
 type PolicyViolationReportReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// APIReader reads the webhook Secrets of NotificationChannels without
+	// caching every Secret of the cluster, and re-reads reports whose status
+	// patch conflicted. Falls back to Client when nil.
+	APIReader client.Reader
+	// ClusterName identifies the cluster in notification messages.
+	ClusterName string
+	// Governor enforces the rate limits and flap suppression of the channels.
+	// A new one is created when nil.
+	Governor *notify.Governor
+	// Channels keeps the channels built from the NotificationChannels. A new
+	// one is created when nil.
+	Channels *notify.ChannelCache
+
+	governorOnce sync.Once
+	channelsOnce sync.Once
+	now          func() time.Time
+}
+
+// suppression is a notification dropped by the Governor.
+type suppression struct {
+	target  notify.Target
+	verdict notify.Verdict
 }
 
 // +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports/finalizers,verbs=update
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get
 
-// Reconcile is part of the main kubernetes reconciliation loop which aims to
-// move the current state of the cluster closer to the desired state.
-// TODO(user): Modify the Reconcile function to compare the state specified by
-// the PolicyViolationReport object against the actual cluster state, and then
-// perform operations to make the cluster state reflect the state specified by
-// the user.
+// Reconcile delivers the report to every notification channel. Each channel
+// gets one attempt per reconcile, claimed on the report status before it is
+// made and resolved after; failed deliveries are retried with backoff through
+// RequeueAfter until they are sent or dead-lettered.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.21.0/pkg/reconcile
@@ -66,78 +102,302 @@ func (r *PolicyViolationReportReconciler) Reconcile(ctx context.Context, req ctr
 
 	log.Info("Found PolicyViolationReport", "name", report.Name, "annotations", report.Annotations)
 
-	// Avoid duplicate notifications
-	if report.Annotations != nil && report.Annotations["notified"] == "true" {
-		log.Info("Report already notified, skipping")
+	if _, ok := report.Annotations[notifiedAnnotation]; ok {
+		if err := r.migrateNotified(ctx, &report); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if report.Status.IsResolved() {
+		return r.resolve(ctx, &report)
+	}
+	now := r.clock()
+	if ack := report.Spec.Acknowledgement; ack.Active(now) {
+		log.Info("Violation acknowledged, not notifying", "by", ack.By)
+		if ack.SnoozeUntil != nil {
+			return ctrl.Result{RequeueAfter: ack.SnoozeUntil.Sub(now)}, nil
+		}
 		return ctrl.Result{}, nil
 	}
 
-	log.Info("Processing new report", "webhook", SlackWebhookURL)
-
-	if SlackWebhookURL == "" {
-		log.Info("SLACK_WEBHOOK_URL not set, skipping notification")
+	targets, err := r.targets(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(targets) == 0 {
+		log.Info("No notification channels configured, skipping notification")
 		return ctrl.Result{}, nil
 	}
 
-	msg := formatSlackMessage(&report)
+	var due, batches, queued []notify.Target
+	var suppressed []suppression
+	var admitted []notify.Verdict
+	var wait time.Duration
+	for _, t := range targets {
+		prev := report.Status.Delivery(t.Channel.Name())
+		if prev == nil && !t.Routes(&report) {
+			continue
+		}
+		if prev == nil {
+			v := r.governor().Admit(t, &report, now)
+			if v.Suppressed() {
+				suppressed = append(suppressed, suppression{target: t, verdict: v})
+				continue
+			}
+			admitted = append(admitted, v)
+		}
+		switch {
+		case prev == nil && t.Batch > 0:
+			queued = append(queued, t)
+			wait = minWait(wait, t.Batch)
+			continue
+		case prev != nil:
+			ready, after := attemptDue(prev, t.Policy, now)
+			wait = minWait(wait, after)
+			if !ready {
+				continue
+			}
+		}
+		if t.Batch > 0 {
+			batches = append(batches, t)
+		} else {
+			due = append(due, t)
+		}
+	}
 
-	if err := postToSlack(SlackWebhookURL, msg); err != nil {
-		log.Error(err, "failed to send alert")
-		return ctrl.Result{}, err
+	if len(due) > 0 || len(queued) > 0 || len(suppressed) > 0 {
+		// Claim the attempts before making them. The optimistic lock makes the
+		// claim fail on a stale report, which may already record them as sent.
+		base := report.DeepCopy()
+		for _, t := range due {
+			name := t.Channel.Name()
+			setDelivery(&report.Status.Deliveries, notify.Claim(report.Status.Delivery(name), name, now))
+		}
+		for _, t := range queued {
+			setDelivery(&report.Status.Deliveries, v1alpha1.DeliveryStatus{
+				Channel:       t.Channel.Name(),
+				State:         v1alpha1.DeliveryBatched,
+				NextAttemptAt: &metav1.Time{Time: now.Add(t.Batch)},
+			})
+		}
+		for _, s := range suppressed {
+			setDelivery(&report.Status.Deliveries, v1alpha1.DeliveryStatus{
+				Channel: s.target.Channel.Name(),
+				State:   v1alpha1.DeliverySuppressed,
+				Reason:  s.verdict.Message,
+			})
+		}
+		if err := r.Status().Patch(ctx, &report, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+			// The retry decides on these notifications again.
+			for _, v := range admitted {
+				v.Refund(now)
+			}
+			log.Error(err, "failed to claim notification deliveries")
+			return ctrl.Result{}, err
+		}
 	}
 
-	log.Info("Slack alert sent successfully")
+	for _, s := range suppressed {
+		log.Info("Notification suppressed", "channel", s.target.Channel.Name(), "reason", s.verdict.Reason)
+		if s.verdict.Notice != nil {
+			if err := notify.SendNotice(ctx, s.target, s.verdict.Notice, now); err != nil {
+				log.Error(err, "failed to send suppression notice", "channel", s.target.Channel.Name())
+			}
+		}
+	}
 
-	// Mark as notified
-	if report.Annotations == nil {
-		report.Annotations = map[string]string{}
+	if len(due) > 0 {
+		msg, err := notify.LoadMessage(ctx, r.Client, &report, r.ClusterName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		results := make([]v1alpha1.DeliveryStatus, 0, len(due))
+		for _, t := range due {
+			name := t.Channel.Name()
+			st := notify.Deliver(ctx, t.Channel, t.Policy, msg, *report.Status.Delivery(name), r.clock())
+			results = append(results, st)
+			wait = minWait(wait, logDelivery(log, st, now))
+		}
+		if err := r.recordDeliveries(ctx, &report, deliveries, results); err != nil {
+			log.Error(err, "failed to record notification deliveries")
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
 	}
-	report.Annotations["notified"] = "true"
-	if err := r.Update(ctx, &report); err != nil {
-		log.Error(err, "failed to update report with notified annotation")
-		return ctrl.Result{}, err
+
+	for _, t := range batches {
+		next, err := r.flushBatch(ctx, t, &report, now)
+		if err != nil {
+			log.Error(err, "failed to send notification batch", "channel", t.Channel.Name())
+			return ctrl.Result{}, err
+		}
+		wait = minWait(wait, next)
 	}
-	log.Info("Updated report with notified annotation")
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: wait}, nil
 }
 
-func formatSlackMessage(r *v1alpha1.PolicyViolationReport) string {
-	driftDetails, _ := json.MarshalIndent(r.Spec.Drift, "", "  ")
+// attemptDue reports whether a new attempt of the delivery tracked by prev is
+// due, or else the time until it is.
+func attemptDue(prev *v1alpha1.DeliveryStatus, p notify.Policy, now time.Time) (bool, time.Duration) {
+	switch {
+	case prev.Terminal():
+		return false, 0
+	case prev.State == v1alpha1.DeliverySending:
+		// An attempt whose result was never recorded. Wait for it to time
+		// out, then resend with the same idempotency key.
+		if timeout := prev.LastAttemptAt.Add(p.Timeout); now.Before(timeout) {
+			return false, timeout.Sub(now)
+		}
+	case prev.NextAttemptAt != nil && now.Before(prev.NextAttemptAt.Time):
+		return false, prev.NextAttemptAt.Sub(now)
+	}
+	return true, 0
+}
 
-	return fmt.Sprintf(
-		"*🚨 MADMMAS: Policy Violation Detected*\n*Resource:* %s/%s (%s)\n*Policy:* %s\n*Drift:*\n```%s```",
-		r.Spec.ViolatedResource.Namespace,
-		r.Spec.ViolatedResource.Name,
-		r.Spec.ViolatedResource.Kind,
-		r.Spec.ProfileName,
-		string(driftDetails),
-	)
+// logDelivery logs the result of a delivery attempt and returns the time
+// until it is retried, 0 if it is not.
+func logDelivery(log logr.Logger, st v1alpha1.DeliveryStatus, now time.Time) time.Duration {
+	switch st.State {
+	case v1alpha1.DeliverySent:
+		log.Info("Notification sent", "channel", st.Channel, "attempts", st.Attempts, "messageID", st.MessageID)
+	case v1alpha1.DeliveryDeadLetter:
+		log.Info("Notification dead-lettered", "channel", st.Channel, "attempts", st.Attempts, "error", st.LastError)
+	default:
+		log.Info("Notification failed, retrying", "channel", st.Channel, "attempts", st.Attempts,
+			"error", st.LastError, "nextAttemptAt", st.NextAttemptAt.Time)
+		return st.NextAttemptAt.Sub(now)
+	}
+	return 0
 }
 
-func postToSlack(webhook, msg string) error {
-	payload := map[string]string{"text": msg}
-	jsonBody, _ := json.Marshal(payload)
+// recordDeliveries patches the results of the attempts onto the list of the
+// report status, re-reading the report on conflicts so a result is not lost
+// to a concurrent status update.
+func (r *PolicyViolationReportReconciler) recordDeliveries(ctx context.Context,
+	report *v1alpha1.PolicyViolationReport, list statusList, results []v1alpha1.DeliveryStatus) error {
+	key := client.ObjectKeyFromObject(report)
+	stale := false
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if stale {
+			if err := r.reader().Get(ctx, key, report); err != nil {
+				return err
+			}
+		}
+		stale = true
+		base := report.DeepCopy()
+		for _, st := range results {
+			setDelivery(list(&report.Status), st)
+		}
+		return r.Status().Patch(ctx, report, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+	})
+}
 
-	resp, err := http.Post(webhook, "application/json", bytes.NewBuffer(jsonBody))
+// targets returns the configured NotificationChannels plus the Slack webhook
+// set through SLACK_WEBHOOK_URL.
+func (r *PolicyViolationReportReconciler) targets(ctx context.Context) ([]notify.Target, error) {
+	targets, err := r.channels().Load(ctx, r.Client, r.reader())
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if SlackWebhookURL != "" {
+		targets = append(targets, notify.Target{
+			Channel: notify.NewSlack(DefaultChannelName, SlackWebhookURL, notify.SlackOptions{}),
+			Policy:  notify.DefaultPolicy(),
+		})
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			// Log the error but don't fail the function for body close errors
-			// as the main operation (HTTP request) has already completed
-			logf.Log.Error(closeErr, "failed to close response body")
+	return targets, nil
+}
+
+func (r *PolicyViolationReportReconciler) governor() *notify.Governor {
+	r.governorOnce.Do(func() {
+		if r.Governor == nil {
+			r.Governor = notify.NewGovernor()
 		}
-	}()
+	})
+	return r.Governor
+}
 
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("slack webhook error: %s", resp.Status)
-	}
+func (r *PolicyViolationReportReconciler) channels() *notify.ChannelCache {
+	r.channelsOnce.Do(func() {
+		if r.Channels == nil {
+			r.Channels = notify.NewChannelCache()
+		}
+	})
+	return r.Channels
+}
 
+// migrateNotified records the notified annotation of a report as a delivery
+// sent to the default channel, then removes it, so that the report is
+// resolved and reopened like the others.
+func (r *PolicyViolationReportReconciler) migrateNotified(ctx context.Context, report *v1alpha1.PolicyViolationReport) error {
+	if report.Annotations[notifiedAnnotation] == "true" && report.Status.Delivery(DefaultChannelName) == nil {
+		base := report.DeepCopy()
+		setDelivery(&report.Status.Deliveries, v1alpha1.DeliveryStatus{
+			Channel:  DefaultChannelName,
+			State:    v1alpha1.DeliverySent,
+			Attempts: 1,
+			SentAt:   &report.CreationTimestamp,
+		})
+		if err := r.Status().Patch(ctx, report, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+			return fmt.Errorf("failed recording the legacy notification: %w", err)
+		}
+	}
+	base := report.DeepCopy()
+	delete(report.Annotations, notifiedAnnotation)
+	if err := r.Patch(ctx, report, client.MergeFrom(base)); err != nil {
+		return fmt.Errorf("failed removing the %s annotation: %w", notifiedAnnotation, err)
+	}
+	logf.FromContext(ctx).Info("Migrated the legacy notified annotation", "channel", DefaultChannelName)
 	return nil
 }
 
+func (r *PolicyViolationReportReconciler) reader() client.Reader {
+	if r.APIReader != nil {
+		return r.APIReader
+	}
+	return r.Client
+}
+
+func (r *PolicyViolationReportReconciler) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
+
+// statusList selects the deliveries or the resolutions of a report status.
+type statusList func(*v1alpha1.PolicyViolationReportStatus) *[]v1alpha1.DeliveryStatus
+
+func deliveries(s *v1alpha1.PolicyViolationReportStatus) *[]v1alpha1.DeliveryStatus {
+	return &s.Deliveries
+}
+
+func resolutions(s *v1alpha1.PolicyViolationReportStatus) *[]v1alpha1.DeliveryStatus {
+	return &s.Resolutions
+}
+
+func setDelivery(list *[]v1alpha1.DeliveryStatus, st v1alpha1.DeliveryStatus) {
+	for i := range *list {
+		if (*list)[i].Channel == st.Channel {
+			(*list)[i] = st
+			return
+		}
+	}
+	*list = append(*list, st)
+}
+
+func minWait(cur, d time.Duration) time.Duration {
+	if d == 0 {
+		return cur
+	}
+	if d < time.Second {
+		d = time.Second
+	}
+	if cur == 0 || d < cur {
+		return d
+	}
+	return cur
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *PolicyViolationReportReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -18,16 +18,25 @@ package watchdog
 
 import (
 	"context"
+	"io"
 
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
 
 	"github.com/madmmas/gokubedog/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -40,9 +49,16 @@ func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 var testScheme = runtime.NewScheme()
 var _ = func() bool {
 	_ = v1alpha1.AddToScheme(testScheme)
+	_ = corev1.AddToScheme(testScheme)
 	return true
 }()
 
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(testScheme).
+		WithStatusSubresource(&v1alpha1.PolicyViolationReport{}).
+		WithObjects(objs...).Build()
+}
+
 var _ = Describe("PolicyViolationReport Controller", func() {
 	Context("When reconciling a resource", func() {
 
@@ -53,8 +69,8 @@ var _ = Describe("PolicyViolationReport Controller", func() {
 		})
 	})
 
-	It("should set notified annotation after reconciliation", func() {
-		cl := fake.NewClientBuilder().WithScheme(testScheme).Build()
+	It("should record the delivery on the report status", func() {
+		cl := newFakeClient()
 		r := &PolicyViolationReportReconciler{Client: cl}
 		report := &v1alpha1.PolicyViolationReport{
 			ObjectMeta: metav1.ObjectMeta{
@@ -89,11 +105,25 @@ var _ = Describe("PolicyViolationReport Controller", func() {
 		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
 		Expect(err).NotTo(HaveOccurred())
 		_ = cl.Get(context.Background(), client.ObjectKeyFromObject(report), report)
-		Expect(report.Annotations).To(HaveKeyWithValue("notified", "true"))
+		Expect(report.Annotations).NotTo(HaveKey("notified"))
+		st := report.Status.Delivery(DefaultChannelName)
+		Expect(st).NotTo(BeNil())
+		Expect(st.State).To(Equal(v1alpha1.DeliverySent))
+		Expect(st.Attempts).To(Equal(int32(1)))
+		Expect(st.SentAt).NotTo(BeNil())
 	})
 
-	It("should not send notification if already notified", func() {
-		cl := fake.NewClientBuilder().WithScheme(testScheme).Build()
+	It("should record the legacy notified annotation as a sent delivery", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		oldWebhook := SlackWebhookURL
+		SlackWebhookURL = srv.URL
+		defer func() { SlackWebhookURL = oldWebhook }()
+
+		cl := newFakeClient()
 		r := &PolicyViolationReportReconciler{Client: cl}
 		report := &v1alpha1.PolicyViolationReport{
 			ObjectMeta: metav1.ObjectMeta{
@@ -115,11 +145,20 @@ var _ = Describe("PolicyViolationReport Controller", func() {
 		_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
 		Expect(err).NotTo(HaveOccurred())
 		_ = cl.Get(context.Background(), client.ObjectKeyFromObject(report), report)
-		Expect(report.Annotations).To(HaveKeyWithValue("notified", "true"))
+		Expect(report.Annotations).NotTo(HaveKey("notified"))
+		Expect(report.Status.Delivery(DefaultChannelName).State).To(Equal(v1alpha1.DeliverySent))
+		Expect(calls.Load()).To(BeZero())
+
+		By("notifying it again once it reopens")
+		report.Status.Deliveries = nil
+		Expect(cl.Status().Update(context.Background(), report)).To(Succeed())
+		_, err = r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls.Load()).To(Equal(int32(1)))
 	})
 
 	It("should not panic or send notification if webhook is missing", func() {
-		cl := fake.NewClientBuilder().WithScheme(testScheme).Build()
+		cl := newFakeClient()
 		r := &PolicyViolationReportReconciler{Client: cl}
 		report := &v1alpha1.PolicyViolationReport{
 			ObjectMeta: metav1.ObjectMeta{
@@ -144,3 +183,429 @@ var _ = Describe("PolicyViolationReport Controller", func() {
 		Expect(report.Annotations).To(BeNil())
 	})
 })
+
+var _ = Describe("PolicyViolationReport notification delivery", func() {
+	var (
+		ctx    context.Context
+		report *v1alpha1.PolicyViolationReport
+		now    time.Time
+	)
+
+	channel := func(url string, maxAttempts int32) []client.Object {
+		return []client.Object{
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "gokubedog-system"},
+				Data:       map[string][]byte{"url": []byte(url)},
+			},
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "ops"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type: v1alpha1.ChannelTypeSlack,
+					Slack: &v1alpha1.SlackChannelSpec{WebhookURLSecretRef: &v1alpha1.SecretKeySelector{
+						Name: "slack", Namespace: "gokubedog-system", Key: "url",
+					}},
+					Retry: &v1alpha1.RetrySpec{
+						MaxAttempts:    maxAttempts,
+						InitialBackoff: &metav1.Duration{Duration: 10 * time.Second},
+						MaxBackoff:     &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+		}
+	}
+
+	reconcileAt := func(cl client.Client, at time.Time) ctrl.Result {
+		r := &PolicyViolationReportReconciler{Client: cl, now: func() time.Time { return at }}
+		res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(report), report)).To(Succeed())
+		return res
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		report = &v1alpha1.PolicyViolationReport{
+			ObjectMeta: metav1.ObjectMeta{Name: "delivery", Namespace: "default"},
+			Spec: v1alpha1.PolicyViolationReportSpec{
+				ProfileName: "test-profile",
+				Drift:       map[string]string{"foo": "bar"},
+				ViolatedResource: v1alpha1.ViolatedResourceSpec{
+					Kind: "NetworkPolicy", Name: "np-drift", Namespace: "default",
+				},
+			},
+		}
+	})
+
+	It("retries failed deliveries with backoff until they are sent", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+		cl := newFakeClient(append(channel(srv.URL, 3), report)...)
+
+		res := reconcileAt(cl, now)
+		Expect(res.RequeueAfter).To(Equal(10 * time.Second))
+		st := report.Status.Delivery("ops")
+		Expect(st).NotTo(BeNil())
+		Expect(st.State).To(Equal(v1alpha1.DeliveryPending))
+		Expect(st.Attempts).To(Equal(int32(1)))
+		Expect(st.LastError).To(ContainSubstring("502"))
+
+		By("not attempting again before the backoff elapsed")
+		res = reconcileAt(cl, now.Add(5*time.Second))
+		Expect(res.RequeueAfter).To(Equal(5 * time.Second))
+		Expect(calls.Load()).To(Equal(int32(1)))
+
+		res = reconcileAt(cl, now.Add(10*time.Second))
+		Expect(res.RequeueAfter).To(BeZero())
+		st = report.Status.Delivery("ops")
+		Expect(st.State).To(Equal(v1alpha1.DeliverySent))
+		Expect(st.Attempts).To(Equal(int32(2)))
+		Expect(st.LastError).To(BeEmpty())
+		Expect(st.SentAt.Time).To(BeTemporally("==", now.Add(10*time.Second)))
+		Expect(report.Annotations).NotTo(HaveKey("notified"))
+
+		By("not sending again once sent")
+		reconcileAt(cl, now.Add(time.Hour))
+		Expect(calls.Load()).To(Equal(int32(2)))
+	})
+
+	It("honors Retry-After on rate limits", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "42")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer srv.Close()
+		cl := newFakeClient(append(channel(srv.URL, 3), report)...)
+
+		res := reconcileAt(cl, now)
+		Expect(res.RequeueAfter).To(Equal(42 * time.Second))
+		Expect(report.Status.Delivery("ops").NextAttemptAt.Time).To(BeTemporally("==", now.Add(42*time.Second)))
+	})
+
+	It("dead-letters deliveries once attempts run out", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+		cl := newFakeClient(append(channel(srv.URL, 2), report)...)
+
+		reconcileAt(cl, now)
+		res := reconcileAt(cl, now.Add(time.Minute))
+		Expect(res.RequeueAfter).To(BeZero())
+		st := report.Status.Delivery("ops")
+		Expect(st.State).To(Equal(v1alpha1.DeliveryDeadLetter))
+		Expect(st.Attempts).To(Equal(int32(2)))
+		Expect(st.NextAttemptAt).To(BeNil())
+
+		reconcileAt(cl, now.Add(time.Hour))
+		Expect(calls.Load()).To(Equal(int32(2)))
+	})
+
+	It("dead-letters permanent failures immediately", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+		cl := newFakeClient(append(channel(srv.URL, 8), report)...)
+
+		reconcileAt(cl, now)
+		st := report.Status.Delivery("ops")
+		Expect(st.State).To(Equal(v1alpha1.DeliveryDeadLetter))
+		Expect(st.Attempts).To(Equal(int32(1)))
+	})
+
+	It("keeps retrying channels whose Secret is missing", func() {
+		objs := channel("unused", 3)
+		cl := newFakeClient(objs[1], report)
+
+		res := reconcileAt(cl, now)
+		Expect(res.RequeueAfter).To(Equal(10 * time.Second))
+		st := report.Status.Delivery("ops")
+		Expect(st.State).To(Equal(v1alpha1.DeliveryPending))
+		Expect(st.LastError).To(ContainSubstring("gokubedog-system/slack"))
+	})
+	It("resends an attempt left in Sending once it timed out", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		report.Status.Deliveries = []v1alpha1.DeliveryStatus{{
+			Channel: "ops", State: v1alpha1.DeliverySending, Attempts: 1,
+			LastAttemptAt: &metav1.Time{Time: now},
+		}}
+		cl := newFakeClient(append(channel(srv.URL, 3), report)...)
+
+		res := reconcileAt(cl, now.Add(4*time.Second))
+		Expect(res.RequeueAfter).To(Equal(6 * time.Second))
+		Expect(calls.Load()).To(BeZero())
+
+		reconcileAt(cl, now.Add(10*time.Second))
+		Expect(calls.Load()).To(Equal(int32(1)))
+		st := report.Status.Delivery("ops")
+		Expect(st.State).To(Equal(v1alpha1.DeliverySent))
+		Expect(st.Attempts).To(Equal(int32(2)))
+	})
+
+	It("does not send when the claim conflicts", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		cl := fake.NewClientBuilder().WithScheme(testScheme).
+			WithStatusSubresource(&v1alpha1.PolicyViolationReport{}).
+			WithObjects(append(channel(srv.URL, 3), report)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+					return apierrors.NewConflict(schema.GroupResource{Resource: "policyviolationreports"}, report.Name, nil)
+				},
+			}).Build()
+
+		r := &PolicyViolationReportReconciler{Client: cl, now: func() time.Time { return now }}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+		Expect(calls.Load()).To(BeZero())
+	})
+
+	It("does not spend the rate limit on a conflicting claim", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		objs := channel(srv.URL, 3)
+		objs[1].(*v1alpha1.NotificationChannel).Spec.RateLimit = &v1alpha1.RateLimitSpec{
+			Channel: &v1alpha1.TokenBucketSpec{PerMinute: 1},
+		}
+		var conflicts atomic.Int32
+		cl := fake.NewClientBuilder().WithScheme(testScheme).
+			WithStatusSubresource(&v1alpha1.PolicyViolationReport{}).
+			WithObjects(append(objs, report)...).
+			WithInterceptorFuncs(interceptor.Funcs{
+				SubResourcePatch: func(ctx context.Context, c client.Client, sub string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+					if conflicts.Add(1) == 1 {
+						return apierrors.NewConflict(schema.GroupResource{Resource: "policyviolationreports"}, report.Name, nil)
+					}
+					return c.SubResource(sub).Patch(ctx, obj, patch, opts...)
+				},
+			}).Build()
+
+		r := &PolicyViolationReportReconciler{Client: cl, now: func() time.Time { return now }}
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
+		Expect(apierrors.IsConflict(err)).To(BeTrue())
+
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(report)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls.Load()).To(Equal(int32(1)))
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(report), report)).To(Succeed())
+		Expect(report.Status.Delivery("ops").State).To(Equal(v1alpha1.DeliverySent))
+	})
+	It("sends the violations of a batching window as one digest", func() {
+		var bodies []string
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+		}))
+		defer srv.Close()
+		objs := channel(srv.URL, 3)
+		objs[1].(*v1alpha1.NotificationChannel).Spec.Batch = &v1alpha1.BatchSpec{
+			Window: metav1.Duration{Duration: time.Minute},
+		}
+		reports := []*v1alpha1.PolicyViolationReport{report}
+		for _, name := range []string{"np-a", "np-b"} {
+			rep := report.DeepCopy()
+			rep.Name = "delivery-" + name
+			rep.Spec.ViolatedResource.Name = name
+			reports = append(reports, rep)
+		}
+		for _, rep := range reports {
+			objs = append(objs, rep)
+		}
+		cl := newFakeClient(objs...)
+
+		for i, rep := range reports {
+			report = rep
+			res := reconcileAt(cl, now.Add(time.Duration(i)*time.Second))
+			Expect(res.RequeueAfter).To(Equal(time.Minute))
+			Expect(report.Status.Delivery("ops").State).To(Equal(v1alpha1.DeliveryBatched))
+		}
+		Expect(bodies).To(BeEmpty())
+
+		report = reports[0]
+		res := reconcileAt(cl, now.Add(time.Minute))
+		Expect(res.RequeueAfter).To(BeZero())
+		Expect(bodies).To(HaveLen(1))
+		Expect(bodies[0]).To(ContainSubstring("3 violation(s)"))
+		Expect(bodies[0]).To(ContainSubstring("test-profile in default: 3"))
+
+		for _, rep := range reports {
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(rep), rep)).To(Succeed())
+			st := rep.Status.Delivery("ops")
+			Expect(st.State).To(Equal(v1alpha1.DeliverySent))
+			Expect(st.Attempts).To(Equal(int32(1)))
+		}
+
+		By("not sending the batch again from the other reports")
+		report = reports[2]
+		reconcileAt(cl, now.Add(2*time.Minute))
+		Expect(bodies).To(HaveLen(1))
+	})
+	It("notifies the resolution of notified reports once", func() {
+		var bodies []string
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+		}))
+		defer srv.Close()
+		objs := channel(srv.URL, 3)
+		objs[1].(*v1alpha1.NotificationChannel).Spec.SendResolved = true
+		cl := newFakeClient(append(objs, report)...)
+
+		reconcileAt(cl, now)
+		Expect(report.Status.Delivery("ops").State).To(Equal(v1alpha1.DeliverySent))
+
+		report.Status.Phase = v1alpha1.ReportPhaseResolved
+		report.Status.ResolvedAt = &metav1.Time{Time: now.Add(time.Hour)}
+		Expect(cl.Status().Update(ctx, report)).To(Succeed())
+		reconcileAt(cl, now.Add(time.Hour))
+		st := report.Status.Resolution("ops")
+		Expect(st).NotTo(BeNil())
+		Expect(st.State).To(Equal(v1alpha1.DeliverySent))
+		Expect(bodies).To(HaveLen(2))
+		Expect(bodies[1]).To(ContainSubstring("Policy Violation Resolved"))
+
+		By("not sending the resolution again")
+		reconcileAt(cl, now.Add(2*time.Hour))
+		Expect(bodies).To(HaveLen(2))
+	})
+
+	It("does not notify resolutions of reports the channel was not notified of", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		objs := channel(srv.URL, 3)
+		objs[1].(*v1alpha1.NotificationChannel).Spec.SendResolved = true
+		report.Status.Phase = v1alpha1.ReportPhaseResolved
+		cl := newFakeClient(append(objs, report)...)
+
+		reconcileAt(cl, now)
+		Expect(calls.Load()).To(BeZero())
+		Expect(report.Status.Resolutions).To(BeEmpty())
+	})
+
+	It("does not notify acknowledged violations until their snooze ends", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		report.Spec.Acknowledgement = &v1alpha1.Acknowledgement{
+			By:          "alice",
+			At:          metav1.NewTime(now),
+			Reason:      "fixing next sprint",
+			SnoozeUntil: &metav1.Time{Time: now.Add(time.Hour)},
+		}
+		cl := newFakeClient(append(channel(srv.URL, 3), report)...)
+
+		res := reconcileAt(cl, now)
+		Expect(res.RequeueAfter).To(Equal(time.Hour))
+		Expect(calls.Load()).To(BeZero())
+		Expect(report.Status.Deliveries).To(BeEmpty())
+
+		reconcileAt(cl, now.Add(time.Hour))
+		Expect(calls.Load()).To(Equal(int32(1)))
+		Expect(report.Status.Delivery("ops").State).To(Equal(v1alpha1.DeliverySent))
+	})
+
+	It("does not notify violations acknowledged without a snooze", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		report.Spec.Acknowledgement = &v1alpha1.Acknowledgement{By: "alice", At: metav1.NewTime(now)}
+		cl := newFakeClient(append(channel(srv.URL, 3), report)...)
+
+		res := reconcileAt(cl, now.Add(30*24*time.Hour))
+		Expect(res.RequeueAfter).To(BeZero())
+		Expect(calls.Load()).To(BeZero())
+	})
+
+	It("only notifies channels routing the team owning the resource", func() {
+		var calls atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+		}))
+		defer srv.Close()
+		objs := channel(srv.URL, 3)
+		objs[1].(*v1alpha1.NotificationChannel).Spec.Route = &v1alpha1.RouteSpec{Teams: []string{"payments"}}
+		report.Spec.Ownership = &v1alpha1.Ownership{Team: "search"}
+		cl := newFakeClient(append(objs, report)...)
+
+		reconcileAt(cl, now)
+		Expect(calls.Load()).To(BeZero())
+		Expect(report.Status.Deliveries).To(BeEmpty())
+
+		report.Spec.Ownership.Team = "payments"
+		Expect(cl.Update(ctx, report)).To(Succeed())
+		reconcileAt(cl, now)
+		Expect(calls.Load()).To(Equal(int32(1)))
+		Expect(report.Status.Delivery("ops").State).To(Equal(v1alpha1.DeliverySent))
+	})
+
+	It("suppresses notifications over the rate limit and announces it", func() {
+		var bodies []string
+		srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+		}))
+		defer srv.Close()
+		objs := channel(srv.URL, 3)
+		objs[1].(*v1alpha1.NotificationChannel).Spec.RateLimit = &v1alpha1.RateLimitSpec{
+			Channel: &v1alpha1.TokenBucketSpec{PerMinute: 1},
+		}
+		var reports []*v1alpha1.PolicyViolationReport
+		for _, name := range []string{"np-a", "np-b", "np-c"} {
+			rep := report.DeepCopy()
+			rep.Name = "limited-" + name
+			rep.Spec.ViolatedResource.Name = name
+			reports = append(reports, rep)
+			objs = append(objs, rep)
+		}
+		cl := newFakeClient(objs...)
+		r := &PolicyViolationReportReconciler{Client: cl, now: func() time.Time { return now }}
+
+		for _, rep := range reports {
+			_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rep)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cl.Get(ctx, client.ObjectKeyFromObject(rep), rep)).To(Succeed())
+		}
+
+		Expect(reports[0].Status.Delivery("ops").State).To(Equal(v1alpha1.DeliverySent))
+		for _, rep := range reports[1:] {
+			st := rep.Status.Delivery("ops")
+			Expect(st.State).To(Equal(v1alpha1.DeliverySuppressed))
+			Expect(st.Reason).To(ContainSubstring("exceed 1 per minute"))
+			Expect(st.Attempts).To(BeZero())
+		}
+		Expect(bodies).To(HaveLen(2))
+		Expect(bodies[1]).To(ContainSubstring("Suppressing notifications"))
+
+		By("not retrying suppressed deliveries")
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(reports[1])})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bodies).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watchdog
+
+import (
+	"context"
+	"time"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/notify"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// resolve notifies the resolution of report to the channels sending
+// resolutions that were notified of the violation. Resolutions are claimed,
+// retried and dead-lettered like the notifications of violations.
+func (r *PolicyViolationReportReconciler) resolve(ctx context.Context,
+	report *v1alpha1.PolicyViolationReport) (ctrl.Result, error) {
+	log := logf.FromContext(ctx).WithValues("violation", client.ObjectKeyFromObject(report))
+
+	targets, err := r.targets(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	now := r.clock()
+	var due []notify.Target
+	var wait time.Duration
+	for _, t := range targets {
+		if _, ok := t.Resolver(); !ok {
+			continue
+		}
+		name := t.Channel.Name()
+		if opened := report.Status.Delivery(name); opened == nil || opened.State != v1alpha1.DeliverySent {
+			continue
+		}
+		if prev := report.Status.Resolution(name); prev != nil {
+			ready, after := attemptDue(prev, t.Policy, now)
+			wait = minWait(wait, after)
+			if !ready {
+				continue
+			}
+		}
+		due = append(due, t)
+	}
+	if len(due) == 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	base := report.DeepCopy()
+	for _, t := range due {
+		name := t.Channel.Name()
+		setDelivery(&report.Status.Resolutions, notify.Claim(report.Status.Resolution(name), name, now))
+	}
+	if err := r.Status().Patch(ctx, report, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{})); err != nil {
+		log.Error(err, "failed to claim resolution notifications")
+		return ctrl.Result{}, err
+	}
+
+	msg, err := notify.LoadMessage(ctx, r.Client, report, r.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	results := make([]v1alpha1.DeliveryStatus, 0, len(due))
+	for _, t := range due {
+		ch, _ := t.Resolver()
+		name := t.Channel.Name()
+		st := notify.DeliverResolution(ctx, ch, t.Policy, msg,
+			report.Status.Delivery(name), *report.Status.Resolution(name), r.clock())
+		results = append(results, st)
+		wait = minWait(wait, logDelivery(log.WithValues("resolution", true), st, now))
+	}
+	if err := r.recordDeliveries(ctx, report, resolutions, results); err != nil {
+		log.Error(err, "failed to record resolution notifications")
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	return ctrl.Result{RequeueAfter: wait}, nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboard serves the embedded web UI on top of the compliance API
+// and lets authorized users create PolicyExceptions from a violation.
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/httpapi"
+)
+
+//go:embed static
+var static embed.FS
+
+const maxRequestBytes = 64 * 1024
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyexceptions,verbs=create
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Reviewer authenticates bearer tokens and authorizes the resulting users.
+type Reviewer interface {
+	Authenticate(ctx context.Context, token string) (authenticationv1.UserInfo, bool, error)
+	Authorize(ctx context.Context, user authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes) (bool, error)
+}
+
+// Handler serves the dashboard assets and the exception endpoint. Everything
+// else the UI shows is read from the compliance API, so the dashboard uses the
+// same informer cache as the controllers.
+type Handler struct {
+	// Client creates the PolicyExceptions.
+	Client client.Client
+	// Reviewer checks the caller before an exception is created.
+	Reviewer Reviewer
+}
+
+// Routes returns the dashboard endpoints keyed by path. The assets are public,
+// the exception endpoint performs its own authentication and authorization.
+func (h *Handler) Routes() map[string]http.Handler {
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embedded tree is fixed at build time
+	}
+	return map[string]http.Handler{
+		"/dashboard/":        httpapi.Public(http.StripPrefix("/dashboard/", http.FileServer(http.FS(assets)))),
+		"/api/v1/exceptions": httpapi.Public(http.HandlerFunc(h.createException)),
+	}
+}
+
+// ExceptionRequest is the body accepted by the exception endpoint.
+type ExceptionRequest struct {
+	Namespace        string     `json:"namespace"`
+	ProfileName      string     `json:"profileName"`
+	ProfileNamespace string     `json:"profileNamespace,omitempty"`
+	Kind             string     `json:"kind"`
+	Name             string     `json:"name"`
+	Reason           string     `json:"reason"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+}
+
+func (r ExceptionRequest) validate() error {
+	switch {
+	case r.Namespace == "", r.ProfileName == "", r.Kind == "", r.Name == "":
+		return errors.New("namespace, profileName, kind and name are required")
+	case strings.TrimSpace(r.Reason) == "":
+		return errors.New("a reason is required")
+	case r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()):
+		return errors.New("expiresAt must be in the future")
+	}
+	return nil
+}
+
+func (h *Handler) createException(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := req.Context()
+	log := logf.FromContext(ctx)
+
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+		return
+	}
+	user, authenticated, err := h.Reviewer.Authenticate(ctx, token)
+	if err != nil {
+		log.Error(err, "token review failed")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !authenticated {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body ExceptionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxRequestBytes)).Decode(&body); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := body.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	allowed, err := h.Reviewer.Authorize(ctx, user, authorizationv1.ResourceAttributes{
+		Namespace: body.Namespace,
+		Verb:      "create",
+		Group:     watchdogv1alpha1.GroupVersion.Group,
+		Resource:  "policyexceptions",
+	})
+	if err != nil {
+		log.Error(err, "subject access review failed")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if !allowed {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	exc := &watchdogv1alpha1.PolicyException{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: body.ProfileName + "-",
+			Namespace:    body.Namespace,
+		},
+		Spec: watchdogv1alpha1.PolicyExceptionSpec{
+			ProfileName:      body.ProfileName,
+			ProfileNamespace: body.ProfileNamespace,
+			Resource:         watchdogv1alpha1.ExceptionResourceSpec{Kind: body.Kind, Name: body.Name},
+			Reason:           body.Reason,
+			CreatedBy:        user.Username,
+		},
+	}
+	if body.ExpiresAt != nil {
+		exc.Spec.ExpiresAt = &metav1.Time{Time: *body.ExpiresAt}
+	}
+	if err := h.Client.Create(ctx, exc); err != nil {
+		log.Error(err, "failed creating PolicyException", "namespace", body.Namespace)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	log.Info("PolicyException created from dashboard", "name", exc.Name, "namespace", exc.Namespace, "user", user.Username)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(exc); err != nil {
+		log.Error(err, "failed writing dashboard response")
+	}
+}
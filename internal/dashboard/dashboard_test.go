@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+type fakeReviewer struct {
+	tokens  map[string]string
+	allowed map[string]bool
+	attrs   authorizationv1.ResourceAttributes
+}
+
+func (r *fakeReviewer) Authenticate(_ context.Context, token string) (authenticationv1.UserInfo, bool, error) {
+	user, ok := r.tokens[token]
+	return authenticationv1.UserInfo{Username: user}, ok, nil
+}
+
+func (r *fakeReviewer) Authorize(
+	_ context.Context, user authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes,
+) (bool, error) {
+	r.attrs = attrs
+	return r.allowed[user.Username], nil
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		h        *Handler
+		cl       client.Client
+		reviewer *fakeReviewer
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(watchdogv1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).Build()
+		reviewer = &fakeReviewer{
+			tokens:  map[string]string{"alice-token": "alice", "bob-token": "bob"},
+			allowed: map[string]bool{"alice": true},
+		}
+		h = &Handler{Client: cl, Reviewer: reviewer}
+	})
+
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/exceptions", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.Routes()["/api/v1/exceptions"].ServeHTTP(rec, req)
+		return rec
+	}
+
+	const validBody = `{"namespace":"team-a","profileName":"require-foo","kind":"NetworkPolicy",` +
+		`"name":"np1","reason":"legacy workload"}`
+
+	It("serves the embedded assets", func() {
+		rec := httptest.NewRecorder()
+		h.Routes()["/dashboard/"].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard/", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Body.String()).To(ContainSubstring("app.js"))
+
+		rec = httptest.NewRecorder()
+		h.Routes()["/dashboard/"].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard/app.js", nil))
+		Expect(rec.Code).To(Equal(http.StatusOK))
+	})
+
+	It("creates an exception for an authorized user", func() {
+		rec := post("alice-token", validBody)
+		Expect(rec.Code).To(Equal(http.StatusCreated))
+		Expect(reviewer.attrs).To(Equal(authorizationv1.ResourceAttributes{
+			Namespace: "team-a", Verb: "create", Group: "watchdog.bizaikube.io", Resource: "policyexceptions",
+		}))
+
+		var created watchdogv1alpha1.PolicyException
+		Expect(json.Unmarshal(rec.Body.Bytes(), &created)).To(Succeed())
+
+		list := &watchdogv1alpha1.PolicyExceptionList{}
+		Expect(cl.List(context.Background(), list, client.InNamespace("team-a"))).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		exc := list.Items[0]
+		Expect(exc.Name).To(Equal(created.Name))
+		Expect(exc.Spec.CreatedBy).To(Equal("alice"))
+		Expect(exc.Profile()).To(Equal(types.NamespacedName{Namespace: "team-a", Name: "require-foo"}))
+		Expect(exc.Spec.Resource).To(Equal(watchdogv1alpha1.ExceptionResourceSpec{Kind: "NetworkPolicy", Name: "np1"}))
+		Expect(exc.Spec.ExpiresAt).To(BeNil())
+	})
+
+	It("rejects callers without a valid token", func() {
+		Expect(post("", validBody).Code).To(Equal(http.StatusUnauthorized))
+		Expect(post("unknown", validBody).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("rejects users not allowed to create exceptions", func() {
+		Expect(post("bob-token", validBody).Code).To(Equal(http.StatusForbidden))
+
+		list := &watchdogv1alpha1.PolicyExceptionList{}
+		Expect(cl.List(context.Background(), list)).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	})
+
+	It("validates the request", func() {
+		Expect(post("alice-token", `{"namespace":"team-a"}`).Code).To(Equal(http.StatusBadRequest))
+		Expect(post("alice-token", `not json`).Code).To(Equal(http.StatusBadRequest))
+
+		past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		body := strings.TrimSuffix(validBody, "}") + `,"expiresAt":"` + past + `"}`
+		Expect(post("alice-token", body).Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("only accepts POST", func() {
+		rec := httptest.NewRecorder()
+		h.Routes()["/api/v1/exceptions"].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/exceptions", nil))
+		Expect(rec.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+})
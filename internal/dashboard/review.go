@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboard
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// APIReviewer delegates authentication and authorization to the API server
+// through TokenReviews and SubjectAccessReviews.
+type APIReviewer struct {
+	Client client.Client
+}
+
+// Authenticate validates the token and returns the user it belongs to.
+func (r *APIReviewer) Authenticate(ctx context.Context, token string) (authenticationv1.UserInfo, bool, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	if err := r.Client.Create(ctx, review); err != nil {
+		return authenticationv1.UserInfo{}, false, fmt.Errorf("failed creating TokenReview: %w", err)
+	}
+	return review.Status.User, review.Status.Authenticated, nil
+}
+
+// Authorize reports whether user may perform the action described by attrs.
+func (r *APIReviewer) Authorize(
+	ctx context.Context, user authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes,
+) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		ResourceAttributes: &attrs,
+		User:               user.Username,
+		UID:                user.UID,
+		Groups:             user.Groups,
+		Extra:              extra,
+	}}
+	if err := r.Client.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("failed creating SubjectAccessReview: %w", err)
+	}
+	return review.Status.Allowed, nil
+}
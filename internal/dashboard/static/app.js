@@ -0,0 +1,177 @@
+'use strict';
+
+// The dashboard reads everything from the compliance API with the bearer
+// token entered by the user, which is kept for the browser session only.
+const severities = ['critical', 'high', 'medium', 'low'];
+let selected = null;
+
+function token() {
+  return sessionStorage.getItem('gokubedog-token') || '';
+}
+
+async function api(path, options = {}) {
+  const headers = Object.assign({ Authorization: 'Bearer ' + token() }, options.headers || {});
+  const resp = await fetch(path, Object.assign({}, options, { headers }));
+  if (!resp.ok) {
+    throw new Error(resp.status + ' ' + (await resp.text()).trim());
+  }
+  return resp.json();
+}
+
+async function listAll(path) {
+  let items = [];
+  let cont = '';
+  do {
+    const sep = path.includes('?') ? '&' : '?';
+    const page = await api(path + (cont ? sep + 'continue=' + encodeURIComponent(cont) : ''));
+    items = items.concat(page.items);
+    cont = page.continue || '';
+  } while (cont);
+  return items;
+}
+
+function cell(row, text) {
+  const td = document.createElement('td');
+  td.textContent = text;
+  row.appendChild(td);
+  return td;
+}
+
+function showError(err) {
+  const main = document.querySelector('main');
+  const p = document.createElement('p');
+  p.className = 'error';
+  p.textContent = err.message;
+  main.prepend(p);
+  setTimeout(() => p.remove(), 8000);
+}
+
+async function loadProfiles() {
+  const body = document.querySelector('#profiles tbody');
+  body.replaceChildren();
+  for (const p of await listAll('/api/v1/profiles')) {
+    const row = body.insertRow();
+    cell(row, p.namespace);
+    cell(row, p.name);
+    cell(row, p.kind);
+    cell(row, p.matchNamespace);
+    cell(row, p.lastChecked ? new Date(p.lastChecked).toLocaleString() : 'never');
+  }
+}
+
+async function loadViolations() {
+  const phase = document.getElementById('phase').value;
+  const violations = await listAll('/api/v1/violations?phase=' + phase);
+  const byNamespace = new Map();
+  for (const v of violations) {
+    if (!byNamespace.has(v.namespace)) {
+      byNamespace.set(v.namespace, []);
+    }
+    byNamespace.get(v.namespace).push(v);
+  }
+
+  const container = document.getElementById('violations');
+  container.replaceChildren();
+  for (const [namespace, items] of [...byNamespace].sort()) {
+    const heading = document.createElement('h3');
+    heading.textContent = namespace + ' (' + items.length + ')';
+    container.appendChild(heading);
+
+    const table = document.createElement('table');
+    const head = table.createTHead().insertRow();
+    ['Severity', 'Kind', 'Resource', 'Profile', 'Phase', 'Since'].forEach((h) => cell(head, h));
+    const body = table.createTBody();
+    items.sort((a, b) => severities.indexOf(a.severity) - severities.indexOf(b.severity));
+    for (const v of items) {
+      const row = body.insertRow();
+      row.className = 'violation';
+      const sev = cell(row, v.severity);
+      sev.className = 'severity severity-' + v.severity;
+      cell(row, v.kind);
+      cell(row, v.resource);
+      cell(row, v.profile);
+      cell(row, v.phase);
+      cell(row, new Date(v.createdAt).toLocaleString());
+      row.addEventListener('click', () => showDetails(v).catch(showError));
+    }
+    container.appendChild(table);
+  }
+}
+
+async function showDetails(v) {
+  selected = v;
+  document.getElementById('details').hidden = false;
+  document.getElementById('details-title').textContent = v.namespace + '/' + v.resource + ' (' + v.kind + ')';
+  document.getElementById('exception-result').textContent = '';
+
+  const drift = document.querySelector('#drift tbody');
+  drift.replaceChildren();
+  for (const [field, diff] of Object.entries(v.drift || {}).sort()) {
+    const row = drift.insertRow();
+    cell(row, field);
+    cell(row, diff);
+  }
+
+  const timeline = document.getElementById('timeline');
+  timeline.replaceChildren();
+  try {
+    const params = new URLSearchParams({ namespace: v.namespace, report: v.name });
+    for (const ev of await listAll('/api/v1/history?' + params)) {
+      const li = document.createElement('li');
+      li.textContent = new Date(ev.time).toLocaleString() + ' ' + ev.type;
+      timeline.appendChild(li);
+    }
+  } catch (err) {
+    const li = document.createElement('li');
+    li.textContent = 'History unavailable: ' + err.message;
+    timeline.appendChild(li);
+  }
+}
+
+async function createException(event) {
+  event.preventDefault();
+  if (!selected) {
+    return;
+  }
+  const expires = document.getElementById('exception-expires').value;
+  const body = {
+    namespace: selected.namespace,
+    profileName: selected.profile,
+    profileNamespace: selected.profileNamespace,
+    kind: selected.kind,
+    name: selected.resource,
+    reason: document.getElementById('exception-reason').value,
+  };
+  if (expires) {
+    body.expiresAt = new Date(expires).toISOString();
+  }
+  const result = document.getElementById('exception-result');
+  try {
+    const exc = await api('/api/v1/exceptions', {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify(body),
+    });
+    result.className = '';
+    result.textContent = 'Created ' + exc.metadata.name;
+  } catch (err) {
+    result.className = 'error';
+    result.textContent = err.message;
+  }
+}
+
+function refresh() {
+  Promise.all([loadProfiles(), loadViolations()]).catch(showError);
+}
+
+document.getElementById('token-form').addEventListener('submit', (event) => {
+  event.preventDefault();
+  sessionStorage.setItem('gokubedog-token', document.getElementById('token').value);
+  refresh();
+});
+document.getElementById('phase').addEventListener('change', () => loadViolations().catch(showError));
+document.getElementById('exception-form').addEventListener('submit', createException);
+
+if (token()) {
+  refresh();
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileSink appends events as JSON lines to a local file, typically on a
+// PersistentVolume mounted into the manager.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+}
+
+// NewFileSink opens path for appending, creating it and its directory if needed.
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("file history sink needs a path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed creating history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening history file: %w", err)
+	}
+	return &FileSink{path: path, f: f}, nil
+}
+
+// Append writes the event as a single line and syncs it to disk.
+func (s *FileSink) Append(_ context.Context, ev Event) error {
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.f.Write(line); err != nil {
+		return fmt.Errorf("failed writing history event: %w", err)
+	}
+	return s.f.Sync()
+}
+
+// Query scans the file and returns the matching events.
+func (s *FileSink) Query(ctx context.Context, q Query) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening history file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var events []Event
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("corrupt history line: %w", err)
+		}
+		if !q.Matches(ev) {
+			continue
+		}
+		if skipped < q.Offset {
+			skipped++
+			continue
+		}
+		events = append(events, ev)
+		if q.Limit > 0 && len(events) == q.Limit {
+			break
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package history archives the state transitions of PolicyViolationReports
+// so that past drift stays auditable after the reports themselves are gone.
+package history
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// EventType is the kind of state transition recorded for a report.
+type EventType string
+
+const (
+	// EventOpened is recorded when a report is created or reopened.
+	EventOpened EventType = "opened"
+	// EventUpdated is recorded when the drift of an open report changes.
+	EventUpdated EventType = "updated"
+	// EventResolved is recorded when a report moves to Resolved.
+	EventResolved EventType = "resolved"
+	// EventSuppressed is recorded when a violation is deliberately not reported.
+	EventSuppressed EventType = "suppressed"
+)
+
+// Event is a single report state transition.
+type Event struct {
+	Time             time.Time         `json:"time"`
+	Type             EventType         `json:"type"`
+	Namespace        string            `json:"namespace"`
+	Report           string            `json:"report"`
+	ProfileName      string            `json:"profileName"`
+	ProfileNamespace string            `json:"profileNamespace,omitempty"`
+	Kind             string            `json:"kind"`
+	Resource         string            `json:"resource"`
+	Drift            map[string]string `json:"drift,omitempty"`
+}
+
+// NewEvent builds an event of the given type from the current state of a report.
+func NewEvent(eventType EventType, rep *watchdogv1alpha1.PolicyViolationReport) Event {
+	return Event{
+		Time:             time.Now().UTC(),
+		Type:             eventType,
+		Namespace:        rep.Namespace,
+		Report:           rep.Name,
+		ProfileName:      rep.Spec.ProfileName,
+		ProfileNamespace: rep.Spec.ProfileNamespace,
+		Kind:             rep.Spec.ViolatedResource.Kind,
+		Resource:         rep.Spec.ViolatedResource.Name,
+		Drift:            rep.Spec.Drift,
+	}
+}
+
+// Sink is an append-only store for report events.
+type Sink interface {
+	// Append stores a single event.
+	Append(ctx context.Context, ev Event) error
+	// Close releases the resources held by the sink.
+	Close() error
+}
+
+// Query selects events from a Reader. Empty fields match everything.
+type Query struct {
+	Namespace   string
+	ProfileName string
+	Report      string
+	// Since drops events recorded before the given time.
+	Since time.Time
+	// Offset skips the first matching events, Limit caps the result when positive.
+	Offset int
+	Limit  int
+}
+
+// Matches reports whether ev satisfies the filters of q.
+func (q Query) Matches(ev Event) bool {
+	if q.Namespace != "" && ev.Namespace != q.Namespace {
+		return false
+	}
+	if q.ProfileName != "" && ev.ProfileName != q.ProfileName {
+		return false
+	}
+	if q.Report != "" && ev.Report != q.Report {
+		return false
+	}
+	return q.Since.IsZero() || !ev.Time.Before(q.Since)
+}
+
+// Reader is implemented by sinks whose events can be read back, oldest first.
+type Reader interface {
+	Query(ctx context.Context, q Query) ([]Event, error)
+}
+
+// Open returns the sink described by rawURL. Supported schemes are
+//
+//	file:///data/history.jsonl                  one JSON event per line
+//	sqlite:///data/history.db                   a SQLite database
+//	s3://bucket/prefix?endpoint=minio:9000      one object per event
+//
+// The s3 sink reads its credentials from AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY and accepts the region and insecure query parameters.
+func Open(rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history sink URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return NewFileSink(u.Path)
+	case "sqlite":
+		return NewSQLiteSink(u.Path)
+	case "s3":
+		return newS3SinkFromURL(u)
+	default:
+		return nil, fmt.Errorf("unsupported history sink scheme %q", u.Scheme)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("History sinks", func() {
+	ctx := context.Background()
+
+	report := &watchdogv1alpha1.PolicyViolationReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "violation-abc", Namespace: "team-a"},
+		Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+			ProfileName:      "require-owner",
+			ProfileNamespace: "team-a",
+			Drift:            map[string]string{"owner": "Expected: payments, Got: "},
+			ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{
+				Kind: "NetworkPolicy", Name: "allow-web", Namespace: "team-a",
+			},
+		},
+	}
+
+	It("should append JSON lines to a file", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "nested", "history.jsonl")
+		sink, err := Open("file://" + path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Append(ctx, NewEvent(EventOpened, report))).To(Succeed())
+		Expect(sink.Append(ctx, NewEvent(EventResolved, report))).To(Succeed())
+		Expect(sink.Close()).To(Succeed())
+
+		f, err := os.Open(path)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = f.Close() }()
+		var types []EventType
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var ev Event
+			Expect(json.Unmarshal(scanner.Bytes(), &ev)).To(Succeed())
+			Expect(ev.Resource).To(Equal("allow-web"))
+			types = append(types, ev.Type)
+		}
+		Expect(types).To(Equal([]EventType{EventOpened, EventResolved}))
+	})
+
+	It("should insert rows into a SQLite database", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "history.db")
+		sink, err := Open("sqlite://" + path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sink.Append(ctx, NewEvent(EventOpened, report))).To(Succeed())
+		Expect(sink.Append(ctx, NewEvent(EventUpdated, report))).To(Succeed())
+		Expect(sink.Close()).To(Succeed())
+
+		db, err := sql.Open("sqlite", path)
+		Expect(err).NotTo(HaveOccurred())
+		defer func() { _ = db.Close() }()
+		var count int
+		Expect(db.QueryRow(`SELECT COUNT(*) FROM report_events WHERE profile_name = ?`, "require-owner").
+			Scan(&count)).To(Succeed())
+		Expect(count).To(Equal(2))
+	})
+
+	It("should read back matching events from the file and SQLite sinks", func() {
+		dir := GinkgoT().TempDir()
+		for _, rawURL := range []string{"file://" + filepath.Join(dir, "h.jsonl"), "sqlite://" + filepath.Join(dir, "h.db")} {
+			sink, err := Open(rawURL)
+			Expect(err).NotTo(HaveOccurred())
+			other := report.DeepCopy()
+			other.Namespace = "team-b"
+			Expect(sink.Append(ctx, NewEvent(EventOpened, report))).To(Succeed())
+			Expect(sink.Append(ctx, NewEvent(EventOpened, other))).To(Succeed())
+			Expect(sink.Append(ctx, NewEvent(EventResolved, report))).To(Succeed())
+
+			reader, ok := sink.(Reader)
+			Expect(ok).To(BeTrue(), rawURL)
+			events, err := reader.Query(ctx, Query{Namespace: "team-a", Offset: 1, Limit: 5})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(1), rawURL)
+			Expect(events[0].Type).To(Equal(EventResolved))
+			Expect(events[0].Drift).To(HaveKey("owner"))
+
+			events, err = reader.Query(ctx, Query{Namespace: "team-a", Report: "other-report"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty(), rawURL)
+			Expect(sink.Close()).To(Succeed())
+		}
+	})
+
+	It("should upload one object per event to an S3-compatible store", func() {
+		var mu sync.Mutex
+		objects := map[string][]byte{}
+		// Minimal stand-in for MinIO that accepts PutObject requests
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodPut {
+				w.WriteHeader(http.StatusNotImplemented)
+				return
+			}
+			body, _ := io.ReadAll(req.Body)
+			if strings.HasPrefix(req.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+				body = decodeAWSChunked(body)
+			}
+			mu.Lock()
+			objects[req.URL.Path] = body
+			mu.Unlock()
+			w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		sink, err := NewS3Sink(S3Options{
+			Endpoint:    strings.TrimPrefix(server.URL, "http://"),
+			Bucket:      "audit",
+			Prefix:      "gokubedog/",
+			Region:      "us-east-1",
+			Insecure:    true,
+			Credentials: credentials.NewStaticV4("minio", "minio123", ""),
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		ev := NewEvent(EventResolved, report)
+		ev.Time = time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+		Expect(sink.Append(ctx, ev)).To(Succeed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(objects).To(HaveLen(1))
+		for key, body := range objects {
+			Expect(key).To(HavePrefix("/audit/gokubedog/2025/03/04/"))
+			Expect(key).To(HaveSuffix("-team-a-violation-abc-resolved.json"))
+			var stored Event
+			Expect(json.Unmarshal(body, &stored)).To(Succeed())
+			Expect(stored.Type).To(Equal(EventResolved))
+		}
+	})
+
+	It("should reject unknown schemes", func() {
+		_, err := Open("ftp://example.com/history")
+		Expect(err).To(MatchError(ContainSubstring("unsupported")))
+	})
+})
+
+// decodeAWSChunked strips the chunk headers minio-go adds when it streams a
+// signed upload over plain HTTP.
+func decodeAWSChunked(body []byte) []byte {
+	var out []byte
+	r := bufio.NewReader(strings.NewReader(string(body)))
+	for {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return out
+		}
+		size, err := strconv.ParseInt(strings.SplitN(strings.TrimSpace(header), ";", 2)[0], 16, 64)
+		if err != nil || size == 0 {
+			return out
+		}
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return out
+		}
+		out = append(out, chunk[:size]...)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Options configures an S3Sink.
+type S3Options struct {
+	// Endpoint is the host[:port] of the S3-compatible service.
+	Endpoint string
+	Bucket   string
+	// Prefix is prepended to every object key.
+	Prefix string
+	Region string
+	// Insecure talks plain HTTP to the endpoint, e.g. for a local MinIO.
+	Insecure bool
+	// Credentials default to the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+	Credentials *credentials.Credentials
+}
+
+// S3Sink writes every event as its own JSON object to an S3-compatible
+// object store. Objects are immutable, so appending means adding objects
+// under a date-partitioned key.
+type S3Sink struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Sink creates a sink writing to the bucket described by opts.
+func NewS3Sink(opts S3Options) (*S3Sink, error) {
+	if opts.Endpoint == "" || opts.Bucket == "" {
+		return nil, fmt.Errorf("s3 history sink needs an endpoint and a bucket")
+	}
+	creds := opts.Credentials
+	if creds == nil {
+		creds = credentials.NewEnvAWS()
+	}
+	cl, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: !opts.Insecure,
+		Region: opts.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating s3 client: %w", err)
+	}
+	return &S3Sink{client: cl, bucket: opts.Bucket, prefix: strings.Trim(opts.Prefix, "/")}, nil
+}
+
+func newS3SinkFromURL(u *url.URL) (*S3Sink, error) {
+	q := u.Query()
+	insecure, _ := strconv.ParseBool(q.Get("insecure"))
+	region := q.Get("region")
+	if region == "" {
+		region = "us-east-1"
+	}
+	return NewS3Sink(S3Options{
+		Endpoint: q.Get("endpoint"),
+		Bucket:   u.Host,
+		Prefix:   u.Path,
+		Region:   region,
+		Insecure: insecure,
+	})
+}
+
+// Append uploads the event as a new object.
+func (s *S3Sink) Append(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, s.objectKey(ev), bytes.NewReader(body), int64(len(body)),
+		minio.PutObjectOptions{ContentType: "application/json"})
+	if err != nil {
+		return fmt.Errorf("failed uploading history event: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op, the sink holds no open resources.
+func (s *S3Sink) Close() error {
+	return nil
+}
+
+func (s *S3Sink) objectKey(ev Event) string {
+	t := ev.Time.UTC()
+	name := fmt.Sprintf("%d-%s-%s-%s.json", t.UnixNano(), ev.Namespace, ev.Report, ev.Type)
+	return path.Join(s.prefix, t.Format("2006/01/02"), name)
+}
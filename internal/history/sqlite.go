@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	// Pure Go SQLite driver, the manager is built with CGO disabled.
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS report_events (
+	id                INTEGER PRIMARY KEY AUTOINCREMENT,
+	time              TIMESTAMP NOT NULL,
+	type              TEXT NOT NULL,
+	namespace         TEXT NOT NULL,
+	report            TEXT NOT NULL,
+	profile_name      TEXT NOT NULL,
+	profile_namespace TEXT NOT NULL,
+	kind              TEXT NOT NULL,
+	resource          TEXT NOT NULL,
+	drift             TEXT
+);
+CREATE INDEX IF NOT EXISTS report_events_namespace_time ON report_events (namespace, time);
+`
+
+// SQLiteSink stores events in a SQLite database.
+type SQLiteSink struct {
+	db *sql.DB
+}
+
+// NewSQLiteSink opens or creates the database at path.
+func NewSQLiteSink(path string) (*SQLiteSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite history sink needs a path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, fmt.Errorf("failed creating history directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening history database: %w", err)
+	}
+	// SQLite allows a single writer, serialize through one connection
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed creating history schema: %w", err)
+	}
+	return &SQLiteSink{db: db}, nil
+}
+
+// Append inserts the event as a new row.
+func (s *SQLiteSink) Append(ctx context.Context, ev Event) error {
+	drift, err := json.Marshal(ev.Drift)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO report_events
+			(time, type, namespace, report, profile_name, profile_namespace, kind, resource, drift)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ev.Time.UTC(), string(ev.Type), ev.Namespace, ev.Report,
+		ev.ProfileName, ev.ProfileNamespace, ev.Kind, ev.Resource, string(drift),
+	)
+	if err != nil {
+		return fmt.Errorf("failed inserting history event: %w", err)
+	}
+	return nil
+}
+
+// Query selects the matching events ordered by insertion.
+func (s *SQLiteSink) Query(ctx context.Context, q Query) ([]Event, error) {
+	stmt := `SELECT time, type, namespace, report, profile_name, profile_namespace, kind, resource, drift
+		FROM report_events WHERE 1 = 1`
+	var args []any
+	if q.Namespace != "" {
+		stmt += ` AND namespace = ?`
+		args = append(args, q.Namespace)
+	}
+	if q.ProfileName != "" {
+		stmt += ` AND profile_name = ?`
+		args = append(args, q.ProfileName)
+	}
+	if q.Report != "" {
+		stmt += ` AND report = ?`
+		args = append(args, q.Report)
+	}
+	if !q.Since.IsZero() {
+		stmt += ` AND time >= ?`
+		args = append(args, q.Since.UTC())
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	stmt += ` ORDER BY id LIMIT ? OFFSET ?`
+	args = append(args, limit, q.Offset)
+
+	rows, err := s.db.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed querying history: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var events []Event
+	for rows.Next() {
+		var ev Event
+		var eventType, drift string
+		if err := rows.Scan(&ev.Time, &eventType, &ev.Namespace, &ev.Report, &ev.ProfileName,
+			&ev.ProfileNamespace, &ev.Kind, &ev.Resource, &drift); err != nil {
+			return nil, err
+		}
+		ev.Type = EventType(eventType)
+		if err := json.Unmarshal([]byte(drift), &ev.Drift); err != nil {
+			return nil, fmt.Errorf("corrupt history drift: %w", err)
+		}
+		events = append(events, ev)
+	}
+	return events, rows.Err()
+}
+
+// Close closes the database.
+func (s *SQLiteSink) Close() error {
+	return s.db.Close()
+}
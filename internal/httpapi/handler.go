@@ -0,0 +1,375 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package httpapi serves a read-only JSON API over profiles, violations,
+// compliance scores and report history for consumers without cluster access.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/history"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+var errBadRequest = errors.New("bad request")
+
+// Handler serves the compliance API. Reader is normally the manager client,
+// so every request is answered from the informer cache.
+type Handler struct {
+	Reader client.Reader
+	// History is optional, the history endpoint answers 501 without it.
+	History history.Reader
+}
+
+// Routes returns the API endpoints keyed by path, ready to be mounted on a server.
+func (h *Handler) Routes() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/api/v1/profiles":   getOnly(h.listProfiles),
+		"/api/v1/violations": getOnly(h.listViolations),
+		"/api/v1/compliance": getOnly(h.listCompliance),
+		"/api/v1/history":    getOnly(h.listHistory),
+	}
+}
+
+// Page is the envelope of every list response. Continue is empty on the last page.
+type Page[T any] struct {
+	Items    []T    `json:"items"`
+	Continue string `json:"continue,omitempty"`
+}
+
+// Profile is the API view of a PolicyProfile.
+type Profile struct {
+	Name        string                              `json:"name"`
+	Namespace   string                              `json:"namespace"`
+	Kind        string                              `json:"kind"`
+	Match       string                              `json:"matchNamespace"`
+	Policy      map[string]string                   `json:"policy,omitempty"`
+	Required    []string                            `json:"requiredLabels,omitempty"`
+	LastChecked *time.Time                          `json:"lastChecked,omitempty"`
+	Namespaces  []watchdogv1alpha1.NamespaceSummary `json:"namespaces,omitempty"`
+}
+
+// Violation is the API view of a PolicyViolationReport.
+type Violation struct {
+	Name             string                       `json:"name"`
+	Namespace        string                       `json:"namespace"`
+	Profile          string                       `json:"profile"`
+	ProfileNamespace string                       `json:"profileNamespace,omitempty"`
+	Kind             string                       `json:"kind"`
+	Resource         string                       `json:"resource"`
+	Severity         watchdogv1alpha1.Severity    `json:"severity"`
+	Phase            watchdogv1alpha1.ReportPhase `json:"phase"`
+	Drift            map[string]string            `json:"drift"`
+	CreatedAt        time.Time                    `json:"createdAt"`
+	ResolvedAt       *time.Time                   `json:"resolvedAt,omitempty"`
+}
+
+// NamespaceCompliance aggregates the latest evaluation of all profiles in a namespace.
+type NamespaceCompliance struct {
+	Namespace string `json:"namespace"`
+	Matched   int32  `json:"matched"`
+	Violating int32  `json:"violating"`
+	// Score is the share of matched resources without drift, between 0 and 1.
+	Score    float64 `json:"score"`
+	Profiles int     `json:"profiles"`
+}
+
+func (h *Handler) listProfiles(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	profiles := &watchdogv1alpha1.PolicyProfileList{}
+	if err := h.Reader.List(req.Context(), profiles, namespaceOption(q.Get("namespace"))...); err != nil {
+		writeError(w, req, err)
+		return
+	}
+
+	items := make([]Profile, 0, len(profiles.Items))
+	for _, p := range profiles.Items {
+		// A profile whose template is missing or invalid is shown as is, its
+		// TemplateResolved condition tells why.
+		resolved, err := policy.ResolveTemplate(req.Context(), h.Reader, &p)
+		if err != nil {
+			resolved = &p
+		}
+		out := Profile{
+			Name:       p.Name,
+			Namespace:  p.Namespace,
+			Kind:       p.Spec.Match.Kind,
+			Match:      p.Spec.Match.Namespace,
+			Policy:     resolved.Spec.Policy,
+			Required:   resolved.Spec.RequiredLabels,
+			Namespaces: p.Status.Namespaces,
+		}
+		if !p.Status.LastChecked.IsZero() {
+			t := p.Status.LastChecked.Time
+			out.LastChecked = &t
+		}
+		items = append(items, out)
+	}
+
+	page, err := paginate(items, func(p Profile) string { return p.Namespace + "/" + p.Name }, q)
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	writeJSON(w, req, page)
+}
+
+// listViolations returns open violations unless phase=resolved or phase=all is given.
+// Reports created before severities existed count as medium.
+func (h *Handler) listViolations(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	reports := &watchdogv1alpha1.PolicyViolationReportList{}
+	if err := h.Reader.List(req.Context(), reports, namespaceOption(q.Get("namespace"))...); err != nil {
+		writeError(w, req, err)
+		return
+	}
+
+	phase := q.Get("phase")
+	items := make([]Violation, 0, len(reports.Items))
+	for _, rep := range reports.Items {
+		switch {
+		case phase == "all":
+		case phase == "resolved" && !rep.Status.IsResolved():
+			continue
+		case phase != "resolved" && rep.Status.IsResolved():
+			continue
+		}
+		if v := q.Get("profile"); v != "" && rep.Spec.ProfileName != v {
+			continue
+		}
+		if v := q.Get("kind"); v != "" && rep.Spec.ViolatedResource.Kind != v {
+			continue
+		}
+		v := toViolation(&rep)
+		if sev := q.Get("severity"); sev != "" && string(v.Severity) != sev {
+			continue
+		}
+		items = append(items, v)
+	}
+
+	page, err := paginate(items, func(v Violation) string { return v.Namespace + "/" + v.Name }, q)
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	writeJSON(w, req, page)
+}
+
+func (h *Handler) listCompliance(w http.ResponseWriter, req *http.Request) {
+	q := req.URL.Query()
+	profiles := &watchdogv1alpha1.PolicyProfileList{}
+	if err := h.Reader.List(req.Context(), profiles); err != nil {
+		writeError(w, req, err)
+		return
+	}
+
+	byNamespace := map[string]*NamespaceCompliance{}
+	for _, p := range profiles.Items {
+		for _, summary := range p.Status.Namespaces {
+			if ns := q.Get("namespace"); ns != "" && summary.Namespace != ns {
+				continue
+			}
+			c, ok := byNamespace[summary.Namespace]
+			if !ok {
+				c = &NamespaceCompliance{Namespace: summary.Namespace}
+				byNamespace[summary.Namespace] = c
+			}
+			c.Matched += summary.Matched
+			c.Violating += summary.Violating
+			c.Profiles++
+		}
+	}
+
+	items := make([]NamespaceCompliance, 0, len(byNamespace))
+	for _, c := range byNamespace {
+		c.Score = 1
+		if c.Matched > 0 {
+			c.Score = float64(c.Matched-c.Violating) / float64(c.Matched)
+		}
+		items = append(items, *c)
+	}
+
+	page, err := paginate(items, func(c NamespaceCompliance) string { return c.Namespace }, q)
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	writeJSON(w, req, page)
+}
+
+func (h *Handler) listHistory(w http.ResponseWriter, req *http.Request) {
+	if h.History == nil {
+		http.Error(w, "history is not available with the configured sink", http.StatusNotImplemented)
+		return
+	}
+
+	q := req.URL.Query()
+	limit, err := parseLimit(q.Get("limit"))
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	offset := 0
+	if token := q.Get("continue"); token != "" {
+		if offset, err = strconv.Atoi(token); err != nil || offset < 0 {
+			writeError(w, req, errBadRequest)
+			return
+		}
+	}
+	query := history.Query{
+		Namespace:   q.Get("namespace"),
+		ProfileName: q.Get("profile"),
+		Report:      q.Get("report"),
+		Offset:      offset,
+		Limit:       limit,
+	}
+	if since := q.Get("since"); since != "" {
+		if query.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			writeError(w, req, errBadRequest)
+			return
+		}
+	}
+
+	events, err := h.History.Query(req.Context(), query)
+	if err != nil {
+		writeError(w, req, err)
+		return
+	}
+	page := Page[history.Event]{Items: events}
+	if page.Items == nil {
+		page.Items = []history.Event{}
+	}
+	if len(events) == limit {
+		page.Continue = strconv.Itoa(offset + len(events))
+	}
+	writeJSON(w, req, page)
+}
+
+func toViolation(rep *watchdogv1alpha1.PolicyViolationReport) Violation {
+	v := Violation{
+		Name:             rep.Name,
+		Namespace:        rep.Namespace,
+		Profile:          rep.Spec.ProfileName,
+		ProfileNamespace: rep.Spec.ProfileNamespace,
+		Kind:             rep.Spec.ViolatedResource.Kind,
+		Resource:         rep.Spec.ViolatedResource.Name,
+		Severity:         rep.Spec.Severity,
+		Phase:            rep.Status.Phase,
+		Drift:            rep.Spec.Drift,
+		CreatedAt:        rep.CreationTimestamp.Time,
+	}
+	if v.Phase == "" {
+		v.Phase = watchdogv1alpha1.ReportPhaseOpen
+	}
+	if v.Severity == "" {
+		v.Severity = watchdogv1alpha1.SeverityMedium
+	}
+	if rep.Status.ResolvedAt != nil {
+		t := rep.Status.ResolvedAt.Time
+		v.ResolvedAt = &t
+	}
+	return v
+}
+
+// paginate sorts items by key and returns the page following the continue
+// token, which is the key of the last item of the previous page. The cache
+// does not support Limit/Continue, so pages are cut in memory.
+func paginate[T any](items []T, key func(T) string, q map[string][]string) (Page[T], error) {
+	limit, err := parseLimit(first(q["limit"]))
+	if err != nil {
+		return Page[T]{}, err
+	}
+	sort.Slice(items, func(i, j int) bool { return key(items[i]) < key(items[j]) })
+
+	start := 0
+	if after := first(q["continue"]); after != "" {
+		start = sort.Search(len(items), func(i int) bool { return key(items[i]) > after })
+	}
+	end := min(start+limit, len(items))
+
+	page := Page[T]{Items: items[start:end]}
+	if end < len(items) {
+		page.Continue = key(items[end-1])
+	}
+	return page, nil
+}
+
+func parseLimit(raw string) (int, error) {
+	if raw == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, errBadRequest
+	}
+	return min(limit, maxLimit), nil
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func namespaceOption(namespace string) []client.ListOption {
+	if namespace == "" {
+		return nil
+	}
+	return []client.ListOption{client.InNamespace(namespace)}
+}
+
+func getOnly(fn http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		fn(w, req)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, req *http.Request, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logf.FromContext(req.Context()).Error(err, "failed writing API response", "path", req.URL.Path)
+	}
+}
+
+func writeError(w http.ResponseWriter, req *http.Request, err error) {
+	if errors.Is(err, errBadRequest) {
+		http.Error(w, "invalid query parameters", http.StatusBadRequest)
+		return
+	}
+	logf.FromContext(req.Context()).Error(err, "API request failed", "path", req.URL.Path)
+	http.Error(w, "internal error", http.StatusInternalServerError)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Handler", func() {
+	var h *Handler
+
+	newReport := func(namespace, name, profile string, phase watchdogv1alpha1.ReportPhase) client.Object {
+		return &watchdogv1alpha1.PolicyViolationReport{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+				ProfileName: profile,
+				Drift:       map[string]string{"foo": "Expected: bar, Got: "},
+				ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{
+					Kind: "NetworkPolicy", Name: name, Namespace: namespace,
+				},
+			},
+			Status: watchdogv1alpha1.PolicyViolationReportStatus{Phase: phase},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(watchdogv1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newReport("team-a", "v1", "require-foo", watchdogv1alpha1.ReportPhaseOpen),
+			newReport("team-a", "v2", "require-foo", ""),
+			newReport("team-a", "v3", "require-foo", watchdogv1alpha1.ReportPhaseResolved),
+			newReport("team-b", "v4", "other", watchdogv1alpha1.ReportPhaseOpen),
+			&watchdogv1alpha1.PolicyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-foo", Namespace: "team-a"},
+				Status: watchdogv1alpha1.PolicyProfileStatus{Namespaces: []watchdogv1alpha1.NamespaceSummary{
+					{Namespace: "team-a", Matched: 4, Violating: 1},
+				}},
+			},
+			&watchdogv1alpha1.PolicyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "templated", Namespace: "team-c"},
+				Spec: watchdogv1alpha1.PolicyProfileSpec{
+					Policy:   map[string]string{"tier": "web"},
+					Template: &watchdogv1alpha1.TemplateReference{Name: "owned"},
+				},
+			},
+			&watchdogv1alpha1.PolicyTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "owned"},
+				Spec: watchdogv1alpha1.PolicyTemplateSpec{Labels: []watchdogv1alpha1.LabelRule{
+					{Key: "owner", Value: "team-c"}, {Key: "app"},
+				}},
+			},
+			&watchdogv1alpha1.PolicyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "team-b"},
+				Status: watchdogv1alpha1.PolicyProfileStatus{Namespaces: []watchdogv1alpha1.NamespaceSummary{
+					{Namespace: "team-a", Matched: 4, Violating: 3},
+					{Namespace: "team-b", Matched: 0},
+				}},
+			},
+		).Build()
+		h = &Handler{Reader: cl}
+	})
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		path, _, _ := strings.Cut(target, "?")
+		h.Routes()[path].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	It("should page through open violations", func() {
+		rec := get("/api/v1/violations?namespace=team-a&limit=1")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var page Page[Violation]
+		Expect(json.Unmarshal(rec.Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].Name).To(Equal("v1"))
+		Expect(page.Continue).To(Equal("team-a/v1"))
+
+		rec = get("/api/v1/violations?namespace=team-a&limit=1&continue=" + page.Continue)
+		page = Page[Violation]{}
+		Expect(json.Unmarshal(rec.Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].Name).To(Equal("v2"))
+		Expect(page.Items[0].Phase).To(Equal(watchdogv1alpha1.ReportPhaseOpen))
+		Expect(page.Items[0].Severity).To(Equal(watchdogv1alpha1.SeverityMedium))
+		Expect(page.Continue).To(BeEmpty())
+	})
+
+	It("should filter violations by phase and profile", func() {
+		var page Page[Violation]
+		Expect(json.Unmarshal(get("/api/v1/violations?phase=resolved").Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].Name).To(Equal("v3"))
+
+		page = Page[Violation]{}
+		Expect(json.Unmarshal(get("/api/v1/violations?phase=all&profile=other").Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].Namespace).To(Equal("team-b"))
+
+		page = Page[Violation]{}
+		Expect(json.Unmarshal(get("/api/v1/violations?severity=high").Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(BeEmpty())
+	})
+
+	It("should aggregate compliance scores per namespace", func() {
+		var page Page[NamespaceCompliance]
+		Expect(json.Unmarshal(get("/api/v1/compliance").Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(HaveLen(2))
+		Expect(page.Items[0]).To(Equal(NamespaceCompliance{
+			Namespace: "team-a", Matched: 8, Violating: 4, Score: 0.5, Profiles: 2,
+		}))
+		Expect(page.Items[1].Score).To(Equal(1.0))
+	})
+
+	It("should show the label rules profiles get from their template", func() {
+		var page Page[Profile]
+		Expect(json.Unmarshal(get("/api/v1/profiles?namespace=team-c").Body.Bytes(), &page)).To(Succeed())
+		Expect(page.Items).To(HaveLen(1))
+		Expect(page.Items[0].Policy).To(Equal(map[string]string{"tier": "web", "owner": "team-c"}))
+		Expect(page.Items[0].Required).To(Equal([]string{"app"}))
+	})
+
+	It("should reject invalid parameters and answer 501 without history", func() {
+		Expect(get("/api/v1/profiles?limit=-3").Code).To(Equal(http.StatusBadRequest))
+		Expect(get("/api/v1/history").Code).To(Equal(http.StatusNotImplemented))
+	})
+})
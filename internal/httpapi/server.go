@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpapi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Options configures the API server.
+type Options struct {
+	// BindAddress is the address the API binds to, "0" disables it.
+	BindAddress   string
+	SecureServing bool
+	TLSOpts       []func(*tls.Config)
+	// FilterProvider protects every endpoint, normally with the same
+	// authentication and authorization filter as the metrics endpoint.
+	FilterProvider func(c *rest.Config, httpClient *http.Client) (metricsserver.Filter, error)
+	// ExtraHandlers are served next to the API routes. Handlers wrapped with
+	// Public bypass the filter.
+	ExtraHandlers map[string]http.Handler
+}
+
+// publicHandler marks a handler the filter must not wrap, either because it
+// serves static content or because it authenticates requests on its own.
+type publicHandler struct {
+	http.Handler
+}
+
+// Public marks h as exempt from the server filter.
+func Public(h http.Handler) http.Handler {
+	return publicHandler{h}
+}
+
+// Server serves the handler routes on a listener of its own. Unlike the
+// metrics server it is modelled on, it serves nothing but the routes it is
+// given: /metrics stays on the metrics address.
+type Server struct {
+	opts   Options
+	routes map[string]http.Handler
+	filter metricsserver.Filter
+}
+
+// NewServer returns a runnable serving the handler routes and the extra
+// handlers of opts, each protected by the filter of opts unless public.
+func NewServer(h *Handler, opts Options, config *rest.Config, httpClient *http.Client) (*Server, error) {
+	routes := h.Routes()
+	maps.Copy(routes, opts.ExtraHandlers)
+	s := &Server{opts: opts, routes: routes}
+	if provider := skipPublic(opts.FilterProvider); provider != nil {
+		filter, err := provider(config, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating the filter of the API server: %w", err)
+		}
+		s.filter = filter
+	}
+	return s, nil
+}
+
+// NeedLeaderElection lets every replica serve the API.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the API until the context is cancelled. It implements
+// manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	if s.opts.BindAddress == "0" {
+		return nil
+	}
+	log := logf.FromContext(ctx).WithName("compliance-api")
+	handler, err := s.handler(log)
+	if err != nil {
+		return err
+	}
+	listener, err := s.listen(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to start the API server: %w", err)
+	}
+
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 32 * time.Second}
+	idleConnsClosed := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Error(err, "error shutting down the API server")
+		}
+		close(idleConnsClosed)
+	}()
+
+	log.Info("Serving compliance API", "bindAddress", s.opts.BindAddress, "secure", s.opts.SecureServing)
+	if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-idleConnsClosed
+	return nil
+}
+
+// handler routes requests to the routes of the server, wrapped in its filter.
+func (s *Server) handler(log logr.Logger) (http.Handler, error) {
+	mux := http.NewServeMux()
+	for path, h := range s.routes {
+		if s.filter != nil {
+			var err error
+			if h, err = s.filter(log.WithValues("path", path), h); err != nil {
+				return nil, fmt.Errorf("failed adding the filter to %s: %w", path, err)
+			}
+		}
+		mux.Handle(path, h)
+	}
+	return mux, nil
+}
+
+// listen opens the listener of the server, serving TLS with a self-signed
+// certificate unless the TLS options provide one.
+func (s *Server) listen(ctx context.Context) (net.Listener, error) {
+	var lc net.ListenConfig
+	l, err := lc.Listen(ctx, "tcp", s.opts.BindAddress)
+	if err != nil || !s.opts.SecureServing {
+		return l, err
+	}
+	cfg := &tls.Config{NextProtos: []string{"h2"}}
+	for _, op := range s.opts.TLSOpts {
+		op(cfg)
+	}
+	if cfg.GetCertificate == nil {
+		cert, key, err := certutil.GenerateSelfSignedCertKeyWithFixtures("localhost", []net.IP{{127, 0, 0, 1}}, nil, "")
+		if err != nil {
+			_ = l.Close()
+			return nil, fmt.Errorf("failed to generate a self-signed certificate: %w", err)
+		}
+		keyPair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			_ = l.Close()
+			return nil, fmt.Errorf("failed to create a self-signed key pair: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{keyPair}
+	}
+	return tls.NewListener(l, cfg), nil
+}
+
+// skipPublic wraps provider so that the returned filter leaves public handlers alone.
+func skipPublic(
+	provider func(*rest.Config, *http.Client) (metricsserver.Filter, error),
+) func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+	if provider == nil {
+		return nil
+	}
+	return func(c *rest.Config, httpClient *http.Client) (metricsserver.Filter, error) {
+		filter, err := provider(c, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		return func(log logr.Logger, handler http.Handler) (http.Handler, error) {
+			if _, ok := handler.(publicHandler); ok {
+				return handler, nil
+			}
+			return filter(log, handler)
+		}, nil
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+var _ = Describe("Server", func() {
+	deny := func(*rest.Config, *http.Client) (metricsserver.Filter, error) {
+		return func(_ logr.Logger, _ http.Handler) (http.Handler, error) {
+			return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			}), nil
+		}, nil
+	}
+
+	It("should serve its routes only, behind the filter", func() {
+		ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+		s, err := NewServer(&Handler{}, Options{
+			BindAddress:    ":0",
+			FilterProvider: deny,
+			ExtraHandlers:  map[string]http.Handler{"/dashboard/": Public(ok)},
+		}, nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+		handler, err := s.handler(logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+
+		for path, code := range map[string]int{
+			"/api/v1/profiles":      http.StatusForbidden,
+			"/dashboard/index.html": http.StatusOK,
+			"/metrics":              http.StatusNotFound,
+		} {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			Expect(rec.Code).To(Equal(code), path)
+		}
+	})
+
+	It("should not filter public handlers", func() {
+		filter, err := skipPublic(deny)(nil, nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+		for _, tc := range []struct {
+			handler http.Handler
+			code    int
+		}{{ok, http.StatusForbidden}, {Public(ok), http.StatusOK}} {
+			wrapped, err := filter(logr.Discard(), tc.handler)
+			Expect(err).NotTo(HaveOccurred())
+			rec := httptest.NewRecorder()
+			wrapped.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			Expect(rec.Code).To(Equal(tc.code))
+		}
+		Expect(skipPublic(nil)).To(BeNil())
+	})
+})
@@ -0,0 +1,247 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+const (
+	// DefaultAlertRefreshInterval is how often open alerts are resent to
+	// channels that do not set a refresh interval.
+	DefaultAlertRefreshInterval = time.Minute
+	// alertLifetimeIntervals is the number of refresh intervals an alert
+	// outlives its last refresh by.
+	alertLifetimeIntervals = 4
+	// AlertName is the alertname label of violation alerts.
+	AlertName = "PolicyViolation"
+)
+
+// Alertmanager pushes violations as alerts to the Alertmanager v2 API.
+// Alertmanager ends alerts once their endsAt passes, so the alerts of open
+// violations are refreshed by a Refresher and expire once the violation is
+// resolved, unless the resolution ends them first.
+type Alertmanager struct {
+	name   string
+	url    string
+	opts   AlertmanagerOptions
+	client *http.Client
+}
+
+// AlertmanagerOptions configures the alerts of an Alertmanager channel.
+type AlertmanagerOptions struct {
+	// Labels are added to every alert.
+	Labels map[string]string
+	// RemediationURL renders the remediation_url annotation when set.
+	RemediationURL *template.Template
+	// RefreshInterval is how often alerts are resent,
+	// DefaultAlertRefreshInterval if zero.
+	RefreshInterval time.Duration
+	// Headers are added to every request.
+	Headers map[string]string
+	// Client sends the requests, a default client if nil.
+	Client *http.Client
+}
+
+// NewAlertmanager returns a channel pushing alerts to the Alertmanager
+// serving baseURL.
+func NewAlertmanager(name, baseURL string, opts AlertmanagerOptions) *Alertmanager {
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = DefaultAlertRefreshInterval
+	}
+	c := opts.Client
+	if c == nil {
+		c = &http.Client{}
+	}
+	return &Alertmanager{name: name, url: strings.TrimSuffix(baseURL, "/") + "/api/v2/alerts", opts: opts, client: c}
+}
+
+// CloseIdleConnections closes the idle connections of the client given in
+// the options, if any.
+func (a *Alertmanager) CloseIdleConnections() {
+	if a.opts.Client != nil {
+		a.opts.Client.CloseIdleConnections()
+	}
+}
+
+// Name implements Channel.
+func (a *Alertmanager) Name() string { return a.name }
+
+// RefreshInterval implements Refreshing.
+func (a *Alertmanager) RefreshInterval() time.Duration { return a.opts.RefreshInterval }
+
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// Send implements Channel, firing the alert of the violation. Alertmanager
+// identifies alerts by their labels, so a resend updates the same alert.
+func (a *Alertmanager) Send(ctx context.Context, m *Message, _ string) (string, error) {
+	al, err := a.alert(m, a.expiry())
+	if err != nil {
+		return "", err
+	}
+	return "", a.post(ctx, []alert{al})
+}
+
+// SendResolved implements Resolver, ending the alert of the violation now.
+func (a *Alertmanager) SendResolved(ctx context.Context, m *Message, _ *v1alpha1.DeliveryStatus, _ string) (string, error) {
+	end := time.Now()
+	if at := m.Report.Status.ResolvedAt; at != nil {
+		end = at.Time
+	}
+	al, err := a.alert(m, end)
+	if err != nil {
+		return "", err
+	}
+	return "", a.post(ctx, []alert{al})
+}
+
+// Refresh implements Refreshing, resending the alerts of ms in one request.
+// Messages whose alert cannot be rendered are skipped and reported.
+func (a *Alertmanager) Refresh(ctx context.Context, ms []*Message) error {
+	alerts := make([]alert, 0, len(ms))
+	var errs []error
+	end := a.expiry()
+	for _, m := range ms {
+		al, err := a.alert(m, end)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("report %s/%s: %w", m.Report.Namespace, m.Report.Name, err))
+			continue
+		}
+		alerts = append(alerts, al)
+	}
+	if len(alerts) > 0 {
+		errs = append(errs, a.post(ctx, alerts))
+	}
+	return errors.Join(errs...)
+}
+
+// SendDigest implements Channel. Alerts are per violation, so Alertmanager
+// channels reject batching and digests.
+func (a *Alertmanager) SendDigest(context.Context, *Digest, string) (string, error) {
+	return "", fmt.Errorf("%w: alertmanager channels do not send digests", ErrPermanent)
+}
+
+// SendNotice implements Channel. Notices are not alerts and are dropped.
+func (a *Alertmanager) SendNotice(context.Context, *Notice, string) (string, error) {
+	return "", nil
+}
+
+func (a *Alertmanager) expiry() time.Time {
+	return time.Now().Add(alertLifetimeIntervals * a.opts.RefreshInterval)
+}
+
+func (a *Alertmanager) alert(m *Message, endsAt time.Time) (alert, error) {
+	res := m.Resource
+	labels := maps.Clone(a.opts.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range map[string]string{
+		"alertname": AlertName,
+		"profile":   m.Report.Spec.ProfileName,
+		"namespace": res.Namespace,
+		"kind":      res.Kind,
+		"resource":  res.Name,
+		"severity":  string(m.Severity),
+		"cluster":   m.Cluster,
+		"team":      ownerTeam(m.Report),
+	} {
+		// Alertmanager treats empty labels as unset
+		if v != "" {
+			labels[k] = v
+		}
+	}
+
+	var drift strings.Builder
+	for _, d := range m.Drift {
+		fmt.Fprintf(&drift, "%s: %s\n", d.Key, d.Value)
+	}
+	annotations := map[string]string{
+		"summary":     fmt.Sprintf("%s %s/%s violates %s", res.Kind, res.Namespace, res.Name, m.Report.Spec.ProfileName),
+		"description": strings.TrimSuffix(drift.String(), "\n"),
+		"report":      m.Report.Namespace + "/" + m.Report.Name,
+	}
+	if a.opts.RemediationURL != nil {
+		var b bytes.Buffer
+		if err := a.opts.RemediationURL.Execute(&b, m); err != nil {
+			return alert{}, fmt.Errorf("%w: rendering remediation URL: %v", ErrPermanent, err)
+		}
+		annotations["remediation_url"] = b.String()
+	}
+	return alert{Labels: labels, Annotations: annotations, StartsAt: m.Report.Opened(), EndsAt: endsAt}, nil
+}
+
+// ownerTeam returns the team owning the resource of rep, if known.
+func ownerTeam(rep *v1alpha1.PolicyViolationReport) string {
+	if o := rep.Spec.Ownership; o != nil {
+		return o.Team
+	}
+	return ""
+}
+
+func (a *Alertmanager) post(ctx context.Context, alerts []alert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	for k, v := range a.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return &RetryAfterError{
+			After: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:   fmt.Errorf("alertmanager error: %s", resp.Status),
+		}
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("alertmanager error: %s", resp.Status)
+	default:
+		return fmt.Errorf("%w: alertmanager error: %s: %s", ErrPermanent, resp.Status, bytes.TrimSpace(respBody))
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// alertmanagerStandIn records the alerts pushed to the v2 API.
+func alertmanagerStandIn(posts *[][]alert) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.Method).To(Equal(http.MethodPost))
+		Expect(r.URL.Path).To(Equal("/api/v2/alerts"))
+		var alerts []alert
+		if err := json.NewDecoder(r.Body).Decode(&alerts); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*posts = append(*posts, alerts)
+	}))
+	DeferCleanup(srv.Close)
+	return srv
+}
+
+var _ = Describe("Alertmanager", func() {
+	var (
+		posts [][]alert
+		srv   *httptest.Server
+	)
+
+	BeforeEach(func() {
+		posts = nil
+		srv = alertmanagerStandIn(&posts)
+	})
+
+	It("fires alerts labelled after the violation", func() {
+		remediation, err := ParseTemplate("remediationURL", "https://wiki.example.com/{{ .Report.Spec.ProfileName }}")
+		Expect(err).NotTo(HaveOccurred())
+		am := NewAlertmanager("am", srv.URL+"/", AlertmanagerOptions{
+			Labels:          map[string]string{"team": "platform", "severity": "overridden"},
+			RemediationURL:  remediation,
+			RefreshInterval: time.Minute,
+		})
+		msg := SampleMessage("prod-eu")
+		opened := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		msg.Report.Status.OpenedAt = &metav1.Time{Time: opened}
+
+		_, err = am.Send(context.Background(), msg, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(posts).To(HaveLen(1))
+		al := posts[0][0]
+		Expect(al.Labels).To(Equal(map[string]string{
+			"alertname": AlertName,
+			"profile":   "require-owner",
+			"namespace": "team-a",
+			"kind":      "NetworkPolicy",
+			"resource":  "allow-web",
+			"severity":  "high",
+			"cluster":   "prod-eu",
+			"team":      "platform",
+		}))
+		Expect(al.Annotations).To(HaveKeyWithValue("remediation_url", "https://wiki.example.com/require-owner"))
+		Expect(al.Annotations).To(HaveKeyWithValue("description", "owner: missing\ntier: expected backend, got frontend"))
+		Expect(al.StartsAt).To(BeTemporally("==", opened))
+		Expect(al.EndsAt).To(BeTemporally("~", time.Now().Add(4*time.Minute), 5*time.Second))
+	})
+
+	It("ends the alert on resolution", func() {
+		am := NewAlertmanager("am", srv.URL, AlertmanagerOptions{})
+		msg := SampleMessage("")
+		resolved := time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)
+		msg.Report.Status.ResolvedAt = &metav1.Time{Time: resolved}
+		_, err := am.SendResolved(context.Background(), msg, nil, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(posts[0][0].EndsAt).To(BeTemporally("==", resolved))
+		Expect(posts[0][0].Labels).NotTo(HaveKey("cluster"))
+	})
+
+	It("rejects digests permanently", func() {
+		_, err := NewAlertmanager("am", srv.URL, AlertmanagerOptions{}).SendDigest(context.Background(), &Digest{}, "key")
+		Expect(errors.Is(err, ErrPermanent)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Refresher", func() {
+	var (
+		posts [][]alert
+		srv   *httptest.Server
+		now   time.Time
+	)
+
+	BeforeEach(func() {
+		posts = nil
+		srv = alertmanagerStandIn(&posts)
+		now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	It("resends the alerts of open violations that were sent", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		sent := newReport("1", "deny-all", "team-a", "np-1")
+		sent.Status.Deliveries = []v1alpha1.DeliveryStatus{{Channel: "am", State: v1alpha1.DeliverySent}}
+		resolved := newReport("2", "deny-all", "team-a", "np-2")
+		resolved.Status.Deliveries = sent.Status.Deliveries
+		resolved.Status.Phase = v1alpha1.ReportPhaseResolved
+		suppressed := newReport("3", "deny-all", "team-a", "np-3")
+		suppressed.Status.Deliveries = []v1alpha1.DeliveryStatus{{Channel: "am", State: v1alpha1.DeliverySuppressed}}
+		acked := newReport("4", "deny-all", "team-a", "np-4")
+		acked.Status.Deliveries = sent.Status.Deliveries
+		acked.Spec.Acknowledgement = &v1alpha1.Acknowledgement{By: "alice", At: metav1.NewTime(now)}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			sent, resolved, suppressed, acked,
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "am"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type: v1alpha1.ChannelTypeAlertmanager,
+					Alertmanager: &v1alpha1.AlertmanagerChannelSpec{
+						URL:             srv.URL,
+						RefreshInterval: &metav1.Duration{Duration: time.Minute},
+					},
+				},
+			},
+		).Build()
+		r := &Refresher{Client: cl, now: func() time.Time { return now }}
+
+		Expect(r.Run(context.Background())).To(Succeed())
+		Expect(posts).To(HaveLen(1))
+		Expect(posts[0]).To(HaveLen(1))
+		Expect(posts[0][0].Labels).To(HaveKeyWithValue("resource", "np-1"))
+
+		By("waiting for the refresh interval")
+		now = now.Add(30 * time.Second)
+		Expect(r.Run(context.Background())).To(Succeed())
+		Expect(posts).To(HaveLen(1))
+
+		now = now.Add(30 * time.Second)
+		Expect(r.Run(context.Background())).To(Succeed())
+		Expect(posts).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,205 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// RefsRecheckInterval is the time a ChannelCache trusts the Secrets and
+// ConfigMaps of a channel not to have changed before checking them again.
+const RefsRecheckInterval = time.Minute
+
+// ChannelCache loads the targets of the NotificationChannels like Load, but
+// keeps the channels it builds until their NotificationChannel changes or one
+// of the Secrets and ConfigMaps they refer to does, so that loading the
+// targets on every reconcile neither reads the references nor parses the
+// templates again. The references are checked by their metadata, at most
+// every RefsRecheckInterval. A ChannelCache is safe for concurrent use.
+type ChannelCache struct {
+	mu       sync.Mutex
+	channels map[string]*cachedChannel
+	now      func() time.Time
+}
+
+// cachedChannel is a channel built from a version of its NotificationChannel.
+type cachedChannel struct {
+	uid     types.UID
+	version string
+	channel Channel
+	// refs are the resource versions of the Secrets and ConfigMaps read to
+	// build the channel, empty for the missing ones.
+	refs    map[reference]string
+	checked time.Time
+	// volatile channels failed to read a reference for another reason than
+	// it missing, and are built again on the next load.
+	volatile bool
+}
+
+// reference is a Secret or ConfigMap a channel refers to.
+type reference struct {
+	kind string
+	key  types.NamespacedName
+}
+
+// NewChannelCache returns an empty ChannelCache.
+func NewChannelCache() *ChannelCache {
+	return &ChannelCache{channels: map[string]*cachedChannel{}}
+}
+
+// Load returns the targets of all NotificationChannels listed from c,
+// building the channels that changed with the Secrets and ConfigMaps read
+// from refs.
+func (cc *ChannelCache) Load(ctx context.Context, c, refs client.Reader) ([]Target, error) {
+	var list v1alpha1.NotificationChannelList
+	if err := c.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed listing NotificationChannels: %w", err)
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	now := cc.clock()
+	listed := make(map[string]bool, len(list.Items))
+	targets := make([]Target, 0, len(list.Items))
+	for i := range list.Items {
+		nc := &list.Items[i]
+		listed[nc.Name] = true
+		cached := cc.channels[nc.Name]
+		if !cached.current(ctx, refs, nc, now) {
+			cached.close()
+			cached = buildCached(ctx, refs, nc, now)
+			cc.channels[nc.Name] = cached
+		}
+		targets = append(targets, newTarget(nc, cached.channel))
+	}
+	for name := range cc.channels {
+		if !listed[name] {
+			cc.channels[name].close()
+			delete(cc.channels, name)
+		}
+	}
+	return targets, nil
+}
+
+func (cc *ChannelCache) clock() time.Time {
+	if cc.now != nil {
+		return cc.now()
+	}
+	return time.Now()
+}
+
+// buildCached builds the channel of nc, recording the references it reads.
+func buildCached(ctx context.Context, refs client.Reader, nc *v1alpha1.NotificationChannel, now time.Time) *cachedChannel {
+	rec := &recorder{Reader: refs, refs: map[reference]string{}}
+	ch, err := build(ctx, rec, nc)
+	if err != nil {
+		ch = broken{name: nc.Name, err: err}
+	}
+	return &cachedChannel{
+		uid:      nc.UID,
+		version:  nc.ResourceVersion,
+		channel:  ch,
+		refs:     rec.refs,
+		checked:  now,
+		volatile: rec.failed,
+	}
+}
+
+// current reports whether c was built from nc and the references it read are
+// unchanged.
+func (c *cachedChannel) current(ctx context.Context, refs client.Reader, nc *v1alpha1.NotificationChannel, now time.Time) bool {
+	if c == nil || c.volatile || c.uid != nc.UID || c.version != nc.ResourceVersion {
+		return false
+	}
+	if now.Sub(c.checked) < RefsRecheckInterval {
+		return true
+	}
+	for ref, version := range c.refs {
+		if v, err := ref.version(ctx, refs); err != nil || v != version {
+			return false
+		}
+	}
+	c.checked = now
+	return true
+}
+
+// close releases the idle connections of a channel that is replaced, such as
+// the transport of its client certificate. Deliveries still using it open
+// new connections.
+func (c *cachedChannel) close() {
+	if c == nil {
+		return
+	}
+	if ch, ok := c.channel.(interface{ CloseIdleConnections() }); ok {
+		ch.CloseIdleConnections()
+	}
+}
+
+// version returns the resource version of the referenced object, empty if
+// it does not exist.
+func (r reference) version(ctx context.Context, c client.Reader) (string, error) {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind(r.kind))
+	if err := c.Get(ctx, r.key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return obj.ResourceVersion, nil
+}
+
+// recorder records the resource versions of the Secrets and ConfigMaps read
+// through it.
+type recorder struct {
+	client.Reader
+	refs   map[reference]string
+	failed bool
+}
+
+func (r *recorder) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := r.Reader.Get(ctx, key, obj, opts...)
+	var kind string
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	default:
+		return err
+	}
+	switch {
+	case err == nil:
+		r.refs[reference{kind: kind, key: key}] = obj.GetResourceVersion()
+	case apierrors.IsNotFound(err):
+		r.refs[reference{kind: kind, key: key}] = ""
+	default:
+		r.failed = true
+	}
+	return err
+}
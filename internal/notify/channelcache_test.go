@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("ChannelCache", func() {
+	var (
+		ctx   = context.Background()
+		cl    client.Client
+		cache *ChannelCache
+		now   time.Time
+		reads int
+	)
+	ref := v1alpha1.SecretKeySelector{Name: "slack", Namespace: "gokubedog-system", Key: "url"}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		reads = 0
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "ops", UID: "ops-uid"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type:  v1alpha1.ChannelTypeSlack,
+					Slack: &v1alpha1.SlackChannelSpec{WebhookURLSecretRef: &ref},
+				},
+			},
+		).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Secret); ok {
+					reads++
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+		now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		cache = NewChannelCache()
+		cache.now = func() time.Time { return now }
+	})
+
+	load := func() Channel {
+		targets, err := cache.Load(ctx, cl, cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(1))
+		return targets[0].Channel
+	}
+	createSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace},
+			Data:       map[string][]byte{"url": []byte("http://hooks.example.com/a")},
+		}
+		Expect(cl.Create(ctx, secret)).To(Succeed())
+		return secret
+	}
+
+	It("builds a channel once while nothing changes", func() {
+		createSecret()
+		ch := load()
+		Expect(ch).To(BeAssignableToTypeOf(&Slack{}))
+		Expect(reads).To(Equal(1))
+
+		Expect(load()).To(BeIdenticalTo(ch))
+		now = now.Add(RefsRecheckInterval)
+		Expect(load()).To(BeIdenticalTo(ch))
+		Expect(reads).To(Equal(1))
+	})
+
+	It("builds the channel again when it or its Secret changes", func() {
+		secret := createSecret()
+		ch := load()
+
+		secret.Data["url"] = []byte("http://hooks.example.com/b")
+		Expect(cl.Update(ctx, secret)).To(Succeed())
+		Expect(load()).To(BeIdenticalTo(ch), "the Secret is trusted until the recheck")
+		now = now.Add(RefsRecheckInterval)
+		rotated := load()
+		Expect(rotated).NotTo(BeIdenticalTo(ch))
+		Expect(reads).To(Equal(2))
+
+		nc := &v1alpha1.NotificationChannel{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "ops"}, nc)).To(Succeed())
+		nc.Spec.Slack.Format = v1alpha1.SlackFormatText
+		Expect(cl.Update(ctx, nc)).To(Succeed())
+		Expect(load()).NotTo(BeIdenticalTo(rotated))
+		Expect(reads).To(Equal(3))
+	})
+
+	It("builds a broken channel again once its Secret exists", func() {
+		Expect(load()).To(BeAssignableToTypeOf(broken{}))
+		Expect(load()).To(BeAssignableToTypeOf(broken{}))
+		Expect(reads).To(Equal(1))
+
+		createSecret()
+		now = now.Add(RefsRecheckInterval)
+		Expect(load()).To(BeAssignableToTypeOf(&Slack{}))
+	})
+
+	It("closes the idle connections of the channels it replaces", func() {
+		certPEM, keyPEM := clientCertificate()
+		pool := x509.NewCertPool()
+		Expect(pool.AppendCertsFromPEM(certPEM)).To(BeTrue())
+		var closed atomic.Int32
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+		srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateClosed {
+				closed.Add(1)
+			}
+		}
+		srv.StartTLS()
+		DeferCleanup(srv.Close)
+
+		tlsRef := &v1alpha1.SecretReference{Name: "bus-tls", Namespace: "gokubedog-system"}
+		Expect(cl.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: tlsRef.Name, Namespace: tlsRef.Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				"tls.crt": certPEM, "tls.key": keyPEM,
+				"ca.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}),
+			},
+		})).To(Succeed())
+		nc := &v1alpha1.NotificationChannel{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "ops"}, nc)).To(Succeed())
+		nc.Spec = v1alpha1.NotificationChannelSpec{
+			Type:    v1alpha1.ChannelTypeWebhook,
+			Webhook: &v1alpha1.WebhookChannelSpec{URL: srv.URL, ClientCertSecretRef: tlsRef},
+		}
+		Expect(cl.Update(ctx, nc)).To(Succeed())
+
+		_, err := load().Send(ctx, SampleMessage(""), "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(closed.Load()).To(BeZero())
+
+		nc.Spec.Webhook.Mode = v1alpha1.CloudEventsBinary
+		Expect(cl.Update(ctx, nc)).To(Succeed())
+		load()
+		Eventually(closed.Load).Should(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,355 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// Target is a channel together with the policy its deliveries follow.
+type Target struct {
+	Channel Channel
+	Policy  Policy
+	// Batch is the batching window of the channel, 0 if it sends one message
+	// per violation.
+	Batch time.Duration
+	// Object is the NotificationChannel the target was built from, nil for
+	// channels configured otherwise.
+	Object *v1alpha1.NotificationChannel
+}
+
+// Resolver returns the channel of t if it sends resolutions.
+func (t Target) Resolver() (Resolver, bool) {
+	if t.Object == nil || !t.Object.Spec.SendResolved || t.Batch > 0 {
+		return nil, false
+	}
+	r, ok := t.Channel.(Resolver)
+	return r, ok
+}
+
+// Routes reports whether the violation of rep is routed to t.
+func (t Target) Routes(rep *v1alpha1.PolicyViolationReport) bool {
+	return t.Object == nil || t.Object.Spec.Route.Matches(rep.Spec.Ownership)
+}
+
+// Load builds the targets of all NotificationChannels listed from c, reading
+// the Secrets and ConfigMaps they refer to from refs. A channel that cannot
+// be built, e.g. because its Secret is missing, is still returned and fails
+// every attempt with a retryable error, so its deliveries stay visible on the
+// reports. They are retried with the backoff of the channel, and delivered if
+// it is fixed in time, but dead-lettered once they run out of attempts.
+func Load(ctx context.Context, c, refs client.Reader) ([]Target, error) {
+	var list v1alpha1.NotificationChannelList
+	if err := c.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed listing NotificationChannels: %w", err)
+	}
+	targets := make([]Target, 0, len(list.Items))
+	for i := range list.Items {
+		nc := &list.Items[i]
+		ch, err := build(ctx, refs, nc)
+		if err != nil {
+			ch = broken{name: nc.Name, err: err}
+		}
+		targets = append(targets, newTarget(nc, ch))
+	}
+	return targets, nil
+}
+
+// newTarget returns the target of the channel built from nc.
+func newTarget(nc *v1alpha1.NotificationChannel, ch Channel) Target {
+	t := Target{Channel: ch, Policy: PolicyFromSpec(&nc.Spec), Object: nc}
+	if nc.Spec.Batch != nil {
+		t.Batch = nc.Spec.Batch.Window.Duration
+	}
+	return t
+}
+
+func build(ctx context.Context, c client.Reader, nc *v1alpha1.NotificationChannel) (Channel, error) {
+	switch nc.Spec.Type {
+	case v1alpha1.ChannelTypeSlack:
+		if nc.Spec.Slack == nil {
+			return nil, fmt.Errorf("slack is required for slack channels")
+		}
+		def := DefaultChannelTemplate(&nc.Spec)
+		tmpl, err := NewTemplate(nc.Spec.Template, def, func(ref v1alpha1.ConfigMapKeySelector) (string, error) {
+			return configMapValue(ctx, c, ref)
+		})
+		if err != nil {
+			return nil, err
+		}
+		opts := SlackOptions{
+			Template: tmpl,
+			Text:     nc.Spec.Slack.Format == v1alpha1.SlackFormatText,
+			Actions:  nc.Spec.Slack.Actions,
+		}
+		if ref := nc.Spec.Slack.BotTokenSecretRef; ref != nil {
+			token, err := secretValue(ctx, c, *ref)
+			if err != nil {
+				return nil, err
+			}
+			return NewSlackBot(nc.Name, token, nc.Spec.Slack.ChannelID, opts), nil
+		}
+		if nc.Spec.Slack.WebhookURLSecretRef == nil {
+			return nil, fmt.Errorf("slack channels require webhookURLSecretRef or botTokenSecretRef")
+		}
+		url, err := secretValue(ctx, c, *nc.Spec.Slack.WebhookURLSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		return NewSlack(nc.Name, url, opts), nil
+	case v1alpha1.ChannelTypePagerDuty:
+		if nc.Spec.PagerDuty == nil {
+			return nil, fmt.Errorf("pagerduty is required for pagerduty channels")
+		}
+		key, err := secretValue(ctx, c, nc.Spec.PagerDuty.RoutingKeySecretRef)
+		if err != nil {
+			return nil, err
+		}
+		return NewPagerDuty(nc.Name, key), nil
+	case v1alpha1.ChannelTypeWebhook:
+		if nc.Spec.Webhook == nil {
+			return nil, fmt.Errorf("webhook is required for webhook channels")
+		}
+		return buildWebhook(ctx, c, nc.Name, nc.Spec.Webhook)
+	case v1alpha1.ChannelTypeAlertmanager:
+		if nc.Spec.Alertmanager == nil {
+			return nil, fmt.Errorf("alertmanager is required for alertmanager channels")
+		}
+		return buildAlertmanager(ctx, c, nc.Name, nc.Spec.Alertmanager)
+	case v1alpha1.ChannelTypeEmail:
+		if nc.Spec.Email == nil {
+			return nil, fmt.Errorf("email is required for email channels")
+		}
+		return buildEmail(ctx, c, nc)
+	default:
+		return nil, fmt.Errorf("unsupported channel type %q", nc.Spec.Type)
+	}
+}
+
+func secretValue(ctx context.Context, c client.Reader, ref v1alpha1.SecretKeySelector) (string, error) {
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return "", fmt.Errorf("failed fetching Secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	v, ok := secret.Data[ref.Key]
+	if !ok || len(v) == 0 {
+		return "", fmt.Errorf("secret %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	return string(v), nil
+}
+
+func buildWebhook(ctx context.Context, c client.Reader, name string, spec *v1alpha1.WebhookChannelSpec) (Channel, error) {
+	opts := WebhookOptions{Binary: spec.Mode == v1alpha1.CloudEventsBinary}
+	if ref := spec.SigningSecretRef; ref != nil {
+		secret, err := secretValue(ctx, c, *ref)
+		if err != nil {
+			return nil, err
+		}
+		opts.SigningSecret = []byte(secret)
+	}
+	var err error
+	if opts.Headers, err = secretHeaders(ctx, c, spec.HeadersSecretRef); err != nil {
+		return nil, err
+	}
+	if opts.Client, err = httpClient(ctx, c, spec.ClientCertSecretRef); err != nil {
+		return nil, err
+	}
+	return NewWebhook(name, spec.URL, opts), nil
+}
+
+func buildAlertmanager(ctx context.Context, c client.Reader, name string, spec *v1alpha1.AlertmanagerChannelSpec) (Channel, error) {
+	opts := AlertmanagerOptions{Labels: spec.Labels}
+	if spec.RefreshInterval != nil {
+		opts.RefreshInterval = spec.RefreshInterval.Duration
+	}
+	if spec.RemediationURL != "" {
+		tmpl, err := ParseTemplate("remediationURL", spec.RemediationURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remediationURL template: %w", err)
+		}
+		opts.RemediationURL = tmpl
+	}
+	var err error
+	if opts.Headers, err = secretHeaders(ctx, c, spec.HeadersSecretRef); err != nil {
+		return nil, err
+	}
+	if opts.Client, err = httpClient(ctx, c, spec.ClientCertSecretRef); err != nil {
+		return nil, err
+	}
+	return NewAlertmanager(name, spec.URL, opts), nil
+}
+
+func buildEmail(ctx context.Context, c client.Reader, nc *v1alpha1.NotificationChannel) (Channel, error) {
+	spec := nc.Spec.Email
+	lookup := func(ref v1alpha1.ConfigMapKeySelector) (string, error) {
+		return configMapValue(ctx, c, ref)
+	}
+	from, err := mail.ParseAddress(spec.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", spec.From, err)
+	}
+	to := make([]*mail.Address, 0, len(spec.To))
+	for _, addr := range spec.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+		to = append(to, a)
+	}
+	if len(to) == 0 && spec.RecipientAnnotation == "" {
+		return nil, errors.New("email channel has neither recipients nor a recipient annotation")
+	}
+
+	server := spec.SMTP
+	if server.Port == 0 {
+		server.Port = 587
+	}
+	smtp := SMTPServer{Host: server.Host, Port: server.Port, ImplicitTLS: server.TLS == v1alpha1.SMTPImplicitTLS}
+	if ref := server.AuthSecretRef; ref != nil {
+		data, err := secretData(ctx, c, *ref)
+		if err != nil {
+			return nil, err
+		}
+		smtp.Username, smtp.Password = string(data["username"]), string(data["password"])
+		if smtp.Username == "" {
+			return nil, fmt.Errorf("secret %s/%s has no key %q", ref.Namespace, ref.Name, "username")
+		}
+	}
+
+	opts := EmailOptions{RecipientAnnotation: spec.RecipientAnnotation, Namespaces: c}
+	if opts.Text, err = NewTemplate(nc.Spec.Template, DefaultEmailTemplate, lookup); err != nil {
+		return nil, err
+	}
+	if src := spec.HTMLTemplate; src != nil {
+		text := src.Inline
+		if ref := src.ConfigMapKeyRef; ref != nil {
+			if text, err = lookup(*ref); err != nil {
+				return nil, err
+			}
+		}
+		if opts.HTML, err = ParseHTMLTemplate("html", text); err != nil {
+			return nil, fmt.Errorf("invalid html template: %w", err)
+		}
+	}
+	if spec.Subject != "" {
+		if opts.Subject, err = ParseTemplate("subject", spec.Subject); err != nil {
+			return nil, fmt.Errorf("invalid subject template: %w", err)
+		}
+	}
+	return NewEmail(nc.Name, from, to, smtp, opts), nil
+}
+
+// secretHeaders returns the keys and values of the Secret named by ref, nil
+// if ref is nil.
+func secretHeaders(ctx context.Context, c client.Reader, ref *v1alpha1.SecretReference) (map[string]string, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	data, err := secretData(ctx, c, *ref)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(data))
+	for k, v := range data {
+		headers[k] = string(v)
+	}
+	return headers, nil
+}
+
+// httpClient returns a client authenticating with the certificate of the
+// kubernetes.io/tls Secret named by ref, nil if ref is nil.
+func httpClient(ctx context.Context, c client.Reader, ref *v1alpha1.SecretReference) (*http.Client, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	data, err := secretData(ctx, c, *ref)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(data[corev1.TLSCertKey], data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in Secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if ca := data["ca.crt"]; len(ca) > 0 {
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("invalid ca.crt in Secret %s/%s", ref.Namespace, ref.Name)
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	return &http.Client{Transport: transport}, nil
+}
+
+func secretData(ctx context.Context, c client.Reader, ref v1alpha1.SecretReference) (map[string][]byte, error) {
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, fmt.Errorf("failed fetching Secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	return secret.Data, nil
+}
+
+func configMapValue(ctx context.Context, c client.Reader, ref v1alpha1.ConfigMapKeySelector) (string, error) {
+	var cm corev1.ConfigMap
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, &cm); err != nil {
+		return "", fmt.Errorf("failed fetching ConfigMap %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	v, ok := cm.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("configmap %s/%s has no key %q", ref.Namespace, ref.Name, ref.Key)
+	}
+	return v, nil
+}
+
+// broken is a channel whose configuration is invalid. Its deliveries fail
+// until they are dead-lettered, they are not sent again once it is fixed.
+type broken struct {
+	name string
+	err  error
+}
+
+func (b broken) Name() string { return b.name }
+
+func (b broken) Send(context.Context, *Message, string) (string, error) {
+	return "", b.err
+}
+
+func (b broken) SendDigest(context.Context, *Digest, string) (string, error) {
+	return "", b.err
+}
+
+func (b broken) SendNotice(context.Context, *Notice, string) (string, error) {
+	return "", b.err
+}
+
+func (b broken) SendResolved(context.Context, *Message, *v1alpha1.DeliveryStatus, string) (string, error) {
+	return "", b.err
+}
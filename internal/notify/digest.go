@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// DefaultTopOffenders is the number of resources listed as top offenders.
+const DefaultTopOffenders = 5
+
+// Digest summarizes many violations in one message.
+type Digest struct {
+	Title string
+	// Total is the number of violations summarized.
+	Total int
+	// Groups counts the violations per profile and namespace, largest first.
+	Groups []DigestGroup
+	// TopOffenders are the resources violating the most profiles.
+	TopOffenders []Offender
+}
+
+// DigestGroup counts the violations of one profile in one namespace.
+type DigestGroup struct {
+	Profile   string
+	Namespace string
+	Count     int
+}
+
+// Offender is a resource and the number of its violations.
+type Offender struct {
+	Resource v1alpha1.ViolatedResourceSpec
+	Count    int
+}
+
+// NewDigest summarizes reps.
+func NewDigest(title string, reps []*v1alpha1.PolicyViolationReport) *Digest {
+	d := &Digest{Title: title, Total: len(reps)}
+	groups := map[DigestGroup]int{}
+	offenders := map[v1alpha1.ViolatedResourceSpec]int{}
+	for _, rep := range reps {
+		groups[DigestGroup{Profile: rep.Spec.ProfileName, Namespace: rep.Spec.ViolatedResource.Namespace}]++
+		offenders[rep.Spec.ViolatedResource]++
+	}
+	for g, n := range groups {
+		g.Count = n
+		d.Groups = append(d.Groups, g)
+	}
+	slices.SortFunc(d.Groups, func(a, b DigestGroup) int {
+		return cmp.Or(b.Count-a.Count, cmp.Compare(a.Profile, b.Profile), cmp.Compare(a.Namespace, b.Namespace))
+	})
+	for res, n := range offenders {
+		d.TopOffenders = append(d.TopOffenders, Offender{Resource: res, Count: n})
+	}
+	slices.SortFunc(d.TopOffenders, func(a, b Offender) int {
+		return cmp.Or(b.Count-a.Count,
+			cmp.Compare(a.Resource.Namespace, b.Resource.Namespace),
+			cmp.Compare(a.Resource.Kind, b.Resource.Kind),
+			cmp.Compare(a.Resource.Name, b.Resource.Name))
+	})
+	if len(d.TopOffenders) > DefaultTopOffenders {
+		d.TopOffenders = d.TopOffenders[:DefaultTopOffenders]
+	}
+	return d
+}
+
+// BatchKey returns the idempotency key of the digest of reps sent to channel.
+// It is the same for every attempt to send the same batch.
+func BatchKey(channel string, reps []*v1alpha1.PolicyViolationReport) string {
+	uids := make([]string, len(reps))
+	for i, rep := range reps {
+		uids[i] = string(rep.UID)
+	}
+	slices.Sort(uids)
+	sum := sha256.Sum256([]byte(strings.Join(uids, ",")))
+	return fmt.Sprintf("batch-%x/%s", sum[:8], channel)
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+func newReport(uid, profile, namespace, name string) *v1alpha1.PolicyViolationReport {
+	return &v1alpha1.PolicyViolationReport{
+		ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid), Name: uid, Namespace: namespace},
+		Spec: v1alpha1.PolicyViolationReportSpec{
+			ProfileName: profile,
+			ViolatedResource: v1alpha1.ViolatedResourceSpec{
+				Kind: "NetworkPolicy", Namespace: namespace, Name: name,
+			},
+		},
+	}
+}
+
+var _ = Describe("Digest", func() {
+	reps := []*v1alpha1.PolicyViolationReport{
+		newReport("1", "deny-all", "team-a", "np-1"),
+		newReport("2", "deny-all", "team-a", "np-2"),
+		newReport("3", "labels", "team-a", "np-1"),
+		newReport("4", "deny-all", "team-b", "np-1"),
+	}
+
+	It("groups violations by profile and namespace, largest first", func() {
+		d := NewDigest("New policy violations", reps)
+		Expect(d.Total).To(Equal(4))
+		Expect(d.Groups).To(Equal([]DigestGroup{
+			{Profile: "deny-all", Namespace: "team-a", Count: 2},
+			{Profile: "deny-all", Namespace: "team-b", Count: 1},
+			{Profile: "labels", Namespace: "team-a", Count: 1},
+		}))
+		Expect(d.TopOffenders[0]).To(Equal(Offender{
+			Resource: v1alpha1.ViolatedResourceSpec{Kind: "NetworkPolicy", Namespace: "team-a", Name: "np-1"},
+			Count:    2,
+		}))
+		Expect(d.TopOffenders).To(HaveLen(3))
+	})
+
+	It("derives the batch key from the reports regardless of their order", func() {
+		reversed := []*v1alpha1.PolicyViolationReport{reps[3], reps[2], reps[1], reps[0]}
+		Expect(BatchKey("ops", reps)).To(Equal(BatchKey("ops", reversed)))
+		Expect(BatchKey("ops", reps)).NotTo(Equal(BatchKey("ops", reps[:3])))
+		Expect(BatchKey("ops", reps)).NotTo(Equal(BatchKey("dev", reps)))
+	})
+
+	It("is formatted for Slack with counts and top offenders", func() {
+		text := formatSlackDigest(NewDigest("Compliance digest", reps))
+		Expect(text).To(HavePrefix("*📋 Compliance digest*\n*4 violation(s)*\n"))
+		Expect(text).To(ContainSubstring("• deny-all in team-a: 2\n"))
+		Expect(text).To(ContainSubstring("*Top offenders:*\n• team-a/np-1 (NetworkPolicy): 2"))
+	})
+})
+
+var _ = Describe("Digester", func() {
+	var (
+		ctx    context.Context
+		bodies []string
+		srv    *httptest.Server
+		scheme *runtime.Scheme
+	)
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		bodies = nil
+		srv = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(b))
+		}))
+		DeferCleanup(srv.Close)
+		scheme = runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	})
+
+	newClient := func() client.Client {
+		open := newReport("1", "deny-all", "team-a", "np-1")
+		resolved := newReport("2", "deny-all", "team-a", "np-2")
+		resolved.Status.Phase = v1alpha1.ReportPhaseResolved
+		return fake.NewClientBuilder().WithScheme(scheme).
+			WithStatusSubresource(&v1alpha1.NotificationChannel{}).
+			WithObjects(
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "slack", Namespace: "gokubedog-system"},
+					Data:       map[string][]byte{"url": []byte(srv.URL)},
+				},
+				&v1alpha1.NotificationChannel{
+					ObjectMeta: metav1.ObjectMeta{Name: "ops", CreationTimestamp: metav1.Time{Time: created}},
+					Spec: v1alpha1.NotificationChannelSpec{
+						Type: v1alpha1.ChannelTypeSlack,
+						Slack: &v1alpha1.SlackChannelSpec{WebhookURLSecretRef: &v1alpha1.SecretKeySelector{
+							Name: "slack", Namespace: "gokubedog-system", Key: "url",
+						}},
+						Digest: &v1alpha1.DigestSpec{Schedule: "0 9 * * *"},
+					},
+				},
+				open, resolved,
+			).Build()
+	}
+
+	run := func(cl client.Client, at time.Time) {
+		d := &Digester{Client: cl, now: func() time.Time { return at }}
+		Expect(d.Run(ctx)).To(Succeed())
+	}
+
+	It("sends the digest of open violations once it is due", func() {
+		cl := newClient()
+
+		run(cl, created.Add(8*time.Hour))
+		Expect(bodies).To(BeEmpty())
+
+		sent := created.Add(9*time.Hour + 30*time.Second)
+		run(cl, sent)
+		Expect(bodies).To(HaveLen(1))
+		Expect(bodies[0]).To(ContainSubstring("1 violation(s)"))
+
+		var nc v1alpha1.NotificationChannel
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "ops"}, &nc)).To(Succeed())
+		Expect(nc.Status.LastDigestAt.Time).To(BeTemporally("==", sent))
+
+		By("waiting for the next day")
+		run(cl, created.Add(20*time.Hour))
+		Expect(bodies).To(HaveLen(1))
+		run(cl, created.Add(33*time.Hour))
+		Expect(bodies).To(HaveLen(2))
+	})
+})
@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// Digester sends the scheduled compliance digests of NotificationChannels.
+type Digester struct {
+	client.Client
+	// APIReader reads the webhook Secrets of the channels. Falls back to
+	// Client when nil.
+	APIReader client.Reader
+	// Interval is the time between two checks of the digest schedules.
+	Interval time.Duration
+	// Channels keeps the channels built from the NotificationChannels. A new
+	// one is created when nil.
+	Channels *ChannelCache
+
+	now func() time.Time
+}
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=notificationchannels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports,verbs=get;list;watch
+
+// Start checks the digest schedules until the context is cancelled. It
+// implements manager.Runnable.
+func (d *Digester) Start(ctx context.Context) error {
+	l := logf.FromContext(ctx).WithName("digest")
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.Run(ctx); err != nil {
+			l.Error(err, "compliance digest failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only the leading manager sends digests.
+func (d *Digester) NeedLeaderElection() bool {
+	return true
+}
+
+// Run sends the digests that are due. A digest that fails is retried at the
+// next check.
+func (d *Digester) Run(ctx context.Context) error {
+	var secrets client.Reader = d.Client
+	if d.APIReader != nil {
+		secrets = d.APIReader
+	}
+	if d.Channels == nil {
+		d.Channels = NewChannelCache()
+	}
+	targets, err := d.Channels.Load(ctx, d.Client, secrets)
+	if err != nil {
+		return err
+	}
+
+	now := d.clock()
+	var open []*v1alpha1.PolicyViolationReport
+	listed := false
+	var errs []error
+	for _, t := range targets {
+		nc := t.Object
+		if nc == nil || nc.Spec.Digest == nil {
+			continue
+		}
+		sched, err := cron.ParseStandard(nc.Spec.Digest.Schedule)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: invalid digest schedule: %w", nc.Name, err))
+			continue
+		}
+		last := nc.CreationTimestamp.Time
+		if nc.Status.LastDigestAt != nil {
+			last = nc.Status.LastDigestAt.Time
+		}
+		due := sched.Next(last.UTC())
+		if now.Before(due) {
+			continue
+		}
+
+		if !listed {
+			if open, err = d.openReports(ctx); err != nil {
+				return err
+			}
+			listed = true
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, t.Policy.Timeout)
+		var routed []*v1alpha1.PolicyViolationReport
+		for _, rep := range open {
+			if t.Routes(rep) {
+				routed = append(routed, rep)
+			}
+		}
+		_, err = t.Channel.SendDigest(sendCtx, NewDigest("Compliance digest", routed),
+			fmt.Sprintf("digest-%d/%s", due.Unix(), nc.Name))
+		cancel()
+		observe(nc.Name, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", nc.Name, err))
+			continue
+		}
+
+		base := nc.DeepCopy()
+		nc.Status.LastDigestAt = &metav1.Time{Time: now}
+		if err := d.Status().Patch(ctx, nc, client.MergeFrom(base)); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: failed recording digest: %w", nc.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *Digester) openReports(ctx context.Context) ([]*v1alpha1.PolicyViolationReport, error) {
+	var list v1alpha1.PolicyViolationReportList
+	if err := d.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed listing reports: %w", err)
+	}
+	var open []*v1alpha1.PolicyViolationReport
+	for i := range list.Items {
+		if !list.Items[i].Status.IsResolved() {
+			open = append(open, &list.Items[i])
+		}
+	}
+	return open, nil
+}
+
+func (d *Digester) clock() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
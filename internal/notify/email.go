@@ -0,0 +1,559 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// DefaultEmailTemplate is the plain-text template of email channels that do
+// not set one.
+const DefaultEmailTemplate = "Policy violation detected{{ with .Cluster }} in {{ . }}{{ end }}\n\n" +
+	"Resource: {{ .Resource.Namespace }}/{{ .Resource.Name }} ({{ .Resource.Kind }})\n" +
+	"Policy:   {{ .Report.Spec.ProfileName }}\n" +
+	"Severity: {{ .Severity }}\n\n" +
+	"Drift:\n{{ range .Drift }}  {{ .Key }}: {{ .Value }}\n{{ end }}"
+
+// DefaultEmailHTMLTemplate is the HTML template of email channels that do not
+// set one.
+const DefaultEmailHTMLTemplate = `<html><body>
+<h2>Policy violation detected{{ with .Cluster }} in {{ . }}{{ end }}</h2>
+<table>
+<tr><th align="left">Resource</th><td>{{ .Resource.Namespace }}/{{ .Resource.Name }} ({{ .Resource.Kind }})</td></tr>
+<tr><th align="left">Policy</th><td>{{ .Report.Spec.ProfileName }}</td></tr>
+<tr><th align="left">Severity</th><td>{{ .Severity }}</td></tr>
+</table>
+<h3>Drift</h3>
+<table>
+{{ range .Drift }}<tr><td><code>{{ .Key }}</code></td><td>{{ .Value }}</td></tr>
+{{ end }}</table>
+</body></html>
+`
+
+// DefaultEmailSubject is the subject template of email channels that do not
+// set one.
+const DefaultEmailSubject = "[gokubedog] {{ .Severity }}: {{ .Resource.Kind }} " +
+	"{{ .Resource.Namespace }}/{{ .Resource.Name }} violates {{ .Report.Spec.ProfileName }}"
+
+var (
+	emailDigestText = template.Must(template.New("digest").Parse(`{{ .Title }}
+
+{{ .Total }} violation(s)
+{{ range .Groups }}
+  {{ .Profile }} in {{ .Namespace }}: {{ .Count }}{{ end }}
+{{ with .TopOffenders }}
+Top offenders:
+{{ range . }}
+  {{ .Resource.Namespace }}/{{ .Resource.Name }} ({{ .Resource.Kind }}): {{ .Count }}{{ end }}
+{{ end }}`))
+
+	emailDigestHTML = htmltemplate.Must(htmltemplate.New("digest").Parse(`<html><body>
+<h2>{{ .Title }}</h2>
+<p>{{ .Total }} violation(s)</p>
+<table>
+<tr><th align="left">Profile</th><th align="left">Namespace</th><th align="right">Violations</th></tr>
+{{ range .Groups }}<tr><td>{{ .Profile }}</td><td>{{ .Namespace }}</td><td align="right">{{ .Count }}</td></tr>
+{{ end }}</table>
+{{ with .TopOffenders }}<h3>Top offenders</h3>
+<table>
+{{ range . }}<tr><td>{{ .Resource.Namespace }}/{{ .Resource.Name }} ({{ .Resource.Kind }})</td><td align="right">{{ .Count }}</td></tr>
+{{ end }}</table>
+{{ end }}</body></html>
+`))
+)
+
+// SMTPServer is the server an email channel sends through.
+type SMTPServer struct {
+	Host string
+	Port int32
+	// ImplicitTLS connects over TLS instead of upgrading the connection with
+	// STARTTLS, which is then required.
+	ImplicitTLS bool
+	// Username and Password authenticate with PLAIN authentication when set.
+	Username string
+	Password string
+	// TLSConfig secures the connection, verifying Host against the system
+	// roots if nil.
+	TLSConfig *tls.Config
+}
+
+// EmailOptions configures the emails of an email channel.
+type EmailOptions struct {
+	// Text renders the plain-text body of violation emails,
+	// DefaultEmailTemplate if nil.
+	Text *Template
+	// HTML renders the HTML body of violation emails, DefaultEmailHTMLTemplate
+	// if nil.
+	HTML *htmltemplate.Template
+	// Subject renders the subject of violation emails, DefaultEmailSubject if
+	// nil.
+	Subject *template.Template
+	// RecipientAnnotation names the Namespace annotation listing more
+	// recipients of the violations in the namespace. Namespaces are read
+	// from Namespaces; either being empty disables the lookup.
+	RecipientAnnotation string
+	Namespaces          client.Reader
+}
+
+// Email sends violations, digests and notices as emails through an SMTP
+// server. Violation emails go to the static recipients and to the owners
+// listed on the Namespace of the violated resource, and so do digests, split
+// by the recipients of the namespaces they cover. Notices only go to the
+// static recipients.
+type Email struct {
+	name   string
+	from   *mail.Address
+	to     []*mail.Address
+	server SMTPServer
+	opts   EmailOptions
+}
+
+// NewEmail returns a channel sending emails from from to the recipients to.
+func NewEmail(name string, from *mail.Address, to []*mail.Address, server SMTPServer, opts EmailOptions) *Email {
+	if opts.Text == nil {
+		opts.Text = builtinTemplate(DefaultEmailTemplate)
+	}
+	if opts.HTML == nil {
+		opts.HTML = htmltemplate.Must(ParseHTMLTemplate("default", DefaultEmailHTMLTemplate))
+	}
+	if opts.Subject == nil {
+		opts.Subject = template.Must(ParseTemplate("subject", DefaultEmailSubject))
+	}
+	return &Email{name: name, from: from, to: to, server: server, opts: opts}
+}
+
+// Name implements Channel.
+func (e *Email) Name() string { return e.name }
+
+// Send implements Channel. SMTP cannot deduplicate, but the Message-ID is
+// derived from the idempotency key so that mail clients can.
+func (e *Email) Send(ctx context.Context, m *Message, idempotencyKey string) (string, error) {
+	text, err := e.opts.Text.Render(m)
+	if err != nil {
+		return "", err
+	}
+	var html, subject bytes.Buffer
+	if err := e.opts.HTML.Execute(&html, m); err != nil {
+		return "", fmt.Errorf("%w: rendering HTML message: %v", ErrPermanent, err)
+	}
+	if err := e.opts.Subject.Execute(&subject, m); err != nil {
+		return "", fmt.Errorf("%w: rendering subject: %v", ErrPermanent, err)
+	}
+	to, err := e.recipients(ctx, m.Resource.Namespace)
+	if err != nil {
+		return "", err
+	}
+	return e.send(ctx, email{
+		to:        to,
+		subject:   subject.String(),
+		text:      text,
+		html:      html.String(),
+		messageID: e.messageID(idempotencyKey),
+	})
+}
+
+// SendResolved implements Resolver, replying to the email that notified the
+// violation.
+func (e *Email) SendResolved(ctx context.Context, m *Message, opened *v1alpha1.DeliveryStatus, idempotencyKey string) (string, error) {
+	var subject bytes.Buffer
+	if err := e.opts.Subject.Execute(&subject, m); err != nil {
+		return "", fmt.Errorf("%w: rendering subject: %v", ErrPermanent, err)
+	}
+	to, err := e.recipients(ctx, m.Resource.Namespace)
+	if err != nil {
+		return "", err
+	}
+	var text strings.Builder
+	text.WriteString("Policy violation resolved")
+	if m.Cluster != "" {
+		fmt.Fprintf(&text, " in %s", m.Cluster)
+	}
+	fmt.Fprintf(&text, "\n\nResource: %s/%s (%s)\nPolicy:   %s\n",
+		m.Resource.Namespace, m.Resource.Name, m.Resource.Kind, m.Report.Spec.ProfileName)
+	msg := email{
+		to:        to,
+		subject:   "Resolved: " + subject.String(),
+		text:      text.String(),
+		messageID: e.messageID(idempotencyKey + "/resolved"),
+	}
+	if opened != nil {
+		msg.inReplyTo = opened.MessageID
+	}
+	return e.send(ctx, msg)
+}
+
+// SendDigest implements Channel. Every set of recipients gets one email
+// about the violations in the namespaces it receives, and the Message-ID of
+// the first one is returned. A digest of no violation is only sent to the
+// static recipients, if any.
+func (e *Email) SendDigest(ctx context.Context, d *Digest, idempotencyKey string) (string, error) {
+	if len(d.Groups) == 0 && len(e.to) == 0 {
+		return "", nil
+	}
+	parts, err := e.splitDigest(ctx, d)
+	if err != nil {
+		return "", err
+	}
+	var first string
+	for i, p := range parts {
+		key := idempotencyKey
+		if len(parts) > 1 {
+			key += "/" + addressKey(p.to)
+		}
+		id, err := e.sendDigest(ctx, p.digest, p.to, key)
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			first = id
+		}
+	}
+	return first, nil
+}
+
+// digestPart is the part of a digest going to a set of recipients.
+type digestPart struct {
+	to     []*mail.Address
+	digest *Digest
+}
+
+// splitDigest splits d by the recipients of the namespaces of its groups.
+// Namespaces without any recipient are left out, it fails if all are.
+func (e *Email) splitDigest(ctx context.Context, d *Digest) ([]digestPart, error) {
+	if len(d.Groups) == 0 {
+		return []digestPart{{to: e.to, digest: d}}, nil
+	}
+	var parts []digestPart
+	byKey := map[string]int{}
+	partOf := map[string]int{}
+	for _, g := range d.Groups {
+		i, ok := partOf[g.Namespace]
+		if !ok {
+			to, err := e.addresses(ctx, g.Namespace)
+			if err != nil {
+				return nil, err
+			}
+			if len(to) == 0 {
+				partOf[g.Namespace] = -1
+				continue
+			}
+			key := addressKey(to)
+			if i, ok = byKey[key]; !ok {
+				i = len(parts)
+				byKey[key] = i
+				parts = append(parts, digestPart{to: to, digest: &Digest{Title: d.Title}})
+			}
+			partOf[g.Namespace] = i
+		}
+		if i < 0 {
+			continue
+		}
+		p := parts[i].digest
+		p.Groups = append(p.Groups, g)
+		p.Total += g.Count
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("%w: no recipients", ErrPermanent)
+	}
+	if len(parts) == 1 && len(parts[0].digest.Groups) == len(d.Groups) {
+		parts[0].digest = d
+		return parts, nil
+	}
+	for _, o := range d.TopOffenders {
+		if i, ok := partOf[o.Resource.Namespace]; ok && i >= 0 {
+			parts[i].digest.TopOffenders = append(parts[i].digest.TopOffenders, o)
+		}
+	}
+	return parts, nil
+}
+
+// addressKey identifies a set of recipients.
+func addressKey(to []*mail.Address) string {
+	keys := make([]string, len(to))
+	for i, a := range to {
+		keys[i] = strings.ToLower(a.Address)
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, ",")
+}
+
+func (e *Email) sendDigest(ctx context.Context, d *Digest, to []*mail.Address, idempotencyKey string) (string, error) {
+	var text, html bytes.Buffer
+	if err := emailDigestText.Execute(&text, d); err != nil {
+		return "", fmt.Errorf("%w: rendering digest: %v", ErrPermanent, err)
+	}
+	if err := emailDigestHTML.Execute(&html, d); err != nil {
+		return "", fmt.Errorf("%w: rendering digest: %v", ErrPermanent, err)
+	}
+	return e.send(ctx, email{
+		to:        to,
+		subject:   "[gokubedog] " + d.Title,
+		text:      text.String(),
+		html:      html.String(),
+		messageID: e.messageID(idempotencyKey),
+	})
+}
+
+// SendNotice implements Channel.
+func (e *Email) SendNotice(ctx context.Context, n *Notice, idempotencyKey string) (string, error) {
+	return e.send(ctx, email{
+		to:        e.to,
+		subject:   "[gokubedog] " + n.Title,
+		text:      n.Text + "\n",
+		messageID: e.messageID(idempotencyKey),
+	})
+}
+
+// recipients returns the static recipients followed by those listed on the
+// Namespace ns, without duplicates. A missing Namespace adds no recipient.
+// It fails permanently if there is no recipient.
+func (e *Email) recipients(ctx context.Context, ns string) ([]*mail.Address, error) {
+	to, err := e.addresses(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	if len(to) == 0 {
+		return nil, fmt.Errorf("%w: no recipients", ErrPermanent)
+	}
+	return to, nil
+}
+
+// addresses is recipients, without failing if there is no recipient.
+func (e *Email) addresses(ctx context.Context, ns string) ([]*mail.Address, error) {
+	to := e.to
+	if e.opts.RecipientAnnotation != "" && e.opts.Namespaces != nil && ns != "" {
+		var namespace corev1.Namespace
+		err := e.opts.Namespaces.Get(ctx, types.NamespacedName{Name: ns}, &namespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed fetching Namespace %s: %w", ns, err)
+		}
+		if v := strings.TrimSpace(namespace.Annotations[e.opts.RecipientAnnotation]); v != "" {
+			owners, err := mail.ParseAddressList(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid %s annotation on Namespace %s: %v",
+					ErrPermanent, e.opts.RecipientAnnotation, ns, err)
+			}
+			to = append(to[:len(to):len(to)], owners...)
+		}
+	}
+
+	seen := map[string]bool{}
+	unique := make([]*mail.Address, 0, len(to))
+	for _, a := range to {
+		if k := strings.ToLower(a.Address); !seen[k] {
+			seen[k] = true
+			unique = append(unique, a)
+		}
+	}
+	return unique, nil
+}
+
+// messageID returns the Message-ID of the email sent with key.
+func (e *Email) messageID(key string) string {
+	domain := "gokubedog"
+	if i := strings.LastIndex(e.from.Address, "@"); i >= 0 {
+		domain = e.from.Address[i+1:]
+	}
+	sum := sha256.Sum256([]byte(key))
+	return fmt.Sprintf("<%x@%s>", sum[:16], domain)
+}
+
+type email struct {
+	to        []*mail.Address
+	subject   string
+	text      string
+	html      string
+	messageID string
+	inReplyTo string
+}
+
+// send sends msg and returns its Message-ID.
+func (e *Email) send(ctx context.Context, msg email) (string, error) {
+	if len(msg.to) == 0 {
+		return "", fmt.Errorf("%w: no recipients", ErrPermanent)
+	}
+	body, err := e.compose(msg)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	if err := e.deliver(ctx, msg.to, body); err != nil {
+		return "", smtpError(err)
+	}
+	return msg.messageID, nil
+}
+
+// compose returns msg as a MIME message, multipart/alternative if it has an
+// HTML body.
+func (e *Email) compose(msg email) ([]byte, error) {
+	var b bytes.Buffer
+	to := make([]string, len(msg.to))
+	for i, a := range msg.to {
+		to[i] = a.String()
+	}
+	// templates may render line breaks, which must not end the header
+	subject := strings.Join(strings.Fields(msg.subject), " ")
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: %s\r\n", msg.messageID)
+	if msg.inReplyTo != "" {
+		fmt.Fprintf(&b, "In-Reply-To: %s\r\nReferences: %s\r\n", msg.inReplyTo, msg.inReplyTo)
+	}
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.html == "" {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&b, msg.text); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+	mw := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.text},
+		{"text/html; charset=utf-8", msg.html},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// deliver sends body to the recipients to in one SMTP transaction.
+func (e *Email) deliver(ctx context.Context, to []*mail.Address, body []byte) error {
+	s := e.server
+	cfg := s.TLSConfig
+	if cfg == nil {
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	cfg = cfg.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = s.Host
+	}
+
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(int(s.Port)))
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	var err error
+	if s.ImplicitTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: cfg}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	// net/smtp has no contexts, bound the whole exchange instead
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if !s.ImplicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%w: %s does not support STARTTLS", ErrPermanent, addr)
+		}
+		if err := c.StartTLS(cfg); err != nil {
+			return err
+		}
+	}
+	if s.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(e.from.Address); err != nil {
+		return err
+	}
+	for _, a := range to {
+		if err := c.Rcpt(a.Address); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// smtpError marks the permanent SMTP replies, 5xx, as permanent failures.
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 && !errors.Is(err, ErrPermanent) {
+		return fmt.Errorf("%w: smtp error: %v", ErrPermanent, err)
+	}
+	return err
+}
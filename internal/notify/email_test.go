@@ -0,0 +1,368 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Email", func() {
+	var (
+		srv    *smtpStandIn
+		server SMTPServer
+		from   = &mail.Address{Name: "gokubedog", Address: "dog@example.com"}
+		ops    = []*mail.Address{{Address: "ops@example.com"}}
+		cl     *fake.ClientBuilder
+	)
+
+	BeforeEach(func() {
+		certSrv := httptest.NewUnstartedServer(http.NotFoundHandler())
+		certSrv.StartTLS()
+		DeferCleanup(certSrv.Close)
+		roots := x509.NewCertPool()
+		roots.AddCert(certSrv.Certificate())
+
+		srv = startSMTP(&tls.Config{Certificates: certSrv.TLS.Certificates})
+		host, port := srv.hostPort()
+		server = SMTPServer{
+			Host:      host,
+			Port:      port,
+			Username:  "dog",
+			Password:  "s3cret",
+			TLSConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		}
+
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a", Annotations: map[string]string{
+				v1alpha1.DefaultRecipientAnnotation: "Team A <team-a@example.com>, OPS@example.com",
+			}},
+		})
+	})
+
+	It("emails violations to the namespace owners over STARTTLS", func() {
+		e := NewEmail("mail", from, ops, server, EmailOptions{
+			RecipientAnnotation: v1alpha1.DefaultRecipientAnnotation,
+			Namespaces:          cl.Build(),
+		})
+		id, err := e.Send(context.Background(), SampleMessage("prod-eu"), "uid/mail")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(HaveSuffix("@example.com>"))
+
+		Expect(srv.mails).To(HaveLen(1))
+		got := srv.mails[0]
+		Expect(got.auth).To(HavePrefix("PLAIN "))
+		Expect(got.from).To(Equal("<dog@example.com>"))
+		Expect(got.to).To(Equal([]string{"<ops@example.com>", "<team-a@example.com>"}))
+
+		msg, err := mail.ReadMessage(strings.NewReader(got.data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg.Header.Get("Message-ID")).To(Equal(id))
+		Expect(msg.Header.Get("Subject")).To(Equal(
+			"[gokubedog] high: NetworkPolicy team-a/allow-web violates require-owner"))
+		parts := alternatives(msg)
+		Expect(parts).To(HaveKeyWithValue("text/plain", ContainSubstring("Resource: team-a/allow-web (NetworkPolicy)")))
+		Expect(parts).To(HaveKeyWithValue("text/plain", ContainSubstring("  tier: expected backend, got frontend")))
+		Expect(parts).To(HaveKeyWithValue("text/html", ContainSubstring("<td><code>owner</code></td><td>missing</td>")))
+	})
+
+	It("renders custom templates and escapes HTML", func() {
+		html, err := ParseHTMLTemplate("html", "<p>{{ .Resource.Name }}: {{ index .Report.Spec.Drift \"tier\" }}</p>")
+		Expect(err).NotTo(HaveOccurred())
+		subject, err := ParseTemplate("subject", "{{ .Report.Spec.ProfileName }}\non {{ .Cluster }}")
+		Expect(err).NotTo(HaveOccurred())
+		e := NewEmail("mail", from, ops, server, EmailOptions{HTML: html, Subject: subject})
+
+		m := SampleMessage("prod")
+		m.Report.Spec.Drift["tier"] = "<script>"
+		_, err = e.Send(context.Background(), m, "uid/mail")
+		Expect(err).NotTo(HaveOccurred())
+
+		msg, err := mail.ReadMessage(strings.NewReader(srv.mails[0].data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg.Header.Get("Subject")).To(Equal("require-owner on prod"))
+		Expect(alternatives(msg)).To(HaveKeyWithValue("text/html", "<p>allow-web: &lt;script&gt;</p>"))
+	})
+
+	It("sends digests to the owners of their namespaces and threads resolutions", func() {
+		e := NewEmail("mail", from, ops, server, EmailOptions{
+			RecipientAnnotation: v1alpha1.DefaultRecipientAnnotation,
+			Namespaces:          cl.Build(),
+		})
+		rep := SampleMessage("").Report
+		other := rep.DeepCopy()
+		other.Spec.ViolatedResource.Namespace = "team-b"
+		reps := []*v1alpha1.PolicyViolationReport{rep, other}
+		_, err := e.SendDigest(context.Background(), NewDigest("Daily digest", reps), "batch/mail")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.mails).To(HaveLen(2))
+		Expect(srv.mails[0].to).To(Equal([]string{"<ops@example.com>", "<team-a@example.com>"}))
+		msg, err := mail.ReadMessage(strings.NewReader(srv.mails[0].data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg.Header.Get("Subject")).To(Equal("[gokubedog] Daily digest"))
+		parts := alternatives(msg)
+		Expect(parts).To(HaveKeyWithValue("text/plain", ContainSubstring("require-owner in team-a: 1")))
+		Expect(parts).NotTo(HaveKeyWithValue("text/plain", ContainSubstring("team-b")))
+		Expect(parts).To(HaveKeyWithValue("text/html", ContainSubstring("<td>require-owner</td><td>team-a</td>")))
+		Expect(srv.mails[1].to).To(Equal([]string{"<ops@example.com>"}))
+		Expect(srv.mails[1].data).To(ContainSubstring("require-owner in team-b: 1"))
+
+		opened := &v1alpha1.DeliveryStatus{Channel: "mail", MessageID: "<opened@example.com>"}
+		_, err = e.SendResolved(context.Background(), SampleMessage(""), opened, "uid/mail")
+		Expect(err).NotTo(HaveOccurred())
+		msg, err = mail.ReadMessage(strings.NewReader(srv.mails[2].data))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(msg.Header.Get("Subject")).To(HavePrefix("Resolved: "))
+		Expect(msg.Header.Get("In-Reply-To")).To(Equal("<opened@example.com>"))
+		Expect(srv.mails[2].to).To(ContainElement("<team-a@example.com>"))
+	})
+
+	It("sends digests without static recipients to the namespace owners only", func() {
+		e := NewEmail("mail", from, nil, server, EmailOptions{
+			RecipientAnnotation: v1alpha1.DefaultRecipientAnnotation,
+			Namespaces:          cl.Build(),
+		})
+		_, err := e.SendDigest(context.Background(), NewDigest("Daily digest", nil), "digest/mail")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.mails).To(BeEmpty())
+
+		rep := SampleMessage("").Report
+		other := rep.DeepCopy()
+		other.Spec.ViolatedResource.Namespace = "team-b"
+		_, err = e.SendDigest(context.Background(), NewDigest("Daily digest", []*v1alpha1.PolicyViolationReport{other}), "batch/mail")
+		Expect(err).To(MatchError(ErrPermanent))
+
+		_, err = e.SendDigest(context.Background(), NewDigest("Daily digest", []*v1alpha1.PolicyViolationReport{rep, other}), "batch/mail")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.mails).To(HaveLen(1))
+		Expect(srv.mails[0].to).To(Equal([]string{"<team-a@example.com>", "<OPS@example.com>"}))
+		Expect(srv.mails[0].data).NotTo(ContainSubstring("team-b"))
+	})
+
+	It("fails permanently on rejected recipients and servers without STARTTLS", func() {
+		srv.reject = "ops@example.com"
+		e := NewEmail("mail", from, ops, server, EmailOptions{})
+		_, err := e.SendNotice(context.Background(), &Notice{Title: "t", Text: "x"}, "k")
+		Expect(err).To(MatchError(ErrPermanent))
+
+		plain := startSMTP(nil)
+		server.Host, server.Port = plain.hostPort()
+		e = NewEmail("mail", from, ops, server, EmailOptions{})
+		_, err = e.SendNotice(context.Background(), &Notice{Title: "t", Text: "x"}, "k")
+		Expect(err).To(MatchError(ErrPermanent))
+		Expect(err.Error()).To(ContainSubstring("STARTTLS"))
+	})
+
+	It("is built from NotificationChannels", func() {
+		ref := &v1alpha1.SecretReference{Name: "smtp", Namespace: "gokubedog-system"}
+		c := cl.WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace},
+				Data:       map[string][]byte{"username": []byte("dog"), "password": []byte("s3cret")},
+			},
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "mail"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type: v1alpha1.ChannelTypeEmail,
+					Email: &v1alpha1.EmailChannelSpec{
+						SMTP: v1alpha1.SMTPSpec{Host: "smtp.example.com", AuthSecretRef: ref},
+						From: "gokubedog <dog@example.com>",
+						To:   []string{"ops@example.com"},
+					},
+				},
+			},
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "bad-html"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type: v1alpha1.ChannelTypeEmail,
+					Email: &v1alpha1.EmailChannelSpec{
+						SMTP:                v1alpha1.SMTPSpec{Host: "smtp.example.com"},
+						From:                "dog@example.com",
+						RecipientAnnotation: v1alpha1.DefaultRecipientAnnotation,
+						HTMLTemplate:        &v1alpha1.TemplateSource{Inline: "{{ .Nope }}"},
+					},
+				},
+			},
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "nobody"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type: v1alpha1.ChannelTypeEmail,
+					Email: &v1alpha1.EmailChannelSpec{
+						SMTP: v1alpha1.SMTPSpec{Host: "smtp.example.com"},
+						From: "dog@example.com",
+					},
+				},
+			},
+		).Build()
+
+		targets, err := Load(context.Background(), c, c)
+		Expect(err).NotTo(HaveOccurred())
+		byName := map[string]Channel{}
+		for _, t := range targets {
+			byName[t.Channel.Name()] = t.Channel
+		}
+		Expect(byName["mail"]).To(BeAssignableToTypeOf(&Email{}))
+		Expect(byName["mail"].(*Email).server.Port).To(Equal(int32(587)))
+		Expect(byName["mail"].(*Email).server.Password).To(Equal("s3cret"))
+		Expect(byName["bad-html"]).To(BeAssignableToTypeOf(broken{}))
+		Expect(byName["nobody"]).To(BeAssignableToTypeOf(broken{}))
+	})
+})
+
+// alternatives returns the decoded parts of a multipart/alternative message
+// by content type.
+func alternatives(msg *mail.Message) map[string]string {
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	Expect(err).NotTo(HaveOccurred())
+	parts := map[string]string{}
+	r := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		Expect(err).NotTo(HaveOccurred())
+		mediaType, _, err := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		Expect(err).NotTo(HaveOccurred())
+		body, err := io.ReadAll(p)
+		Expect(err).NotTo(HaveOccurred())
+		parts[mediaType] = string(body)
+	}
+}
+
+// smtpStandIn is a minimal SMTP server offering STARTTLS when it has a TLS
+// configuration.
+type smtpStandIn struct {
+	ln     net.Listener
+	tls    *tls.Config
+	reject string
+
+	mu    sync.Mutex
+	mails []receivedMail
+}
+
+type receivedMail struct {
+	auth, from, data string
+	to               []string
+}
+
+func startSMTP(cfg *tls.Config) *smtpStandIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	s := &smtpStandIn{ln: ln, tls: cfg}
+	DeferCleanup(ln.Close)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) hostPort() (string, int32) {
+	addr := s.ln.Addr().(*net.TCPAddr)
+	return addr.IP.String(), int32(addr.Port)
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 stand-in ESMTP")
+	secure := false
+	var m receivedMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			switch {
+			case secure:
+				_ = tp.PrintfLine("250-stand-in\r\n250 AUTH PLAIN")
+			case s.tls != nil:
+				_ = tp.PrintfLine("250-stand-in\r\n250 STARTTLS")
+			default:
+				_ = tp.PrintfLine("250 stand-in")
+			}
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 ready")
+			tc := tls.Server(conn, s.tls)
+			if tc.Handshake() != nil {
+				return
+			}
+			conn, tp, secure = tc, textproto.NewConn(tc), true
+		case "AUTH":
+			m.auth = arg
+			_ = tp.PrintfLine("235 accepted")
+		case "MAIL":
+			m.from = strings.TrimPrefix(arg, "FROM:")
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			if s.reject != "" && strings.Contains(arg, s.reject) {
+				_ = tp.PrintfLine("550 no such user")
+				continue
+			}
+			m.to = append(m.to, strings.TrimPrefix(arg, "TO:"))
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			m.data = string(data)
+			s.mu.Lock()
+			s.mails = append(s.mails, m)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
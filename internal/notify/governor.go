@@ -0,0 +1,217 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// SuppressionQuietPeriod ends a suppression episode. A notice is sent when a
+// suppression starts and again only once nothing was suppressed for that
+// reason for this long.
+const SuppressionQuietPeriod = 10 * time.Minute
+
+// Reasons a Governor suppresses a notification for.
+const (
+	ReasonRateLimited = "RateLimited"
+	ReasonFlapping    = "Flapping"
+)
+
+// pruneEvery is the number of decisions between two prunes of the state kept
+// for resources and episodes that went quiet.
+const pruneEvery = 1024
+
+// Verdict is the decision of a Governor on a notification.
+type Verdict struct {
+	// Reason is empty if the notification may be sent.
+	Reason string
+	// Message details the suppression.
+	Message string
+	// Notice, when set, announces the start of the suppression and should be
+	// sent to the channel.
+	Notice *Notice
+
+	reserved []*rate.Reservation
+}
+
+// Suppressed reports whether the notification must be dropped.
+func (v Verdict) Suppressed() bool { return v.Reason != "" }
+
+// Refund gives back the rate limit tokens an admitted notification took, when
+// it ends up not being sent.
+func (v Verdict) Refund(now time.Time) {
+	for _, r := range v.reserved {
+		r.CancelAt(now)
+	}
+}
+
+// Governor enforces the rate limits and flap suppression of the channels. It
+// keeps its state in memory, so limits start afresh when the manager restarts.
+type Governor struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+	opens    map[string]*openings
+	episodes map[string]time.Time
+	calls    int
+}
+
+type openings struct {
+	times  []time.Time
+	window time.Duration
+}
+
+// NewGovernor returns a Governor without any recorded history.
+func NewGovernor() *Governor {
+	return &Governor{
+		limiters: map[string]*rate.Limiter{},
+		opens:    map[string]*openings{},
+		episodes: map[string]time.Time{},
+	}
+}
+
+// Admit decides whether the violation reported by rep, when first notified
+// to t after it opened, may be sent. Batching channels are only subject to flap suppression, as
+// their digests already bound the number of messages.
+func (g *Governor) Admit(t Target, rep *v1alpha1.PolicyViolationReport, now time.Time) Verdict {
+	if t.Object == nil {
+		return Verdict{}
+	}
+	spec := &t.Object.Spec
+	channel := t.Channel.Name()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.calls++; g.calls%pruneEvery == 0 {
+		g.prune(now)
+	}
+
+	if f := spec.FlapSuppression; f != nil && f.Window.Duration > 0 {
+		threshold := int(f.Threshold)
+		if threshold <= 0 {
+			threshold = 3
+		}
+		key := fmt.Sprintf("flap/%s/%s/%s", channel, rep.Namespace, rep.Name)
+		o := g.opens[key]
+		if o == nil {
+			o = &openings{}
+			g.opens[key] = o
+		}
+		o.window = f.Window.Duration
+		// Openings are identified by their time, so deciding twice on the
+		// same one, e.g. after a conflict, does not count it twice.
+		if opened := rep.Opened(); !slices.ContainsFunc(o.times, opened.Equal) {
+			o.times = append(o.times, opened)
+			slices.SortFunc(o.times, time.Time.Compare)
+		}
+		o.times = recent(o.times, now.Add(-o.window))
+		if len(o.times) > threshold {
+			res := rep.Spec.ViolatedResource
+			return g.suppress(channel, key, ReasonFlapping, fmt.Sprintf(
+				"The violation of profile %s by %s %s/%s opened %d times within %s; suppressing it on %s.",
+				rep.Spec.ProfileName, res.Kind, res.Namespace, res.Name, len(o.times), o.window, channel), now)
+		}
+	}
+
+	var admitted Verdict
+	if rl := spec.RateLimit; rl != nil && t.Batch == 0 {
+		if b := rl.Profile; b != nil {
+			key := fmt.Sprintf("rate/%s/%s/%s", channel, rep.Spec.ProfileNamespace, rep.Spec.ProfileName)
+			r := g.limiter(key, b).ReserveN(now, 1)
+			admitted.reserved = append(admitted.reserved, r)
+			if !r.OK() || r.DelayFrom(now) > 0 {
+				admitted.Refund(now)
+				return g.suppress(channel, key, ReasonRateLimited, fmt.Sprintf(
+					"Notifications of profile %s to %s exceed %d per minute; suppressing them.",
+					rep.Spec.ProfileName, channel, b.PerMinute), now)
+			}
+		}
+		if b := rl.Channel; b != nil {
+			key := "rate/" + channel
+			r := g.limiter(key, b).ReserveN(now, 1)
+			admitted.reserved = append(admitted.reserved, r)
+			if !r.OK() || r.DelayFrom(now) > 0 {
+				admitted.Refund(now)
+				return g.suppress(channel, key, ReasonRateLimited, fmt.Sprintf(
+					"Notifications to %s exceed %d per minute; suppressing them.", channel, b.PerMinute), now)
+			}
+		}
+	}
+	return admitted
+}
+
+// limiter returns the limiter of key, updated to the current spec.
+func (g *Governor) limiter(key string, b *v1alpha1.TokenBucketSpec) *rate.Limiter {
+	limit := rate.Limit(float64(b.PerMinute) / 60)
+	burst := int(b.Burst)
+	if burst <= 0 {
+		burst = int(b.PerMinute)
+	}
+	lim, ok := g.limiters[key]
+	if !ok {
+		lim = rate.NewLimiter(limit, burst)
+		g.limiters[key] = lim
+		return lim
+	}
+	if lim.Limit() != limit {
+		lim.SetLimit(limit)
+	}
+	if lim.Burst() != burst {
+		lim.SetBurst(burst)
+	}
+	return lim
+}
+
+// suppress records a suppression under key and returns its verdict, with a
+// notice if it starts a new episode.
+func (g *Governor) suppress(channel, key, reason, message string, now time.Time) Verdict {
+	suppressedTotal.WithLabelValues(channel, reason).Inc()
+	v := Verdict{Reason: reason, Message: message}
+	if last, ok := g.episodes[key]; !ok || now.Sub(last) >= SuppressionQuietPeriod {
+		v.Notice = &Notice{Title: "Suppressing notifications", Text: message}
+	}
+	g.episodes[key] = now
+	return v
+}
+
+func (g *Governor) prune(now time.Time) {
+	for key, o := range g.opens {
+		if o.times = recent(o.times, now.Add(-o.window)); len(o.times) == 0 {
+			delete(g.opens, key)
+		}
+	}
+	for key, last := range g.episodes {
+		if now.Sub(last) >= SuppressionQuietPeriod {
+			delete(g.episodes, key)
+		}
+	}
+}
+
+// recent drops the times before since.
+func recent(times []time.Time, since time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(since) {
+		i++
+	}
+	return times[i:]
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Governor", func() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	target := func(spec v1alpha1.NotificationChannelSpec) Target {
+		return Target{
+			Channel: NewSlack("ops", "http://unused", SlackOptions{}),
+			Object:  &v1alpha1.NotificationChannel{ObjectMeta: metav1.ObjectMeta{Name: "ops"}, Spec: spec},
+		}
+	}
+	report := func(profile, name string, opened time.Time) *v1alpha1.PolicyViolationReport {
+		rep := newReport(name, profile, "team-a", name)
+		rep.Spec.ProfileNamespace = "team-a"
+		rep.Status.OpenedAt = &metav1.Time{Time: opened}
+		return rep
+	}
+
+	It("admits everything on channels without limits", func() {
+		g := NewGovernor()
+		t := target(v1alpha1.NotificationChannelSpec{})
+		for i := range 100 {
+			Expect(g.Admit(t, report("p", "r", now), now.Add(time.Duration(i)*time.Second)).Suppressed()).To(BeFalse())
+		}
+		Expect(g.Admit(Target{Channel: NewSlack("default", "", SlackOptions{})}, report("p", "r", now), now).Suppressed()).To(BeFalse())
+	})
+
+	It("rate limits per profile and announces the suppression once", func() {
+		g := NewGovernor()
+		t := target(v1alpha1.NotificationChannelSpec{RateLimit: &v1alpha1.RateLimitSpec{
+			Profile: &v1alpha1.TokenBucketSpec{PerMinute: 1, Burst: 2},
+		}})
+		Expect(g.Admit(t, report("p", "a", now), now).Suppressed()).To(BeFalse())
+		Expect(g.Admit(t, report("p", "b", now), now).Suppressed()).To(BeFalse())
+
+		v := g.Admit(t, report("p", "c", now), now)
+		Expect(v.Reason).To(Equal(ReasonRateLimited))
+		Expect(v.Notice).NotTo(BeNil())
+		Expect(v.Notice.Text).To(ContainSubstring("profile p to ops exceed 1 per minute"))
+
+		v = g.Admit(t, report("p", "d", now), now.Add(time.Second))
+		Expect(v.Suppressed()).To(BeTrue())
+		Expect(v.Notice).To(BeNil())
+
+		By("keeping other profiles apart")
+		Expect(g.Admit(t, report("q", "e", now), now).Suppressed()).To(BeFalse())
+
+		By("refilling the bucket over time")
+		Expect(g.Admit(t, report("p", "f", now), now.Add(time.Minute)).Suppressed()).To(BeFalse())
+
+		By("announcing a new episode after a quiet period")
+		later := now.Add(time.Minute + SuppressionQuietPeriod)
+		Expect(g.Admit(t, report("p", "g", now), later).Suppressed()).To(BeFalse())
+		Expect(g.Admit(t, report("p", "h", now), later).Suppressed()).To(BeFalse())
+		Expect(g.Admit(t, report("p", "i", now), later).Notice).NotTo(BeNil())
+	})
+
+	It("does not consume the profile budget when the channel limit suppresses", func() {
+		g := NewGovernor()
+		t := target(v1alpha1.NotificationChannelSpec{RateLimit: &v1alpha1.RateLimitSpec{
+			Profile: &v1alpha1.TokenBucketSpec{PerMinute: 2},
+			Channel: &v1alpha1.TokenBucketSpec{PerMinute: 1},
+		}})
+		Expect(g.Admit(t, report("p", "a", now), now).Suppressed()).To(BeFalse())
+		Expect(g.Admit(t, report("p", "b", now), now).Reason).To(Equal(ReasonRateLimited))
+
+		t.Object.Spec.RateLimit.Channel = nil
+		Expect(g.Admit(t, report("p", "c", now), now).Suppressed()).To(BeFalse())
+	})
+
+	It("gives back the tokens of refunded notifications", func() {
+		g := NewGovernor()
+		t := target(v1alpha1.NotificationChannelSpec{RateLimit: &v1alpha1.RateLimitSpec{
+			Profile: &v1alpha1.TokenBucketSpec{PerMinute: 1},
+			Channel: &v1alpha1.TokenBucketSpec{PerMinute: 1},
+		}})
+		v := g.Admit(t, report("p", "a", now), now)
+		Expect(v.Suppressed()).To(BeFalse())
+		v.Refund(now)
+		Expect(g.Admit(t, report("p", "a", now), now).Suppressed()).To(BeFalse())
+		Expect(g.Admit(t, report("p", "b", now), now).Suppressed()).To(BeTrue())
+	})
+
+	It("suppresses resources that keep reopening", func() {
+		g := NewGovernor()
+		t := target(v1alpha1.NotificationChannelSpec{FlapSuppression: &v1alpha1.FlapSuppressionSpec{
+			Window: metav1.Duration{Duration: 10 * time.Minute}, Threshold: 2,
+		}})
+		Expect(g.Admit(t, report("p", "r", now), now).Suppressed()).To(BeFalse())
+		Expect(g.Admit(t, report("p", "r", now), now).Suppressed()).To(BeFalse(), "same opening counted once")
+		opened := now.Add(time.Minute)
+		Expect(g.Admit(t, report("p", "r", opened), opened).Suppressed()).To(BeFalse())
+
+		opened = now.Add(2 * time.Minute)
+		v := g.Admit(t, report("p", "r", opened), opened)
+		Expect(v.Reason).To(Equal(ReasonFlapping))
+		Expect(v.Notice.Text).To(ContainSubstring("NetworkPolicy team-a/r opened 3 times within 10m0s"))
+
+		By("letting it through once the window passed")
+		opened = now.Add(20 * time.Minute)
+		Expect(g.Admit(t, report("p", "r", opened), opened).Suppressed()).To(BeFalse())
+	})
+
+	It("does not rate limit batching channels", func() {
+		g := NewGovernor()
+		t := target(v1alpha1.NotificationChannelSpec{RateLimit: &v1alpha1.RateLimitSpec{
+			Channel: &v1alpha1.TokenBucketSpec{PerMinute: 1},
+		}})
+		t.Batch = time.Minute
+		for _, name := range []string{"a", "b", "c"} {
+			Expect(g.Admit(t, report("p", name, now), now).Suppressed()).To(BeFalse())
+		}
+	})
+})
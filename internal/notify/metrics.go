@@ -0,0 +1,43 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	attemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gokubedog_notification_attempts_total",
+		Help: "Number of notification delivery attempts, by channel and result (success, error).",
+	}, []string{"channel", "result"})
+
+	deadLettersTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gokubedog_notification_dead_letters_total",
+		Help: "Number of notification deliveries moved to the dead-letter state, by channel.",
+	}, []string{"channel"})
+
+	suppressedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gokubedog_notification_suppressed_total",
+		Help: "Number of notifications suppressed, by channel and reason (RateLimited, Flapping).",
+	}, []string{"channel", "reason"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(attemptsTotal, deadLettersTotal, suppressedTotal)
+}
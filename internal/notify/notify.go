@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notify delivers PolicyViolationReports to notification channels,
+// retrying failed deliveries with bounded exponential backoff.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+const (
+	// DefaultTimeout bounds a single delivery attempt.
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxAttempts is the number of attempts before a delivery is
+	// dead-lettered.
+	DefaultMaxAttempts = 8
+	// DefaultInitialBackoff is the delay after the first failed attempt.
+	DefaultInitialBackoff = 10 * time.Second
+	// DefaultMaxBackoff caps the delay between attempts.
+	DefaultMaxBackoff = 10 * time.Minute
+)
+
+// ErrPermanent marks a delivery error that retrying cannot fix, such as a
+// rejected payload or a revoked webhook. Wrap it to dead-letter a delivery
+// immediately.
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// RetryAfterError is returned when the destination asks to be retried no
+// sooner than After, e.g. on a rate limit.
+type RetryAfterError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %s)", e.Err, e.After)
+}
+
+func (e *RetryAfterError) Unwrap() error { return e.Err }
+
+// Channel is a notification destination.
+type Channel interface {
+	// Name identifies the channel in the report status and in metrics.
+	Name() string
+	// Send delivers the message once and returns the ID the destination
+	// assigned to it, if any. Destinations that support it must deduplicate
+	// sends carrying the same idempotency key.
+	Send(ctx context.Context, m *Message, idempotencyKey string) (string, error)
+	// SendDigest delivers a digest once, like Send.
+	SendDigest(ctx context.Context, d *Digest, idempotencyKey string) (string, error)
+	// SendNotice delivers a notice once, like Send.
+	SendNotice(ctx context.Context, n *Notice, idempotencyKey string) (string, error)
+}
+
+// Resolver is a channel able to notify that a violation was resolved.
+type Resolver interface {
+	Channel
+	// SendResolved delivers the resolution of the violation of m once, like
+	// Send. Opened is the delivery that notified the violation and
+	// idempotencyKey the key it was sent with.
+	SendResolved(ctx context.Context, m *Message, opened *v1alpha1.DeliveryStatus, idempotencyKey string) (string, error)
+}
+
+// Refreshing is a channel whose notifications expire unless they are resent
+// while their violation is open.
+type Refreshing interface {
+	Channel
+	// RefreshInterval is how often the notifications are resent.
+	RefreshInterval() time.Duration
+	// Refresh resends the notifications of the open violations of ms.
+	Refresh(ctx context.Context, ms []*Message) error
+}
+
+// Notice is an operational message about the notifications themselves, such
+// as the start of a suppression.
+type Notice struct {
+	Title string
+	Text  string
+}
+
+// IdempotencyKey returns the key identifying the notification of rep to a
+// channel. It is the same for every attempt, so a resend after an attempt
+// with an unknown outcome can be deduplicated by the destination.
+func IdempotencyKey(rep *v1alpha1.PolicyViolationReport, channel string) string {
+	return fmt.Sprintf("%s/%s", rep.UID, channel)
+}
+
+// Policy bounds the attempts made to deliver to a channel.
+type Policy struct {
+	Timeout        time.Duration
+	MaxAttempts    int32
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultPolicy returns the policy used by channels that do not configure one.
+func DefaultPolicy() Policy {
+	return Policy{
+		Timeout:        DefaultTimeout,
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+	}
+}
+
+// PolicyFromSpec returns the policy of a NotificationChannel, filling unset
+// fields with the defaults.
+func PolicyFromSpec(spec *v1alpha1.NotificationChannelSpec) Policy {
+	p := DefaultPolicy()
+	if spec.Timeout != nil && spec.Timeout.Duration > 0 {
+		p.Timeout = spec.Timeout.Duration
+	}
+	if r := spec.Retry; r != nil {
+		if r.MaxAttempts > 0 {
+			p.MaxAttempts = r.MaxAttempts
+		}
+		if r.InitialBackoff != nil && r.InitialBackoff.Duration > 0 {
+			p.InitialBackoff = r.InitialBackoff.Duration
+		}
+		if r.MaxBackoff != nil && r.MaxBackoff.Duration > 0 {
+			p.MaxBackoff = r.MaxBackoff.Duration
+		}
+	}
+	return p
+}
+
+// Backoff returns the delay after the given failed attempt, counting from 1.
+func (p Policy) Backoff(attempt int32) time.Duration {
+	d := p.InitialBackoff
+	for i := int32(1); i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxBackoff)
+}
+
+// Claim returns the status recording the start of an attempt to deliver to
+// channel, continuing from prev when the channel was attempted before. The
+// claim must be persisted before the attempt is made, so that a delivery is
+// never attempted twice without the report saying so.
+func Claim(prev *v1alpha1.DeliveryStatus, channel string, now time.Time) v1alpha1.DeliveryStatus {
+	st := v1alpha1.DeliveryStatus{Channel: channel}
+	if prev != nil {
+		st = *prev.DeepCopy()
+	}
+	st.State = v1alpha1.DeliverySending
+	st.Attempts++
+	st.LastAttemptAt = &metav1.Time{Time: now}
+	st.NextAttemptAt = nil
+	return st
+}
+
+// Deliver makes the attempt claimed by st to send m to ch and returns the
+// resulting delivery status.
+func Deliver(ctx context.Context, ch Channel, p Policy, m *Message,
+	st v1alpha1.DeliveryStatus, now time.Time) v1alpha1.DeliveryStatus {
+	attemptCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	id, err := ch.Send(attemptCtx, m, IdempotencyKey(m.Report, st.Channel))
+	cancel()
+	observe(st.Channel, err)
+	return p.result(st, id, err, now)
+}
+
+// DeliverResolution makes the attempt claimed by st to send the resolution of
+// the violation notified by opened, following the same rules as Deliver.
+func DeliverResolution(ctx context.Context, ch Resolver, p Policy, m *Message,
+	opened *v1alpha1.DeliveryStatus, st v1alpha1.DeliveryStatus, now time.Time) v1alpha1.DeliveryStatus {
+	attemptCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	id, err := ch.SendResolved(attemptCtx, m, opened, IdempotencyKey(m.Report, st.Channel))
+	cancel()
+	observe(st.Channel, err)
+	return p.result(st, id, err, now)
+}
+
+// DeliverBatch makes the attempts claimed by claims to send the reports of a
+// batch to ch as one digest, and returns the resulting delivery statuses.
+func DeliverBatch(ctx context.Context, ch Channel, p Policy, d *Digest, key string,
+	claims []v1alpha1.DeliveryStatus, now time.Time) []v1alpha1.DeliveryStatus {
+	attemptCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	id, err := ch.SendDigest(attemptCtx, d, key)
+	cancel()
+	observe(ch.Name(), err)
+
+	results := make([]v1alpha1.DeliveryStatus, len(claims))
+	for i, st := range claims {
+		results[i] = p.result(st, id, err, now)
+	}
+	return results
+}
+
+// SendNotice makes one attempt to send n to the channel of t. Notices are
+// not retried.
+func SendNotice(ctx context.Context, t Target, n *Notice, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, t.Policy.Timeout)
+	defer cancel()
+	_, err := t.Channel.SendNotice(ctx, n, fmt.Sprintf("notice-%d/%s", now.Unix(), t.Channel.Name()))
+	observe(t.Channel.Name(), err)
+	return err
+}
+
+func observe(channel string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	attemptsTotal.WithLabelValues(channel, result).Inc()
+}
+
+// result records the outcome of the attempt claimed by st.
+func (p Policy) result(st v1alpha1.DeliveryStatus, id string, err error, now time.Time) v1alpha1.DeliveryStatus {
+	if err == nil {
+		st.State = v1alpha1.DeliverySent
+		st.LastError = ""
+		st.SentAt = &metav1.Time{Time: now}
+		st.MessageID = id
+		return st
+	}
+
+	st.LastError = err.Error()
+	if errors.Is(err, ErrPermanent) || st.Attempts >= p.MaxAttempts {
+		st.State = v1alpha1.DeliveryDeadLetter
+		deadLettersTotal.WithLabelValues(st.Channel).Inc()
+		return st
+	}
+
+	wait := p.Backoff(st.Attempts)
+	var ra *RetryAfterError
+	if errors.As(err, &ra) && ra.After > 0 {
+		wait = ra.After
+	}
+	st.State = v1alpha1.DeliveryPending
+	st.NextAttemptAt = &metav1.Time{Time: now.Add(wait)}
+	return st
+}
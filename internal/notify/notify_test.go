@@ -0,0 +1,182 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+type channelFunc func(ctx context.Context, key string) (string, error)
+
+func (f channelFunc) Name() string { return "test" }
+
+func (f channelFunc) Send(ctx context.Context, _ *Message, key string) (string, error) {
+	return f(ctx, key)
+}
+
+func (f channelFunc) SendDigest(ctx context.Context, _ *Digest, key string) (string, error) {
+	return f(ctx, key)
+}
+
+func (f channelFunc) SendNotice(ctx context.Context, _ *Notice, key string) (string, error) {
+	return f(ctx, key)
+}
+
+var _ = Describe("Policy", func() {
+	It("doubles the backoff up to the cap", func() {
+		p := Policy{InitialBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+		Expect(p.Backoff(1)).To(Equal(10 * time.Second))
+		Expect(p.Backoff(2)).To(Equal(20 * time.Second))
+		Expect(p.Backoff(3)).To(Equal(40 * time.Second))
+		Expect(p.Backoff(4)).To(Equal(time.Minute))
+		Expect(p.Backoff(60)).To(Equal(time.Minute))
+	})
+
+	It("fills unset spec fields with the defaults", func() {
+		p := PolicyFromSpec(&v1alpha1.NotificationChannelSpec{
+			Retry: &v1alpha1.RetrySpec{MaxAttempts: 3},
+		})
+		Expect(p).To(Equal(Policy{
+			Timeout:        DefaultTimeout,
+			MaxAttempts:    3,
+			InitialBackoff: DefaultInitialBackoff,
+			MaxBackoff:     DefaultMaxBackoff,
+		}))
+	})
+})
+
+var _ = Describe("Deliver", func() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	policy := Policy{Timeout: 50 * time.Millisecond, MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute}
+	rep := NewMessage(&v1alpha1.PolicyViolationReport{ObjectMeta: metav1.ObjectMeta{UID: "uid-1"}}, nil, "")
+
+	It("records the message ID of sent notifications under a stable key", func() {
+		var keys []string
+		ch := channelFunc(func(_ context.Context, key string) (string, error) {
+			keys = append(keys, key)
+			if len(keys) == 1 {
+				return "", errors.New("boom")
+			}
+			return "msg-1", nil
+		})
+		st := Deliver(context.Background(), ch, policy, rep, Claim(nil, "test", now), now)
+		Expect(st.State).To(Equal(v1alpha1.DeliveryPending))
+		st = Deliver(context.Background(), ch, policy, rep, Claim(&st, "test", now), now)
+		Expect(st.State).To(Equal(v1alpha1.DeliverySent))
+		Expect(st.Attempts).To(Equal(int32(2)))
+		Expect(st.MessageID).To(Equal("msg-1"))
+		Expect(st.SentAt.Time).To(Equal(now))
+		Expect(keys).To(Equal([]string{"uid-1/test", "uid-1/test"}))
+	})
+
+	It("bounds each attempt by the channel timeout", func() {
+		ch := channelFunc(func(ctx context.Context, _ string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		})
+		st := Deliver(context.Background(), ch, policy, rep, Claim(nil, "test", now), now)
+		Expect(st.State).To(Equal(v1alpha1.DeliveryPending))
+		Expect(st.LastError).To(ContainSubstring("deadline exceeded"))
+		Expect(st.NextAttemptAt.Time).To(Equal(now.Add(time.Second)))
+	})
+
+	It("continues from the previous attempts", func() {
+		failing := channelFunc(func(context.Context, string) (string, error) {
+			return "", errors.New("boom")
+		})
+		prev := &v1alpha1.DeliveryStatus{Channel: "test", State: v1alpha1.DeliveryPending, Attempts: 1,
+			NextAttemptAt: &metav1.Time{Time: now}}
+		claim := Claim(prev, "test", now)
+		Expect(claim.State).To(Equal(v1alpha1.DeliverySending))
+		Expect(claim.NextAttemptAt).To(BeNil())
+		Expect(prev.Attempts).To(Equal(int32(1)))
+
+		st := Deliver(context.Background(), failing, policy, rep, claim, now)
+		Expect(st.Attempts).To(Equal(int32(2)))
+		Expect(st.NextAttemptAt.Time).To(Equal(now.Add(2 * time.Second)))
+
+		st = Deliver(context.Background(), failing, policy, rep, Claim(&st, "test", now), now)
+		Expect(st.State).To(Equal(v1alpha1.DeliveryDeadLetter))
+		Expect(st.NextAttemptAt).To(BeNil())
+	})
+})
+
+var _ = Describe("Slack", func() {
+	var (
+		status int
+		header http.Header
+		srv    *httptest.Server
+	)
+
+	BeforeEach(func() {
+		status, header = http.StatusOK, http.Header{}
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	send := func() error {
+		_, err := NewSlack("ops", srv.URL, SlackOptions{}).Send(context.Background(), SampleMessage(""), "key")
+		return err
+	}
+
+	It("succeeds on 2xx", func() {
+		Expect(send()).To(Succeed())
+	})
+
+	It("returns the Retry-After delay on 429", func() {
+		status = http.StatusTooManyRequests
+		header.Set("Retry-After", "30")
+		var ra *RetryAfterError
+		Expect(errors.As(send(), &ra)).To(BeTrue())
+		Expect(ra.After).To(Equal(30 * time.Second))
+	})
+
+	It("retries server errors", func() {
+		status = http.StatusInternalServerError
+		err := send()
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrPermanent)).To(BeFalse())
+	})
+
+	It("treats other client errors as permanent", func() {
+		status = http.StatusForbidden
+		Expect(errors.Is(send(), ErrPermanent)).To(BeTrue())
+	})
+
+	It("parses Retry-After dates", func() {
+		now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+		Expect(retryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)).To(Equal(time.Minute))
+		Expect(retryAfter("garbage", now)).To(BeZero())
+		Expect(retryAfter("", now)).To(BeZero())
+	})
+})
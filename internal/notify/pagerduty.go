@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// pagerDutyEventsURL is the Events API v2 endpoint.
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDuty triggers and resolves PagerDuty incidents. The idempotency key
+// of a notification is the dedup key of its incident, so resends are merged
+// into the same incident and resolutions close it.
+type PagerDuty struct {
+	name       string
+	routingKey string
+	url        string
+	client     *http.Client
+}
+
+// NewPagerDuty returns a PagerDuty channel sending events to the service
+// integration identified by routingKey.
+func NewPagerDuty(name, routingKey string) *PagerDuty {
+	return &PagerDuty{name: name, routingKey: routingKey, url: pagerDutyEventsURL, client: &http.Client{}}
+}
+
+// Name implements Channel.
+func (p *PagerDuty) Name() string { return p.name }
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Component     string         `json:"component,omitempty"`
+	Group         string         `json:"group,omitempty"`
+	Class         string         `json:"class,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// Send implements Channel, triggering an incident deduplicated by the
+// idempotency key. Message templates do not apply to PagerDuty.
+func (p *PagerDuty) Send(ctx context.Context, m *Message, key string) (string, error) {
+	res := m.Resource
+	drift := make(map[string]any, len(m.Drift))
+	for _, d := range m.Drift {
+		drift[d.Key] = d.Value
+	}
+	return p.post(ctx, pagerDutyEvent{
+		EventAction: "trigger",
+		DedupKey:    key,
+		Payload: &pagerDutyPayload{
+			Summary:   fmt.Sprintf("%s %s/%s violates %s", res.Kind, res.Namespace, res.Name, m.Report.Spec.ProfileName),
+			Source:    pagerDutySource(m.Cluster),
+			Severity:  pagerDutySeverity(m.Severity),
+			Component: fmt.Sprintf("%s/%s/%s", res.Kind, res.Namespace, res.Name),
+			Group:     m.Report.Spec.ProfileName,
+			Class:     "policy-violation",
+			CustomDetails: map[string]any{
+				"report": m.Report.Namespace + "/" + m.Report.Name,
+				"drift":  drift,
+			},
+		},
+	})
+}
+
+// SendDigest implements Channel, triggering one incident for the batch.
+func (p *PagerDuty) SendDigest(ctx context.Context, d *Digest, key string) (string, error) {
+	groups := make([]string, 0, len(d.Groups))
+	for _, g := range d.Groups {
+		groups = append(groups, fmt.Sprintf("%s in %s: %d", g.Profile, g.Namespace, g.Count))
+	}
+	return p.post(ctx, pagerDutyEvent{
+		EventAction: "trigger",
+		DedupKey:    key,
+		Payload: &pagerDutyPayload{
+			Summary:       fmt.Sprintf("%s: %d violation(s)", d.Title, d.Total),
+			Source:        pagerDutySource(""),
+			Severity:      "warning",
+			Class:         "policy-violation",
+			CustomDetails: map[string]any{"groups": groups},
+		},
+	})
+}
+
+// SendNotice implements Channel. Notices are about the notifications
+// themselves and do not warrant an incident, so they are dropped.
+func (p *PagerDuty) SendNotice(context.Context, *Notice, string) (string, error) {
+	return "", nil
+}
+
+// SendResolved implements Resolver, resolving the incident of the
+// notification.
+func (p *PagerDuty) SendResolved(ctx context.Context, _ *Message, opened *v1alpha1.DeliveryStatus, key string) (string, error) {
+	if opened != nil && opened.MessageID != "" {
+		key = opened.MessageID
+	}
+	return p.post(ctx, pagerDutyEvent{EventAction: "resolve", DedupKey: key})
+}
+
+func (p *PagerDuty) post(ctx context.Context, ev pagerDutyEvent) (string, error) {
+	ev.RoutingKey = p.routingKey
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode < 300:
+		var res struct {
+			DedupKey string `json:"dedup_key"`
+		}
+		_ = json.Unmarshal(respBody, &res)
+		if res.DedupKey == "" {
+			res.DedupKey = ev.DedupKey
+		}
+		return res.DedupKey, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "", &RetryAfterError{
+			After: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:   fmt.Errorf("pagerduty events error: %s", resp.Status),
+		}
+	case resp.StatusCode >= 500:
+		return "", fmt.Errorf("pagerduty events error: %s", resp.Status)
+	default:
+		return "", fmt.Errorf("%w: pagerduty events error: %s: %s", ErrPermanent, resp.Status, bytes.TrimSpace(respBody))
+	}
+}
+
+func pagerDutySource(cluster string) string {
+	if cluster != "" {
+		return cluster
+	}
+	return "gokubedog"
+}
+
+// pagerDutySeverity maps report severities onto the PagerDuty ones.
+func pagerDutySeverity(s v1alpha1.Severity) string {
+	switch s {
+	case v1alpha1.SeverityCritical:
+		return "critical"
+	case v1alpha1.SeverityHigh:
+		return "error"
+	case v1alpha1.SeverityLow:
+		return "info"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("PagerDuty", func() {
+	var (
+		events []pagerDutyEvent
+		status int
+		pd     *PagerDuty
+	)
+
+	BeforeEach(func() {
+		events, status = nil, http.StatusAccepted
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ev pagerDutyEvent
+			Expect(json.NewDecoder(r.Body).Decode(&ev)).To(Succeed())
+			events = append(events, ev)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(`{"status":"success","dedup_key":"` + ev.DedupKey + `"}`))
+		}))
+		DeferCleanup(srv.Close)
+		pd = NewPagerDuty("oncall", "routing-key")
+		pd.url = srv.URL
+	})
+
+	It("triggers an incident deduplicated by the idempotency key and resolves it", func() {
+		msg := SampleMessage("prod-eu")
+		id, err := pd.Send(context.Background(), msg, "uid/oncall")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("uid/oncall"))
+		Expect(events[0].RoutingKey).To(Equal("routing-key"))
+		Expect(events[0].EventAction).To(Equal("trigger"))
+		Expect(events[0].Payload.Summary).To(Equal("NetworkPolicy team-a/allow-web violates require-owner"))
+		Expect(events[0].Payload.Source).To(Equal("prod-eu"))
+		Expect(events[0].Payload.Severity).To(Equal("error"))
+		Expect(events[0].Payload.CustomDetails["drift"]).To(HaveKeyWithValue("owner", "missing"))
+
+		opened := &v1alpha1.DeliveryStatus{Channel: "oncall", State: v1alpha1.DeliverySent, MessageID: id}
+		_, err = pd.SendResolved(context.Background(), msg, opened, "uid/oncall")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events[1].EventAction).To(Equal("resolve"))
+		Expect(events[1].DedupKey).To(Equal("uid/oncall"))
+		Expect(events[1].Payload).To(BeNil())
+	})
+
+	It("treats rejected events as permanent failures", func() {
+		status = http.StatusBadRequest
+		_, err := pd.Send(context.Background(), SampleMessage(""), "key")
+		Expect(errors.Is(err, ErrPermanent)).To(BeTrue())
+
+		status = http.StatusServiceUnavailable
+		_, err = pd.Send(context.Background(), SampleMessage(""), "key")
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrPermanent)).To(BeFalse())
+	})
+
+	It("does not open incidents for notices", func() {
+		_, err := pd.SendNotice(context.Background(), &Notice{Title: "t"}, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events).To(BeEmpty())
+	})
+})
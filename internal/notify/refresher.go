@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// Refresher resends the notifications of open violations to the channels
+// whose notifications expire, such as Alertmanager alerts. Only violations
+// whose notification was sent to the channel are refreshed, so suppressed
+// and dead-lettered ones are left alone. Acknowledged violations are not
+// refreshed either, so their alerts expire while they are silenced.
+type Refresher struct {
+	client.Client
+	// APIReader reads the Secrets of the channels. Falls back to Client
+	// when nil.
+	APIReader client.Reader
+	// ClusterName identifies the cluster in the notifications.
+	ClusterName string
+	// Interval is the time between two checks of the refresh intervals of
+	// the channels.
+	Interval time.Duration
+	// Channels keeps the channels built from the NotificationChannels. A new
+	// one is created when nil.
+	Channels *ChannelCache
+
+	last map[string]time.Time
+	now  func() time.Time
+}
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=notificationchannels,verbs=get;list;watch
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports,verbs=get;list;watch
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles,verbs=get;list;watch
+
+// Start refreshes the notifications until the context is cancelled. It
+// implements manager.Runnable.
+func (r *Refresher) Start(ctx context.Context) error {
+	l := logf.FromContext(ctx).WithName("refresh")
+
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Run(ctx); err != nil {
+			l.Error(err, "notification refresh failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only the leading manager refreshes.
+func (r *Refresher) NeedLeaderElection() bool {
+	return true
+}
+
+// Run refreshes the channels whose refresh interval elapsed. A channel that
+// fails is retried at the next check.
+func (r *Refresher) Run(ctx context.Context) error {
+	var secrets client.Reader = r.Client
+	if r.APIReader != nil {
+		secrets = r.APIReader
+	}
+	if r.Channels == nil {
+		r.Channels = NewChannelCache()
+	}
+	targets, err := r.Channels.Load(ctx, r.Client, secrets)
+	if err != nil {
+		return err
+	}
+	if r.last == nil {
+		r.last = map[string]time.Time{}
+	}
+
+	now := r.clock()
+	var open []*v1alpha1.PolicyViolationReport
+	listed := false
+	messages := map[*v1alpha1.PolicyViolationReport]*Message{}
+	var errs []error
+	for _, t := range targets {
+		ch, ok := t.Channel.(Refreshing)
+		if !ok {
+			continue
+		}
+		name := ch.Name()
+		if last, ok := r.last[name]; ok && now.Before(last.Add(ch.RefreshInterval())) {
+			continue
+		}
+
+		if !listed {
+			var list v1alpha1.PolicyViolationReportList
+			if err := r.List(ctx, &list); err != nil {
+				return fmt.Errorf("failed listing reports: %w", err)
+			}
+			for i := range list.Items {
+				rep := &list.Items[i]
+				if !rep.Status.IsResolved() && !rep.Spec.Acknowledgement.Active(now) {
+					open = append(open, rep)
+				}
+			}
+			listed = true
+		}
+		var ms []*Message
+		for _, rep := range open {
+			if st := rep.Status.Delivery(name); st == nil || st.State != v1alpha1.DeliverySent {
+				continue
+			}
+			m, ok := messages[rep]
+			if !ok {
+				if m, err = LoadMessage(ctx, r.Client, rep, r.ClusterName); err != nil {
+					return err
+				}
+				messages[rep] = m
+			}
+			ms = append(ms, m)
+		}
+
+		if len(ms) > 0 {
+			refreshCtx, cancel := context.WithTimeout(ctx, t.Policy.Timeout)
+			err := ch.Refresh(refreshCtx, ms)
+			cancel()
+			observe(name, err)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("channel %s: %w", name, err))
+				continue
+			}
+		}
+		r.last[name] = now
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Refresher) clock() time.Time {
+	if r.now != nil {
+		return r.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// Action IDs of the buttons of Slack messages. Their value is the
+// namespace/name of the report.
+const (
+	SlackActionAcknowledge = "gokubedog_acknowledge"
+	SlackActionException   = "gokubedog_exception"
+	SlackActionRemediate   = "gokubedog_remediate"
+)
+
+// slackPostMessageURL is the Web API method bots post with.
+const slackPostMessageURL = "https://slack.com/api/chat.postMessage"
+
+// Slack posts reports to Slack, through an incoming webhook or as a bot.
+type Slack struct {
+	name string
+	// url is the incoming webhook, or the Web API method bots post with.
+	url string
+	// token and channelID are only set for bots.
+	token, channelID string
+	opts             SlackOptions
+	client           *http.Client
+}
+
+// SlackOptions configures the messages of a Slack channel.
+type SlackOptions struct {
+	// Template renders the message text, the default template of the format
+	// if nil.
+	Template *Template
+	// Text posts plain text instead of Block Kit messages.
+	Text bool
+	// Actions adds the action buttons to Block Kit messages.
+	Actions bool
+}
+
+// NewSlack returns a Slack channel posting to webhookURL.
+func NewSlack(name, webhookURL string, opts SlackOptions) *Slack {
+	if opts.Template == nil {
+		opts.Template = builtinTemplate(slackDefaultTemplate(opts.Text))
+	}
+	return &Slack{name: name, url: webhookURL, opts: opts, client: &http.Client{}}
+}
+
+// NewSlackBot returns a Slack channel posting to channelID as the bot
+// authenticated by token.
+func NewSlackBot(name, token, channelID string, opts SlackOptions) *Slack {
+	s := NewSlack(name, slackPostMessageURL, opts)
+	s.token, s.channelID = token, channelID
+	return s
+}
+
+// DefaultSlackTemplate returns the built-in template of a Slack channel.
+func DefaultSlackTemplate(spec *v1alpha1.SlackChannelSpec) string {
+	return slackDefaultTemplate(spec != nil && spec.Format == v1alpha1.SlackFormatText)
+}
+
+func slackDefaultTemplate(text bool) string {
+	if text {
+		return DefaultTemplate
+	}
+	return DefaultBlocksTemplate
+}
+
+// Name implements Channel.
+func (s *Slack) Name() string { return s.name }
+
+// Send implements Channel. Bots return the timestamp of the message as its
+// ID, incoming webhooks return none. Neither accepts an idempotency key, so a
+// resend after an attempt with an unknown outcome may post twice. A 429 is
+// retried after the Retry-After delay, other 5xx responses with backoff; any
+// other failure status is permanent.
+func (s *Slack) Send(ctx context.Context, m *Message, _ string) (string, error) {
+	text, err := s.opts.Template.Render(m)
+	if err != nil {
+		return "", err
+	}
+	payload := slackPayload{Text: text}
+	if !s.opts.Text {
+		payload.Blocks = slackBlocks(m, text, s.opts.Actions)
+	}
+	return s.post(ctx, payload)
+}
+
+// SendDigest implements Channel, with the same caveats as Send.
+func (s *Slack) SendDigest(ctx context.Context, d *Digest, _ string) (string, error) {
+	return s.post(ctx, slackPayload{Text: formatSlackDigest(d)})
+}
+
+// SendNotice implements Channel, with the same caveats as Send.
+func (s *Slack) SendNotice(ctx context.Context, n *Notice, _ string) (string, error) {
+	return s.post(ctx, slackPayload{Text: fmt.Sprintf("*⚠️ %s*\n%s", n.Title, n.Text)})
+}
+
+// SendResolved implements Resolver. Bots reply in the thread of the
+// notification, webhooks cannot thread and post a new message.
+func (s *Slack) SendResolved(ctx context.Context, m *Message, opened *v1alpha1.DeliveryStatus, _ string) (string, error) {
+	var b strings.Builder
+	b.WriteString("*✅ Policy Violation Resolved*")
+	if m.Cluster != "" {
+		fmt.Fprintf(&b, " in %s", m.Cluster)
+	}
+	fmt.Fprintf(&b, "\n*Resource:* %s/%s (%s)\n*Policy:* %s",
+		m.Resource.Namespace, m.Resource.Name, m.Resource.Kind, m.Report.Spec.ProfileName)
+	payload := slackPayload{Text: b.String()}
+	if s.token != "" && opened != nil {
+		payload.ThreadTS = opened.MessageID
+	}
+	return s.post(ctx, payload)
+}
+
+// slackPayload is an incoming webhook message. Text is the notification
+// fallback of Block Kit messages.
+type slackPayload struct {
+	Channel  string       `json:"channel,omitempty"`
+	ThreadTS string       `json:"thread_ts,omitempty"`
+	Text     string       `json:"text"`
+	Blocks   []slackBlock `json:"blocks,omitempty"`
+}
+
+type slackBlock map[string]any
+
+func slackText(kind, text string) map[string]any {
+	return map[string]any{"type": kind, "text": text}
+}
+
+// Limits of the Block Kit fields the messages fill.
+const (
+	maxSlackSectionText = 3000
+	maxSlackFieldText   = 2000
+	maxSlackFields      = 10
+)
+
+// slackBlocks lays m out as a section holding the rendered text, sections
+// with a field per drift entry, a context line and the action buttons.
+func slackBlocks(m *Message, text string, actions bool) []slackBlock {
+	blocks := []slackBlock{{"type": "section", "text": slackText("mrkdwn", truncate(text, maxSlackSectionText))}}
+	for chunk := range slices.Chunk(m.Drift, maxSlackFields) {
+		fields := make([]map[string]any, 0, len(chunk))
+		for _, d := range chunk {
+			fields = append(fields, slackText("mrkdwn", truncate(fmt.Sprintf("*%s*\n%s", d.Key, d.Value), maxSlackFieldText)))
+		}
+		blocks = append(blocks, slackBlock{"type": "section", "fields": fields})
+	}
+
+	report := m.Report.Namespace + "/" + m.Report.Name
+	meta := []map[string]any{slackText("mrkdwn", "*Severity:* "+string(m.Severity))}
+	if m.Cluster != "" {
+		meta = append(meta, slackText("mrkdwn", "*Cluster:* "+m.Cluster))
+	}
+	meta = append(meta, slackText("mrkdwn", "*Report:* "+report))
+	blocks = append(blocks, slackBlock{"type": "context", "elements": meta})
+
+	if actions {
+		button := func(id, label, style string) map[string]any {
+			b := map[string]any{"type": "button", "action_id": id, "text": slackText("plain_text", label), "value": report}
+			if style != "" {
+				b["style"] = style
+			}
+			return b
+		}
+		remediate := button(SlackActionRemediate, "Auto-remediate", "danger")
+		remediate["confirm"] = map[string]any{
+			"title":   slackText("plain_text", "Auto-remediate?"),
+			"text":    slackText("mrkdwn", "Reset the drifted labels of "+m.Resource.Kind+" "+m.Resource.Namespace+"/"+m.Resource.Name+" to the policy."),
+			"confirm": slackText("plain_text", "Remediate"),
+			"deny":    slackText("plain_text", "Cancel"),
+		}
+		blocks = append(blocks, slackBlock{"type": "actions", "block_id": "gokubedog", "elements": []map[string]any{
+			button(SlackActionAcknowledge, "Acknowledge", "primary"),
+			button(SlackActionException, "Create exception", ""),
+			remediate,
+		}})
+	}
+	return blocks
+}
+
+// truncate cuts s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func (s *Slack) post(ctx context.Context, payload slackPayload) (string, error) {
+	payload.Channel = s.channelID
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode < 300 && s.token != "":
+		return slackAPIResult(respBody)
+	case resp.StatusCode < 300:
+		return "", nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "", &RetryAfterError{
+			After: retryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Err:   fmt.Errorf("slack webhook error: %s", resp.Status),
+		}
+	case resp.StatusCode >= 500:
+		return "", fmt.Errorf("slack webhook error: %s", resp.Status)
+	default:
+		return "", fmt.Errorf("%w: slack webhook error: %s", ErrPermanent, resp.Status)
+	}
+}
+
+// slackAPIResult returns the timestamp of a message posted by a bot. The Web
+// API reports most failures with a 200 and an error code in the body.
+func slackAPIResult(body []byte) (string, error) {
+	var res struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return "", fmt.Errorf("invalid slack API response: %v", err)
+	}
+	if res.OK {
+		return res.TS, nil
+	}
+	switch res.Error {
+	case "ratelimited", "internal_error", "fatal_error", "request_timeout", "service_unavailable":
+		return "", fmt.Errorf("slack API error: %s", res.Error)
+	default:
+		return "", fmt.Errorf("%w: slack API error: %s", ErrPermanent, res.Error)
+	}
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date. It returns 0 when the header is missing or malformed.
+func retryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+// maxDigestGroups bounds the groups listed in a Slack digest.
+const maxDigestGroups = 20
+
+func formatSlackDigest(d *Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*📋 %s*\n*%d violation(s)*\n", d.Title, d.Total)
+	for i, g := range d.Groups {
+		if i == maxDigestGroups {
+			fmt.Fprintf(&b, "• …and %d more\n", len(d.Groups)-maxDigestGroups)
+			break
+		}
+		fmt.Fprintf(&b, "• %s in %s: %d\n", g.Profile, g.Namespace, g.Count)
+	}
+	if len(d.TopOffenders) > 0 {
+		b.WriteString("*Top offenders:*\n")
+		for _, o := range d.TopOffenders {
+			fmt.Fprintf(&b, "• %s/%s (%s): %d\n", o.Resource.Namespace, o.Resource.Name, o.Resource.Kind, o.Count)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
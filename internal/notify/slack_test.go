@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Slack messages", func() {
+	var (
+		payload map[string]any
+		srv     *httptest.Server
+	)
+
+	BeforeEach(func() {
+		payload = nil
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(json.NewDecoder(r.Body).Decode(&payload)).To(Succeed())
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	send := func(opts SlackOptions) []any {
+		_, err := NewSlack("ops", srv.URL, opts).Send(context.Background(), SampleMessage("prod-eu"), "key")
+		Expect(err).NotTo(HaveOccurred())
+		blocks, _ := payload["blocks"].([]any)
+		return blocks
+	}
+
+	blockTypes := func(blocks []any) []string {
+		var types []string
+		for _, b := range blocks {
+			types = append(types, b.(map[string]any)["type"].(string))
+		}
+		return types
+	}
+
+	It("lays out Block Kit messages with a field per drift entry", func() {
+		blocks := send(SlackOptions{})
+		Expect(payload["text"]).To(ContainSubstring("*Policy:* require-owner"))
+		Expect(payload["text"]).NotTo(ContainSubstring("*Drift:*"))
+		Expect(blockTypes(blocks)).To(Equal([]string{"section", "section", "context"}))
+
+		fields := blocks[1].(map[string]any)["fields"].([]any)
+		Expect(fields).To(HaveLen(2))
+		Expect(fields[0].(map[string]any)["text"]).To(Equal("*owner*\nmissing"))
+		Expect(fields[1].(map[string]any)["text"]).To(Equal("*tier*\nexpected backend, got frontend"))
+
+		meta := blocks[2].(map[string]any)["elements"].([]any)
+		Expect(meta).To(HaveLen(3))
+		Expect(meta[2].(map[string]any)["text"]).To(Equal("*Report:* team-a/violation-sample"))
+	})
+
+	It("adds action buttons naming the report", func() {
+		blocks := send(SlackOptions{Actions: true})
+		Expect(blockTypes(blocks)).To(Equal([]string{"section", "section", "context", "actions"}))
+
+		buttons := blocks[3].(map[string]any)["elements"].([]any)
+		var ids []string
+		for _, b := range buttons {
+			button := b.(map[string]any)
+			Expect(button["value"]).To(Equal("team-a/violation-sample"))
+			ids = append(ids, button["action_id"].(string))
+		}
+		Expect(ids).To(Equal([]string{SlackActionAcknowledge, SlackActionException, SlackActionRemediate}))
+		Expect(buttons[2].(map[string]any)).To(HaveKey("confirm"))
+	})
+
+	It("posts the default text template without blocks in text format", func() {
+		Expect(send(SlackOptions{Text: true})).To(BeNil())
+		Expect(payload["text"]).To(ContainSubstring("*Drift:*"))
+	})
+
+	It("threads resolutions under the message posted by a bot", func() {
+		var auth []string
+		var posted []map[string]any
+		api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]any
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			auth = append(auth, r.Header.Get("Authorization"))
+			posted = append(posted, body)
+			_, _ = w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+		}))
+		DeferCleanup(api.Close)
+		bot := NewSlackBot("ops", "xoxb-token", "C123", SlackOptions{})
+		bot.url = api.URL
+
+		msg := SampleMessage("")
+		id, err := bot.Send(context.Background(), msg, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("1700000000.000100"))
+
+		opened := &v1alpha1.DeliveryStatus{Channel: "ops", State: v1alpha1.DeliverySent, MessageID: id}
+		_, err = bot.SendResolved(context.Background(), msg, opened, "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(auth).To(HaveEach("Bearer xoxb-token"))
+		Expect(posted[0]["channel"]).To(Equal("C123"))
+		Expect(posted[0]).NotTo(HaveKey("thread_ts"))
+		Expect(posted[1]["thread_ts"]).To(Equal("1700000000.000100"))
+		Expect(posted[1]["text"]).To(ContainSubstring("Policy Violation Resolved"))
+	})
+
+	It("maps Web API errors onto retryable and permanent failures", func() {
+		_, err := slackAPIResult([]byte(`{"ok":false,"error":"ratelimited"}`))
+		Expect(err).To(HaveOccurred())
+		Expect(errors.Is(err, ErrPermanent)).To(BeFalse())
+		_, err = slackAPIResult([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		Expect(errors.Is(err, ErrPermanent)).To(BeTrue())
+	})
+
+	It("truncates text to the Block Kit limits", func() {
+		Expect(truncate("abcdef", 4)).To(Equal("abc…"))
+		Expect(truncate("abc", 4)).To(Equal("abc"))
+	})
+})
@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"slices"
+	"strings"
+	"text/template"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// DefaultTemplate is the message template of channels that do not set one.
+const DefaultTemplate = "*🚨 Policy Violation Detected*{{ with .Cluster }} in {{ . }}{{ end }}\n" +
+	"*Resource:* {{ .Resource.Namespace }}/{{ .Resource.Name }} ({{ .Resource.Kind }})\n" +
+	"*Policy:* {{ .Report.Spec.ProfileName }}\n" +
+	"*Severity:* {{ .Severity }}\n" +
+	"*Drift:*\n```{{ json .Report.Spec.Drift }}```" +
+	"{{ with .Report.Spec.Remediation }}\n*Remediation:* {{ . }}{{ end }}"
+
+// DefaultBlocksTemplate is the message template of Slack Block Kit channels
+// that do not set one. Severity and drift have their own blocks.
+const DefaultBlocksTemplate = "*🚨 Policy Violation Detected*{{ with .Cluster }} in {{ . }}{{ end }}\n" +
+	"*Resource:* {{ .Resource.Namespace }}/{{ .Resource.Name }} ({{ .Resource.Kind }})\n" +
+	"*Policy:* {{ .Report.Spec.ProfileName }}" +
+	"{{ with .Report.Spec.Remediation }}\n*Remediation:* {{ . }}{{ end }}"
+
+// Message is a violation to notify, and the data message templates see.
+type Message struct {
+	Report *v1alpha1.PolicyViolationReport
+	// Profile is the profile that reported the violation, nil if it is gone.
+	Profile  *v1alpha1.PolicyProfile
+	Resource v1alpha1.ViolatedResourceSpec
+	Severity v1alpha1.Severity
+	// Drift lists the drift of the report sorted by key.
+	Drift []DriftEntry
+	// Cluster is the name of the cluster, if configured.
+	Cluster string
+}
+
+// DriftEntry is one drifted key and its description.
+type DriftEntry struct {
+	Key   string
+	Value string
+}
+
+// NewMessage returns the message notifying the violation reported by rep.
+func NewMessage(rep *v1alpha1.PolicyViolationReport, profile *v1alpha1.PolicyProfile, cluster string) *Message {
+	m := &Message{
+		Report:   rep,
+		Profile:  profile,
+		Resource: rep.Spec.ViolatedResource,
+		Severity: rep.Spec.Severity,
+		Cluster:  cluster,
+	}
+	if m.Severity == "" {
+		m.Severity = v1alpha1.SeverityMedium
+	}
+	for k, v := range rep.Spec.Drift {
+		m.Drift = append(m.Drift, DriftEntry{Key: k, Value: v})
+	}
+	slices.SortFunc(m.Drift, func(a, b DriftEntry) int { return strings.Compare(a.Key, b.Key) })
+	return m
+}
+
+// LoadMessage returns the message notifying the violation reported by rep,
+// with the profile that reported it read from c if it still exists.
+func LoadMessage(ctx context.Context, c client.Reader, rep *v1alpha1.PolicyViolationReport, cluster string) (*Message, error) {
+	key := types.NamespacedName{Namespace: rep.Spec.ProfileNamespace, Name: rep.Spec.ProfileName}
+	if name, ok := rep.Labels[v1alpha1.ProfileNameLabel]; ok {
+		key = types.NamespacedName{Namespace: rep.Labels[v1alpha1.ProfileNamespaceLabel], Name: name}
+	}
+	if key.Name == "" {
+		return NewMessage(rep, nil, cluster), nil
+	}
+	profile := &v1alpha1.PolicyProfile{}
+	if err := c.Get(ctx, key, profile); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed fetching PolicyProfile %s: %w", key, err)
+		}
+		profile = nil
+	}
+	return NewMessage(rep, profile, cluster), nil
+}
+
+// SampleMessage returns a message with every field set, used to validate
+// templates and to preview them.
+func SampleMessage(cluster string) *Message {
+	rep := &v1alpha1.PolicyViolationReport{
+		ObjectMeta: metav1.ObjectMeta{Name: "violation-sample", Namespace: "team-a"},
+		Spec: v1alpha1.PolicyViolationReportSpec{
+			ViolatedResource: v1alpha1.ViolatedResourceSpec{Kind: "NetworkPolicy", Namespace: "team-a", Name: "allow-web"},
+			ProfileName:      "require-owner",
+			ProfileNamespace: "team-a",
+			Drift:            map[string]string{"owner": "missing", "tier": "expected backend, got frontend"},
+			Severity:         v1alpha1.SeverityHigh,
+		},
+	}
+	profile := &v1alpha1.PolicyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-owner", Namespace: "team-a"},
+		Spec:       v1alpha1.PolicyProfileSpec{Severity: v1alpha1.SeverityHigh},
+	}
+	return NewMessage(rep, profile, cluster)
+}
+
+var funcs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.MarshalIndent(v, "", "  ")
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Template renders messages, with overrides for some profiles.
+type Template struct {
+	def      *template.Template
+	profiles []profileTemplate
+}
+
+type profileTemplate struct {
+	name, namespace string
+	tmpl            *template.Template
+}
+
+// ParseTemplate parses a single template and validates it against a sample
+// message, so that templates referring to unknown fields fail early.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Execute(&bytes.Buffer{}, SampleMessage("sample")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ParseHTMLTemplate parses an html/template and validates it like
+// ParseTemplate.
+func ParseHTMLTemplate(name, text string) (*htmltemplate.Template, error) {
+	t, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap(funcs)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.Execute(&bytes.Buffer{}, SampleMessage("sample")); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DefaultChannelTemplate returns the built-in message template of a channel.
+func DefaultChannelTemplate(spec *v1alpha1.NotificationChannelSpec) string {
+	if spec.Type == v1alpha1.ChannelTypeEmail {
+		return DefaultEmailTemplate
+	}
+	return DefaultSlackTemplate(spec.Slack)
+}
+
+// DefaultMessageTemplate returns the template of channels that do not set one.
+func DefaultMessageTemplate() *Template {
+	return builtinTemplate(DefaultTemplate)
+}
+
+func builtinTemplate(text string) *Template {
+	t, err := ParseTemplate("default", text)
+	if err != nil {
+		panic(err)
+	}
+	return &Template{def: t}
+}
+
+// NewTemplate parses the message templates of spec, resolving ConfigMap
+// references through lookup. Messages matching no template of spec are
+// rendered with def, one of the built-in default templates.
+func NewTemplate(
+	spec *v1alpha1.MessageTemplateSpec, def string, lookup func(v1alpha1.ConfigMapKeySelector) (string, error),
+) (*Template, error) {
+	t := builtinTemplate(def)
+	if spec == nil {
+		return t, nil
+	}
+	parse := func(name string, src v1alpha1.TemplateSource) (*template.Template, error) {
+		text := src.Inline
+		if ref := src.ConfigMapKeyRef; ref != nil {
+			var err error
+			if text, err = lookup(*ref); err != nil {
+				return nil, err
+			}
+		}
+		tmpl, err := ParseTemplate(name, text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s template: %w", name, err)
+		}
+		return tmpl, nil
+	}
+	if spec.Default != nil {
+		def, err := parse("default", *spec.Default)
+		if err != nil {
+			return nil, err
+		}
+		t.def = def
+	}
+	for _, p := range spec.Profiles {
+		tmpl, err := parse("profile "+p.Profile, p.Template)
+		if err != nil {
+			return nil, err
+		}
+		t.profiles = append(t.profiles, profileTemplate{name: p.Profile, namespace: p.ProfileNamespace, tmpl: tmpl})
+	}
+	return t, nil
+}
+
+// Render renders m with the template of its profile.
+func (t *Template) Render(m *Message) (string, error) {
+	tmpl := t.def
+	for _, p := range t.profiles {
+		if p.name == m.Report.Spec.ProfileName &&
+			(p.namespace == "" || p.namespace == m.Report.Spec.ProfileNamespace) {
+			tmpl = p.tmpl
+			break
+		}
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, m); err != nil {
+		return "", fmt.Errorf("%w: rendering message: %v", ErrPermanent, err)
+	}
+	return b.String(), nil
+}
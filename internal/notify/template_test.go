@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Template", func() {
+	noConfigMaps := func(ref v1alpha1.ConfigMapKeySelector) (string, error) {
+		return "", fmt.Errorf("unexpected lookup of %s", ref.Name)
+	}
+
+	It("renders the default template without a banner", func() {
+		text, err := DefaultMessageTemplate().Render(SampleMessage("prod-eu"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(HavePrefix("*🚨 Policy Violation Detected* in prod-eu\n"))
+		Expect(text).To(ContainSubstring("*Resource:* team-a/allow-web (NetworkPolicy)"))
+		Expect(text).To(ContainSubstring("*Severity:* high"))
+		Expect(text).NotTo(ContainSubstring("MADMMAS"))
+		Expect(text).NotTo(ContainSubstring("Remediation"))
+
+		msg := SampleMessage("")
+		msg.Report.Spec.Remediation = "Label the resource with its team."
+		text, err = DefaultMessageTemplate().Render(msg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(text).To(HaveSuffix("\n*Remediation:* Label the resource with its team."))
+	})
+
+	It("exposes the sorted drift entries and defaults the severity", func() {
+		tmpl, err := NewTemplate(&v1alpha1.MessageTemplateSpec{Default: &v1alpha1.TemplateSource{
+			Inline: "{{ upper (print .Severity) }}:{{ range .Drift }} {{ .Key }}={{ .Value }}{{ end }}",
+		}}, DefaultTemplate, noConfigMaps)
+		Expect(err).NotTo(HaveOccurred())
+		rep := &v1alpha1.PolicyViolationReport{Spec: v1alpha1.PolicyViolationReportSpec{
+			Drift: map[string]string{"b": "2", "a": "1"},
+		}}
+		Expect(tmpl.Render(NewMessage(rep, nil, ""))).To(Equal("MEDIUM: a=1 b=2"))
+	})
+
+	It("uses the template of the profile when one matches", func() {
+		tmpl, err := NewTemplate(&v1alpha1.MessageTemplateSpec{
+			Profiles: []v1alpha1.ProfileTemplate{
+				{Profile: "require-owner", ProfileNamespace: "other", Template: v1alpha1.TemplateSource{Inline: "other"}},
+				{Profile: "require-owner", Template: v1alpha1.TemplateSource{
+					ConfigMapKeyRef: &v1alpha1.ConfigMapKeySelector{Namespace: "ns", Name: "tmpl", Key: "owner"},
+				}},
+			},
+		}, DefaultTemplate, func(ref v1alpha1.ConfigMapKeySelector) (string, error) {
+			Expect(ref.Key).To(Equal("owner"))
+			return "owner of {{ .Resource.Name }}", nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tmpl.Render(SampleMessage(""))).To(Equal("owner of allow-web"))
+
+		msg := SampleMessage("")
+		msg.Report.Spec.ProfileName = "another"
+		Expect(tmpl.Render(msg)).To(HavePrefix("*🚨 Policy Violation Detected*"))
+	})
+
+	It("rejects invalid templates when they are loaded", func() {
+		_, err := NewTemplate(&v1alpha1.MessageTemplateSpec{Default: &v1alpha1.TemplateSource{
+			Inline: "{{ .Resource.Nmae }}",
+		}}, DefaultTemplate, noConfigMaps)
+		Expect(err).To(MatchError(ContainSubstring("invalid default template")))
+
+		_, err = NewTemplate(&v1alpha1.MessageTemplateSpec{Default: &v1alpha1.TemplateSource{
+			Inline: "{{ if }}",
+		}}, DefaultTemplate, noConfigMaps)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails permanently when a message cannot be rendered", func() {
+		tmpl, err := NewTemplate(&v1alpha1.MessageTemplateSpec{Default: &v1alpha1.TemplateSource{
+			Inline: "{{ .Profile.Spec.Severity }}",
+		}}, DefaultTemplate, noConfigMaps)
+		Expect(err).NotTo(HaveOccurred())
+		msg := SampleMessage("")
+		msg.Profile = nil
+		_, err = tmpl.Render(msg)
+		Expect(errors.Is(err, ErrPermanent)).To(BeTrue())
+	})
+})
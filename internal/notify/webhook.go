@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// Headers of the requests of webhook channels.
+const (
+	WebhookTimestampHeader = "X-Gokubedog-Timestamp"
+	WebhookSignatureHeader = "X-Gokubedog-Signature"
+)
+
+// Webhook posts CloudEvents 1.0 events to an HTTP endpoint. The ID of an
+// event is derived from the idempotency key of the notification, so that
+// receivers can drop the duplicates of a resent event.
+type Webhook struct {
+	name   string
+	url    string
+	opts   WebhookOptions
+	client *http.Client
+}
+
+// WebhookOptions configures the requests of a webhook channel.
+type WebhookOptions struct {
+	// Binary sends the event attributes as headers instead of in the body.
+	Binary bool
+	// SigningSecret signs the requests when set.
+	SigningSecret []byte
+	// Headers are added to every request.
+	Headers map[string]string
+	// Client sends the requests, e.g. with a client certificate. A default
+	// client is used if nil.
+	Client *http.Client
+}
+
+// NewWebhook returns a webhook channel posting to url.
+func NewWebhook(name, url string, opts WebhookOptions) *Webhook {
+	c := opts.Client
+	if c == nil {
+		c = &http.Client{}
+	}
+	return &Webhook{name: name, url: url, opts: opts, client: c}
+}
+
+// CloseIdleConnections closes the idle connections of the client given in
+// the options, if any.
+func (w *Webhook) CloseIdleConnections() {
+	if w.opts.Client != nil {
+		w.opts.Client.CloseIdleConnections()
+	}
+}
+
+// Name implements Channel.
+func (w *Webhook) Name() string { return w.name }
+
+// cloudEvent is a CloudEvents 1.0 event in the JSON format.
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	// Cluster is an extension attribute naming the cluster, if configured.
+	Cluster string `json:"cluster,omitempty"`
+	Data    any    `json:"data"`
+}
+
+// Send implements Channel with a violation opened event. Message templates
+// do not apply to webhooks.
+func (w *Webhook) Send(ctx context.Context, m *Message, key string) (string, error) {
+	return w.post(ctx, violationEvent(v1alpha1.EventTypeViolationOpened, key, m))
+}
+
+// SendResolved implements Resolver with a violation resolved event.
+func (w *Webhook) SendResolved(ctx context.Context, m *Message, _ *v1alpha1.DeliveryStatus, key string) (string, error) {
+	return w.post(ctx, violationEvent(v1alpha1.EventTypeViolationResolved, key+"/resolved", m))
+}
+
+// SendDigest implements Channel with a digest event.
+func (w *Webhook) SendDigest(ctx context.Context, d *Digest, key string) (string, error) {
+	data := v1alpha1.DigestEventData{Title: d.Title, Total: d.Total, Groups: []v1alpha1.DigestEventGroup{}}
+	for _, g := range d.Groups {
+		data.Groups = append(data.Groups, v1alpha1.DigestEventGroup{Profile: g.Profile, Namespace: g.Namespace, Count: g.Count})
+	}
+	for _, o := range d.TopOffenders {
+		data.TopOffenders = append(data.TopOffenders, v1alpha1.DigestEventOffender{Resource: o.Resource, Count: o.Count})
+	}
+	return w.post(ctx, &cloudEvent{ID: key, Type: v1alpha1.EventTypeDigest, Data: data})
+}
+
+// SendNotice implements Channel with a notice event.
+func (w *Webhook) SendNotice(ctx context.Context, n *Notice, key string) (string, error) {
+	return w.post(ctx, &cloudEvent{
+		ID:   key,
+		Type: v1alpha1.EventTypeNotice,
+		Data: v1alpha1.NoticeEventData{Title: n.Title, Text: n.Text},
+	})
+}
+
+func violationEvent(typ, id string, m *Message) *cloudEvent {
+	rep := m.Report
+	drift := rep.Spec.Drift
+	if drift == nil {
+		drift = map[string]string{}
+	}
+	phase := rep.Status.Phase
+	if phase == "" {
+		phase = v1alpha1.ReportPhaseOpen
+	}
+	return &cloudEvent{
+		ID:      id,
+		Type:    typ,
+		Subject: rep.Namespace + "/" + rep.Name,
+		Cluster: m.Cluster,
+		Data: v1alpha1.ViolationEventData{
+			Report:           v1alpha1.EventReportReference{Name: rep.Name, Namespace: rep.Namespace, UID: string(rep.UID)},
+			Profile:          rep.Spec.ProfileName,
+			ProfileNamespace: rep.Spec.ProfileNamespace,
+			Resource:         m.Resource,
+			Severity:         m.Severity,
+			Drift:            drift,
+			Phase:            phase,
+			Ownership:        rep.Spec.Ownership,
+			Cluster:          m.Cluster,
+			OpenedAt:         rep.Status.OpenedAt,
+			ResolvedAt:       rep.Status.ResolvedAt,
+		},
+	}
+}
+
+func (w *Webhook) post(ctx context.Context, ev *cloudEvent) (string, error) {
+	now := time.Now()
+	ev.SpecVersion = "1.0"
+	ev.Source = v1alpha1.EventSource
+	ev.Time = now.UTC().Format(time.RFC3339Nano)
+	ev.DataContentType = "application/json"
+
+	var body []byte
+	var err error
+	if w.opts.Binary {
+		body, err = json.Marshal(ev.Data)
+	} else {
+		body, err = json.Marshal(ev)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrPermanent, err)
+	}
+	for k, v := range w.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.opts.Binary {
+		req.Header.Set("Content-Type", ev.DataContentType)
+		req.Header.Set("ce-specversion", ev.SpecVersion)
+		req.Header.Set("ce-id", ev.ID)
+		req.Header.Set("ce-source", ev.Source)
+		req.Header.Set("ce-type", ev.Type)
+		req.Header.Set("ce-time", ev.Time)
+		if ev.Subject != "" {
+			req.Header.Set("ce-subject", ev.Subject)
+		}
+		if ev.Cluster != "" {
+			req.Header.Set("ce-cluster", ev.Cluster)
+		}
+	} else {
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+	}
+	if len(w.opts.SigningSecret) > 0 {
+		ts := strconv.FormatInt(now.Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, ts)
+		req.Header.Set(WebhookSignatureHeader, WebhookSignature(w.opts.SigningSecret, ts, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode < 300:
+		return ev.ID, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return "", &RetryAfterError{
+			After: retryAfter(resp.Header.Get("Retry-After"), now),
+			Err:   fmt.Errorf("webhook error: %s", resp.Status),
+		}
+	case resp.StatusCode >= 500, resp.StatusCode == http.StatusRequestTimeout:
+		return "", fmt.Errorf("webhook error: %s", resp.Status)
+	default:
+		return "", fmt.Errorf("%w: webhook error: %s", ErrPermanent, resp.Status)
+	}
+}
+
+// WebhookSignature returns the X-Gokubedog-Signature of a request body sent
+// at timestamp, in Unix seconds: "sha256=" followed by the hex HMAC-SHA256 of
+// the timestamp, a dot and the body.
+func WebhookSignature(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = fmt.Fprintf(mac, "%s.", timestamp)
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Webhook", func() {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	var (
+		requests []request
+		srv      *httptest.Server
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		srv = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests = append(requests, request{header: r.Header, body: body})
+		}))
+		DeferCleanup(srv.Close)
+	})
+
+	It("sends signed structured events", func() {
+		w := NewWebhook("bus", srv.URL, WebhookOptions{SigningSecret: []byte("s3cret")})
+		id, err := w.Send(context.Background(), SampleMessage("prod-eu"), "uid/bus")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(id).To(Equal("uid/bus"))
+
+		req := requests[0]
+		Expect(req.header.Get("Content-Type")).To(Equal("application/cloudevents+json"))
+		ts := req.header.Get(WebhookTimestampHeader)
+		Expect(req.header.Get(WebhookSignatureHeader)).To(Equal(WebhookSignature([]byte("s3cret"), ts, req.body)))
+
+		var ev struct {
+			SpecVersion string                      `json:"specversion"`
+			ID          string                      `json:"id"`
+			Source      string                      `json:"source"`
+			Type        string                      `json:"type"`
+			Subject     string                      `json:"subject"`
+			Cluster     string                      `json:"cluster"`
+			Data        v1alpha1.ViolationEventData `json:"data"`
+		}
+		Expect(json.Unmarshal(req.body, &ev)).To(Succeed())
+		Expect(ev.SpecVersion).To(Equal("1.0"))
+		Expect(ev.ID).To(Equal("uid/bus"))
+		Expect(ev.Source).To(Equal(v1alpha1.EventSource))
+		Expect(ev.Type).To(Equal(v1alpha1.EventTypeViolationOpened))
+		Expect(ev.Subject).To(Equal("team-a/violation-sample"))
+		Expect(ev.Cluster).To(Equal("prod-eu"))
+		Expect(ev.Data.Profile).To(Equal("require-owner"))
+		Expect(ev.Data.Phase).To(Equal(v1alpha1.ReportPhaseOpen))
+		Expect(ev.Data.Drift).To(HaveKeyWithValue("owner", "missing"))
+	})
+
+	It("sends binary events with custom headers", func() {
+		w := NewWebhook("bus", srv.URL, WebhookOptions{Binary: true, Headers: map[string]string{"Authorization": "Bearer t"}})
+		_, err := w.SendResolved(context.Background(), SampleMessage(""), nil, "uid/bus")
+		Expect(err).NotTo(HaveOccurred())
+
+		req := requests[0]
+		Expect(req.header.Get("Authorization")).To(Equal("Bearer t"))
+		Expect(req.header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(req.header.Get("ce-specversion")).To(Equal("1.0"))
+		Expect(req.header.Get("ce-id")).To(Equal("uid/bus/resolved"))
+		Expect(req.header.Get("ce-type")).To(Equal(v1alpha1.EventTypeViolationResolved))
+		Expect(req.header.Get(WebhookSignatureHeader)).To(BeEmpty())
+
+		var data v1alpha1.ViolationEventData
+		Expect(json.Unmarshal(req.body, &data)).To(Succeed())
+		Expect(data.Resource.Name).To(Equal("allow-web"))
+	})
+
+	It("authenticates with the client certificate of the channel", func() {
+		certPEM, keyPEM := clientCertificate()
+		pool := x509.NewCertPool()
+		Expect(pool.AppendCertsFromPEM(certPEM)).To(BeTrue())
+		tlsSrv := httptest.NewUnstartedServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			Expect(r.TLS.PeerCertificates).To(HaveLen(1))
+			requests = append(requests, request{header: r.Header})
+		}))
+		tlsSrv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+		tlsSrv.StartTLS()
+		DeferCleanup(tlsSrv.Close)
+		caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsSrv.Certificate().Raw})
+
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		ref := &v1alpha1.SecretReference{Name: "bus-tls", Namespace: "gokubedog-system"}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: ref.Name, Namespace: ref.Namespace},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM, "ca.crt": caPEM},
+			},
+			&v1alpha1.NotificationChannel{
+				ObjectMeta: metav1.ObjectMeta{Name: "bus"},
+				Spec: v1alpha1.NotificationChannelSpec{
+					Type:    v1alpha1.ChannelTypeWebhook,
+					Webhook: &v1alpha1.WebhookChannelSpec{URL: tlsSrv.URL, ClientCertSecretRef: ref},
+				},
+			},
+		).Build()
+
+		targets, err := Load(context.Background(), cl, cl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(HaveLen(1))
+		_, err = targets[0].Channel.Send(context.Background(), SampleMessage(""), "key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(HaveLen(1))
+	})
+})
+
+// clientCertificate returns a self-signed client certificate and its key.
+func clientCertificate() (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gokubedog"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ownership resolves who owns a resource from the metadata of the
+// resource, of the chain of its controllers and of its namespace, so that
+// violations can be routed to their owners.
+package ownership
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// DefaultMaxDepth bounds the controllers followed up from a resource, enough
+// for Pod, ReplicaSet, Deployment.
+const DefaultMaxDepth = 3
+
+// Keys lists, per field, the annotation or label keys holding it in order of
+// preference. Annotations take precedence over labels with the same key.
+type Keys struct {
+	Team    []string
+	Contact []string
+	OnCall  []string
+}
+
+// DefaultKeys returns the keys used when none are configured.
+func DefaultKeys() Keys {
+	return Keys{
+		Team:    []string{"gokubedog.io/team", "team"},
+		Contact: []string{"gokubedog.io/contact", watchdogv1alpha1.DefaultRecipientAnnotation, "contact"},
+		OnCall:  []string{"gokubedog.io/oncall", "oncall"},
+	}
+}
+
+// DefaultOwnerKinds are the controllers whose metadata is read. Resources
+// controlled by other kinds stop the owner chain there.
+func DefaultOwnerKinds() []schema.GroupKind {
+	return []schema.GroupKind{
+		{Group: "apps", Kind: "ReplicaSet"},
+		{Group: "apps", Kind: "Deployment"},
+		{Group: "apps", Kind: "StatefulSet"},
+		{Group: "apps", Kind: "DaemonSet"},
+		{Group: "batch", Kind: "Job"},
+		{Group: "batch", Kind: "CronJob"},
+	}
+}
+
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=replicasets;deployments;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
+
+// Resolver resolves the ownership of resources. Each field is taken from the
+// first of the resource, its controllers and its namespace that sets it.
+type Resolver struct {
+	// Reader reads the metadata of controllers and namespaces, usually the
+	// cached manager client.
+	Reader client.Reader
+	Keys   Keys
+	// OwnerKinds are the controllers followed, DefaultOwnerKinds if nil.
+	OwnerKinds []schema.GroupKind
+	// MaxDepth bounds the controllers followed, DefaultMaxDepth if zero.
+	MaxDepth int
+}
+
+// Resolve returns the ownership of obj, nil if no field could be resolved.
+// Controllers or namespaces that are gone or forbidden end the lookup without
+// an error.
+func (r *Resolver) Resolve(ctx context.Context, obj *metav1.PartialObjectMetadata) (*watchdogv1alpha1.Ownership, error) {
+	var o watchdogv1alpha1.Ownership
+	r.fill(&o, &obj.ObjectMeta)
+
+	kinds := r.OwnerKinds
+	if kinds == nil {
+		kinds = DefaultOwnerKinds()
+	}
+	depth := r.MaxDepth
+	if depth == 0 {
+		depth = DefaultMaxDepth
+	}
+	cur := &obj.ObjectMeta
+	for range depth {
+		if r.complete(&o) {
+			break
+		}
+		ref := metav1.GetControllerOfNoCopy(cur)
+		if ref == nil {
+			break
+		}
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil || !slices.Contains(kinds, gv.WithKind(ref.Kind).GroupKind()) {
+			break
+		}
+		owner := &metav1.PartialObjectMetadata{}
+		owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+		key := types.NamespacedName{Namespace: obj.Namespace, Name: ref.Name}
+		if err := r.Reader.Get(ctx, key, owner); err != nil {
+			if ignorable(err) {
+				break
+			}
+			return nil, fmt.Errorf("failed fetching %s %s: %w", ref.Kind, key, err)
+		}
+		r.fill(&o, &owner.ObjectMeta)
+		cur = &owner.ObjectMeta
+	}
+
+	if !r.complete(&o) && obj.Namespace != "" {
+		ns := &metav1.PartialObjectMetadata{}
+		ns.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Namespace"))
+		if err := r.Reader.Get(ctx, types.NamespacedName{Name: obj.Namespace}, ns); err != nil {
+			if !ignorable(err) {
+				return nil, fmt.Errorf("failed fetching Namespace %s: %w", obj.Namespace, err)
+			}
+		} else {
+			r.fill(&o, &ns.ObjectMeta)
+		}
+	}
+
+	if o == (watchdogv1alpha1.Ownership{}) {
+		return nil, nil
+	}
+	return &o, nil
+}
+
+// fill sets the fields of o that are still empty from meta.
+func (r *Resolver) fill(o *watchdogv1alpha1.Ownership, meta *metav1.ObjectMeta) {
+	for _, f := range []struct {
+		field *string
+		keys  []string
+	}{
+		{&o.Team, r.Keys.Team},
+		{&o.Contact, r.Keys.Contact},
+		{&o.OnCall, r.Keys.OnCall},
+	} {
+		if *f.field == "" {
+			*f.field = lookup(meta, f.keys)
+		}
+	}
+}
+
+func lookup(meta *metav1.ObjectMeta, keys []string) string {
+	for _, k := range keys {
+		if v := meta.Annotations[k]; v != "" {
+			return v
+		}
+		if v := meta.Labels[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// complete reports whether every field of o that has keys is set.
+func (r *Resolver) complete(o *watchdogv1alpha1.Ownership) bool {
+	return (o.Team != "" || len(r.Keys.Team) == 0) &&
+		(o.Contact != "" || len(r.Keys.Contact) == 0) &&
+		(o.OnCall != "" || len(r.Keys.OnCall) == 0)
+}
+
+func ignorable(err error) bool {
+	return apierrors.IsNotFound(err) || apierrors.IsForbidden(err)
+}
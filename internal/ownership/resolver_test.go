@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ownership
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Resolver", func() {
+	var (
+		ctx        context.Context
+		namespace  *corev1.Namespace
+		deployment *appsv1.Deployment
+		replicaSet *appsv1.ReplicaSet
+		pod        *metav1.PartialObjectMetadata
+	)
+
+	controlledBy := func(apiVersion, kind, name string) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: name, UID: types.UID("uid-" + name),
+			Controller: ptr.To(true)}}
+	}
+
+	resolve := func(objs ...client.Object) *watchdogv1alpha1.Ownership {
+		cl := fake.NewClientBuilder().WithScheme(clientgoscheme.Scheme).WithObjects(objs...).Build()
+		r := &Resolver{Reader: cl, Keys: DefaultKeys()}
+		o, err := r.Resolve(ctx, pod)
+		Expect(err).NotTo(HaveOccurred())
+		return o
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop", Annotations: map[string]string{
+			"gokubedog.io/team":                         "platform",
+			watchdogv1alpha1.DefaultRecipientAnnotation: "shop-owners@example.com",
+		}}}
+		deployment = &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "shop",
+			Labels: map[string]string{"team": "payments"}}}
+		replicaSet = &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-5d8f", Namespace: "shop",
+			OwnerReferences: controlledBy("apps/v1", "Deployment", "web")}}
+		pod = &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "web-5d8f-x2", Namespace: "shop",
+			Annotations:     map[string]string{"gokubedog.io/oncall": "payments-primary"},
+			OwnerReferences: controlledBy("apps/v1", "ReplicaSet", "web-5d8f")}}
+	})
+
+	It("takes each field from the closest object setting it", func() {
+		Expect(resolve(namespace, deployment, replicaSet)).To(Equal(&watchdogv1alpha1.Ownership{
+			Team:    "payments",
+			Contact: "shop-owners@example.com",
+			OnCall:  "payments-primary",
+		}))
+	})
+
+	It("prefers earlier keys and annotations over labels", func() {
+		deployment.Labels["gokubedog.io/team"] = "labelled"
+		deployment.Annotations = map[string]string{"gokubedog.io/team": "annotated"}
+		Expect(resolve(namespace, deployment, replicaSet).Team).To(Equal("annotated"))
+	})
+
+	It("falls back to the namespace when controllers are gone or not followed", func() {
+		Expect(resolve(namespace).Team).To(Equal("platform"))
+
+		pod.OwnerReferences = controlledBy("argoproj.io/v1alpha1", "Rollout", "web")
+		Expect(resolve(namespace, deployment, replicaSet).Team).To(Equal("platform"))
+	})
+
+	It("returns nil when nothing is owned", func() {
+		pod.Annotations = nil
+		Expect(resolve(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}})).To(BeNil())
+	})
+})
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package packs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// retryInterval is the time between two installation attempts.
+const retryInterval = 30 * time.Second
+
+// Installer installs policy packs and upgrades the ones installed from an
+// older version. Objects of the same name that do not come from the pack are
+// left alone, and so are the changes made to installed objects until the
+// next upgrade.
+type Installer struct {
+	client.Client
+	// APIReader reads the installed objects bypassing the cache.
+	APIReader client.Reader
+	Packs     []Pack
+	// Namespace is where the profiles of the packs are created.
+	Namespace string
+}
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policytemplates,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles,verbs=get;list;watch;create;update
+
+// Start installs the packs, retrying until it succeeds or the context is
+// cancelled. It implements manager.Runnable.
+func (in *Installer) Start(ctx context.Context) error {
+	l := logf.FromContext(ctx).WithName("packs")
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		err := in.Install(ctx)
+		if err == nil {
+			return nil
+		}
+		l.Error(err, "policy pack installation failed, retrying", "after", retryInterval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only the leading manager installs packs.
+func (in *Installer) NeedLeaderElection() bool {
+	return true
+}
+
+// Install creates or upgrades the objects of every pack.
+func (in *Installer) Install(ctx context.Context) error {
+	var errs []error
+	for _, pack := range in.Packs {
+		if err := in.install(ctx, pack); err != nil {
+			errs = append(errs, fmt.Errorf("policy pack %s: %w", pack.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (in *Installer) install(ctx context.Context, pack Pack) error {
+	// The template first, so that the profiles resolve as soon as they exist
+	template := pack.Template()
+	if err := in.apply(ctx, pack, template, &v1alpha1.PolicyTemplate{}, func(current client.Object) {
+		current.(*v1alpha1.PolicyTemplate).Spec = template.Spec
+	}); err != nil {
+		return err
+	}
+	for _, profile := range pack.Profiles(in.Namespace) {
+		if err := in.apply(ctx, pack, profile, &v1alpha1.PolicyProfile{}, func(current client.Object) {
+			current.(*v1alpha1.PolicyProfile).Spec = profile.Spec
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply creates desired, or upgrades current to it with upgrade if it was
+// installed from an older version of pack.
+func (in *Installer) apply(
+	ctx context.Context, pack Pack, desired, current client.Object, upgrade func(current client.Object),
+) error {
+	l := logf.FromContext(ctx).WithValues("pack", pack.Name, "name", desired.GetName())
+	kind := desired.GetObjectKind().GroupVersionKind().Kind
+	err := in.APIReader.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if apierrors.IsNotFound(err) {
+		if err := in.Create(ctx, desired); err != nil {
+			return fmt.Errorf("failed creating %s %s: %w", kind, desired.GetName(), err)
+		}
+		l.Info("Installed policy pack object", "kind", kind, "version", pack.Version)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed fetching %s %s: %w", kind, desired.GetName(), err)
+	}
+
+	labels := current.GetLabels()
+	if labels[v1alpha1.PackLabel] != pack.Name {
+		l.Info("Skipping policy pack object, an object of the same name exists", "kind", kind)
+		return nil
+	}
+	installed := labels[v1alpha1.PackVersionLabel]
+	if !older(installed, pack.Version) {
+		return nil
+	}
+	upgrade(current)
+	labels[v1alpha1.PackVersionLabel] = pack.Version
+	current.SetLabels(labels)
+	if err := in.Update(ctx, current); err != nil {
+		return fmt.Errorf("failed upgrading %s %s: %w", kind, desired.GetName(), err)
+	}
+	l.Info("Upgraded policy pack object", "kind", kind, "from", installed, "version", pack.Version)
+	return nil
+}
+
+// older reports whether the installed version precedes the pack version.
+// Unparsable versions are upgraded.
+func older(installed, pack string) bool {
+	v, err := version.ParseSemantic(installed)
+	if err != nil {
+		return true
+	}
+	return v.LessThan(version.MustParseSemantic(pack))
+}
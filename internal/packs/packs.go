@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package packs ships built-in policy packs: versioned PolicyTemplates
+// running the built-in checks, along with the PolicyProfiles applying them
+// cluster-wide. The manager installs the packs selected with --policy-packs.
+package packs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// workloads are the kinds holding the pod templates the pod checks inspect.
+// Pods are left out: most are created from these templates and would be
+// reported twice.
+var workloads = []string{"Deployment", "StatefulSet", "DaemonSet", "CronJob"}
+
+// Pack is a built-in policy pack.
+type Pack struct {
+	// Name of the pack, also the name of its PolicyTemplate.
+	Name string
+	// Version is bumped whenever the objects of the pack change, so that
+	// installed packs get upgraded.
+	Version     string
+	Description string
+	Checks      []v1alpha1.Check
+	Severity    v1alpha1.Severity
+	Remediation string
+	// Kinds are the kinds a profile of the pack is installed for.
+	Kinds []string
+}
+
+var all = []Pack{
+	{
+		Name:        "pod-security-baseline",
+		Version:     "1.0.0",
+		Description: "Pod Security Standards baseline profile: prevents known privilege escalations.",
+		Checks:      []v1alpha1.Check{v1alpha1.CheckPodSecurityBaseline},
+		Severity:    v1alpha1.SeverityHigh,
+		Remediation: "Remove privileged mode, host namespaces, host ports, hostPath volumes and added capabilities " +
+			"outside the default set from the pod template. " +
+			"See https://kubernetes.io/docs/concepts/security/pod-security-standards/#baseline.",
+		Kinds: workloads,
+	},
+	{
+		Name:        "pod-security-restricted",
+		Version:     "1.0.0",
+		Description: "Pod Security Standards restricted profile: enforces current pod hardening best practices.",
+		Checks:      []v1alpha1.Check{v1alpha1.CheckPodSecurityRestricted},
+		Severity:    v1alpha1.SeverityMedium,
+		Remediation: "On top of the baseline requirements, set runAsNonRoot: true, allowPrivilegeEscalation: false, " +
+			"capabilities.drop: [ALL] and seccompProfile.type: RuntimeDefault. " +
+			"See https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted.",
+		Kinds: workloads,
+	},
+	{
+		Name:        "network-default-deny",
+		Version:     "1.0.0",
+		Description: "Every namespace denies ingress to its pods unless a NetworkPolicy allows it.",
+		Checks:      []v1alpha1.Check{v1alpha1.CheckDefaultDenyIngress},
+		Severity:    v1alpha1.SeverityHigh,
+		Remediation: "Add a NetworkPolicy with an empty podSelector and policyTypes: [Ingress] to the namespace, " +
+			"then allow the traffic each workload needs with narrower NetworkPolicies.",
+		Kinds: []string{"Namespace"},
+	},
+	{
+		Name:        "image-tag-not-latest",
+		Version:     "1.0.0",
+		Description: "Container images are pinned to a tag other than latest, or to a digest.",
+		Checks:      []v1alpha1.Check{v1alpha1.CheckImageTagNotLatest},
+		Severity:    v1alpha1.SeverityMedium,
+		Remediation: "Reference container images by an immutable version tag or by digest, never by latest.",
+		Kinds:       workloads,
+	},
+	{
+		Name:        "resource-limits",
+		Version:     "1.0.0",
+		Description: "Containers set CPU and memory limits.",
+		Checks:      []v1alpha1.Check{v1alpha1.CheckResourceLimits},
+		Severity:    v1alpha1.SeverityMedium,
+		Remediation: "Set resources.limits.cpu and resources.limits.memory on every container and init container.",
+		Kinds:       workloads,
+	},
+	{
+		Name:        "no-host-path",
+		Version:     "1.0.0",
+		Description: "Pods do not mount directories of the node.",
+		Checks:      []v1alpha1.Check{v1alpha1.CheckNoHostPath},
+		Severity:    v1alpha1.SeverityHigh,
+		Remediation: "Replace hostPath volumes with persistent volumes, or with emptyDir for scratch space.",
+		Kinds:       workloads,
+	},
+}
+
+// All returns the built-in packs sorted by name.
+func All() []Pack {
+	packs := slices.Clone(all)
+	sort.Slice(packs, func(i, j int) bool { return packs[i].Name < packs[j].Name })
+	return packs
+}
+
+// Lookup returns the packs named by names, in order. It fails on unknown
+// names.
+func Lookup(names []string) ([]Pack, error) {
+	var packs []Pack
+	for _, name := range names {
+		i := slices.IndexFunc(all, func(p Pack) bool { return p.Name == name })
+		if i < 0 {
+			known := make([]string, 0, len(all))
+			for _, p := range All() {
+				known = append(known, p.Name)
+			}
+			return nil, fmt.Errorf("unknown policy pack %q, expected one of %s", name, strings.Join(known, ", "))
+		}
+		packs = append(packs, all[i])
+	}
+	return packs, nil
+}
+
+// Template returns the cluster-scoped PolicyTemplate of the pack.
+func (p Pack) Template() *v1alpha1.PolicyTemplate {
+	return &v1alpha1.PolicyTemplate{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "PolicyTemplate"},
+		ObjectMeta: metav1.ObjectMeta{Name: p.Name, Labels: p.labels()},
+		Spec: v1alpha1.PolicyTemplateSpec{
+			Description: p.Description,
+			Checks:      slices.Clone(p.Checks),
+			Remediation: p.Remediation,
+		},
+	}
+}
+
+// Profiles returns the PolicyProfiles of the pack, one per kind, created in
+// namespace and matching the resources of every namespace.
+func (p Pack) Profiles(namespace string) []*v1alpha1.PolicyProfile {
+	profiles := make([]*v1alpha1.PolicyProfile, 0, len(p.Kinds))
+	for _, kind := range p.Kinds {
+		profiles = append(profiles, &v1alpha1.PolicyProfile{
+			TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "PolicyProfile"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      p.Name + "-" + strings.ToLower(kind),
+				Namespace: namespace,
+				Labels:    p.labels(),
+			},
+			Spec: v1alpha1.PolicyProfileSpec{
+				Match:    v1alpha1.MatchSpec{Kind: kind, Namespace: "*"},
+				Template: &v1alpha1.TemplateReference{Name: p.Name},
+				Severity: p.Severity,
+			},
+		})
+	}
+	return profiles
+}
+
+func (p Pack) labels() map[string]string {
+	return map[string]string{
+		v1alpha1.PackLabel:        p.Name,
+		v1alpha1.PackVersionLabel: p.Version,
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package packs
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+var _ = Describe("Packs", func() {
+	It("are versioned and resolve to their checks, severity and remediation", func() {
+		for _, pack := range All() {
+			Expect(older("0.0.1", pack.Version)).To(BeTrue(), pack.Name)
+			template := pack.Template()
+			Expect(template.Labels).To(HaveKeyWithValue(v1alpha1.PackVersionLabel, pack.Version))
+			Expect(pack.Profiles("gokubedog-system")).To(HaveLen(len(pack.Kinds)))
+			for _, profile := range pack.Profiles("gokubedog-system") {
+				resolved, err := policy.Resolve(profile, template)
+				Expect(err).NotTo(HaveOccurred(), profile.Name)
+				Expect(resolved.Spec.Checks).To(Equal(pack.Checks))
+				Expect(resolved.Spec.Remediation).NotTo(BeEmpty())
+				Expect(resolved.Spec.Severity).NotTo(BeEmpty())
+				Expect(resolved.Spec.Match.Namespace).To(Equal("*"))
+			}
+		}
+	})
+
+	It("are looked up by name", func() {
+		packs, err := Lookup([]string{"no-host-path", "pod-security-baseline"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packs).To(HaveLen(2))
+		Expect(packs[0].Name).To(Equal("no-host-path"))
+
+		_, err = Lookup([]string{"pod-security"})
+		Expect(err).To(MatchError(ContainSubstring(`unknown policy pack "pod-security", expected one of image-tag-not-latest,`)))
+	})
+})
+
+var _ = Describe("Installer", func() {
+	ctx := context.Background()
+	var (
+		cl        client.Client
+		installer *Installer
+		pack      Pack
+	)
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).Build()
+		packs, err := Lookup([]string{"network-default-deny"})
+		Expect(err).NotTo(HaveOccurred())
+		pack = packs[0]
+		installer = &Installer{Client: cl, APIReader: cl, Packs: packs, Namespace: "gokubedog-system"}
+	})
+
+	profileKey := client.ObjectKey{Namespace: "gokubedog-system", Name: "network-default-deny-namespace"}
+
+	It("creates the template and the profiles of the packs", func() {
+		Expect(installer.Install(ctx)).To(Succeed())
+
+		template := &v1alpha1.PolicyTemplate{}
+		Expect(cl.Get(ctx, client.ObjectKey{Name: "network-default-deny"}, template)).To(Succeed())
+		Expect(template.Spec.Checks).To(Equal([]v1alpha1.Check{v1alpha1.CheckDefaultDenyIngress}))
+		Expect(template.Labels).To(HaveKeyWithValue(v1alpha1.PackLabel, "network-default-deny"))
+
+		profile := &v1alpha1.PolicyProfile{}
+		Expect(cl.Get(ctx, profileKey, profile)).To(Succeed())
+		Expect(profile.Spec.Match).To(Equal(v1alpha1.MatchSpec{Kind: "Namespace", Namespace: "*"}))
+		Expect(profile.Spec.Template.Name).To(Equal("network-default-deny"))
+		Expect(profile.Spec.Severity).To(Equal(v1alpha1.SeverityHigh))
+	})
+
+	It("keeps the changes to installed packs until they are upgraded", func() {
+		Expect(installer.Install(ctx)).To(Succeed())
+		profile := &v1alpha1.PolicyProfile{}
+		Expect(cl.Get(ctx, profileKey, profile)).To(Succeed())
+		profile.Spec.Mode = v1alpha1.ProfileModePreview
+		Expect(cl.Update(ctx, profile)).To(Succeed())
+
+		Expect(installer.Install(ctx)).To(Succeed())
+		Expect(cl.Get(ctx, profileKey, profile)).To(Succeed())
+		Expect(profile.Spec.Mode).To(Equal(v1alpha1.ProfileModePreview))
+
+		installer.Packs[0].Version = "1.1.0"
+		Expect(installer.Install(ctx)).To(Succeed())
+		Expect(cl.Get(ctx, profileKey, profile)).To(Succeed())
+		Expect(profile.Spec.Mode).To(BeEmpty())
+		Expect(profile.Labels).To(HaveKeyWithValue(v1alpha1.PackVersionLabel, "1.1.0"))
+		Expect(pack.Version).To(Equal("1.0.0"), "the built-in pack is left untouched")
+	})
+
+	It("leaves objects that do not come from the pack alone", func() {
+		own := &v1alpha1.PolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "network-default-deny"},
+			Spec:       v1alpha1.PolicyTemplateSpec{Description: "ours"},
+		}
+		Expect(cl.Create(ctx, own)).To(Succeed())
+
+		Expect(installer.Install(ctx)).To(Succeed())
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(own), own)).To(Succeed())
+		Expect(own.Spec.Description).To(Equal("ours"))
+	})
+})
@@ -0,0 +1,197 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+func ack(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("ack", stderr)
+	reason := fs.String("reason", "", "Why the violation is acknowledged, e.g. \"fixing next sprint\".")
+	snooze := fs.Duration("snooze", 0, "Silence the violation for this long, even if it reopens meanwhile.")
+	by := fs.String("by", "", "Who acknowledges the violation, the authenticated user if unset.")
+	clearAck := fs.Bool("clear", false, "Remove the acknowledgement instead.")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(stderr, "Usage: kubectl gokubedog ack <report> [flags]")
+		return 2
+	}
+	if *snooze < 0 {
+		_, _ = fmt.Fprintln(stderr, "kubectl gokubedog ack: -snooze must not be negative")
+		return 2
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "ack", err)
+	}
+	ctx := context.Background()
+	rep, err := s.report(ctx, positional[0])
+	if err != nil {
+		return fail(stderr, "ack", err)
+	}
+
+	patch := client.MergeFrom(rep.DeepCopy())
+	if *clearAck {
+		rep.Spec.Acknowledgement = nil
+	} else {
+		now := s.now()
+		rep.Spec.Acknowledgement = &v1alpha1.Acknowledgement{
+			By:     *by,
+			At:     metav1.NewTime(now),
+			Reason: *reason,
+		}
+		if rep.Spec.Acknowledgement.By == "" {
+			rep.Spec.Acknowledgement.By = s.whoami(ctx)
+		}
+		if *snooze > 0 {
+			until := metav1.NewTime(now.Add(*snooze))
+			rep.Spec.Acknowledgement.SnoozeUntil = &until
+		}
+	}
+	if err := s.Patch(ctx, rep, patch); err != nil {
+		return fail(stderr, "ack", err)
+	}
+
+	switch {
+	case *clearAck:
+		_, _ = fmt.Fprintf(stdout, "policyviolationreport/%s unacknowledged\n", rep.Name)
+	case *snooze > 0:
+		_, _ = fmt.Fprintf(stdout, "policyviolationreport/%s snoozed until %s\n",
+			rep.Name, rep.Spec.Acknowledgement.SnoozeUntil.UTC().Format(time.RFC3339))
+	default:
+		_, _ = fmt.Fprintf(stdout, "policyviolationreport/%s acknowledged\n", rep.Name)
+	}
+	return 0
+}
+
+func except(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("except", stderr)
+	reason := fs.String("reason", "", "Why the drift is accepted. Required.")
+	expires := fs.Duration("expires", 0, "End the exception after this long, never if unset.")
+	by := fs.String("by", "", "Who requests the exception, the authenticated user if unset.")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(stderr, "Usage: kubectl gokubedog except <report> -reason <reason> [flags]")
+		return 2
+	}
+	if *reason == "" {
+		_, _ = fmt.Fprintln(stderr, "kubectl gokubedog except: -reason is required")
+		return 2
+	}
+	if *expires < 0 {
+		_, _ = fmt.Fprintln(stderr, "kubectl gokubedog except: -expires must not be negative")
+		return 2
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "except", err)
+	}
+	ctx := context.Background()
+	rep, err := s.report(ctx, positional[0])
+	if err != nil {
+		return fail(stderr, "except", err)
+	}
+
+	res := rep.Spec.ViolatedResource
+	exc := &v1alpha1.PolicyException{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: rep.Spec.ProfileName + "-",
+			Namespace:    res.Namespace,
+		},
+		Spec: v1alpha1.PolicyExceptionSpec{
+			ProfileName:      rep.Spec.ProfileName,
+			ProfileNamespace: rep.Spec.ProfileNamespace,
+			Resource:         v1alpha1.ExceptionResourceSpec{Kind: res.Kind, Name: res.Name},
+			Reason:           *reason,
+			CreatedBy:        *by,
+		},
+	}
+	if exc.Spec.CreatedBy == "" {
+		exc.Spec.CreatedBy = s.whoami(ctx)
+	}
+	if *expires > 0 {
+		at := metav1.NewTime(s.now().Add(*expires))
+		exc.Spec.ExpiresAt = &at
+	}
+	if err := s.Create(ctx, exc); err != nil {
+		return fail(stderr, "except", err)
+	}
+	_, _ = fmt.Fprintf(stdout, "policyexception/%s created\n", exc.Name)
+	return 0
+}
+
+func evaluate(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("evaluate", stderr)
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(stderr, "Usage: kubectl gokubedog evaluate <profile> [flags]")
+		return 2
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "evaluate", err)
+	}
+	ctx := context.Background()
+	profile := &v1alpha1.PolicyProfile{}
+	if err := s.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: positional[0]}, profile); err != nil {
+		return fail(stderr, "evaluate", err)
+	}
+	patch := client.MergeFrom(profile.DeepCopy())
+	if profile.Annotations == nil {
+		profile.Annotations = map[string]string{}
+	}
+	profile.Annotations[v1alpha1.EvaluateNowAnnotation] = s.now().UTC().Format(time.RFC3339)
+	if err := s.Patch(ctx, profile, patch); err != nil {
+		return fail(stderr, "evaluate", err)
+	}
+	_, _ = fmt.Fprintf(stdout, "policyprofile/%s evaluation requested\n", profile.Name)
+	return 0
+}
+
+// whoami returns the user authenticated by the cluster, falling back to the
+// local user name when the cluster cannot tell.
+func (s *session) whoami(ctx context.Context) string {
+	review := &authenticationv1.SelfSubjectReview{}
+	if err := s.Create(ctx, review); err == nil && review.Status.UserInfo.Username != "" {
+		return review.Status.UserInfo.Username
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "kubectl"
+}
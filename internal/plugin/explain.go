@@ -0,0 +1,269 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+func explain(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("explain", stderr)
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(stderr, "Usage: kubectl gokubedog explain <kind>/<name> [flags]")
+		return 2
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "explain", err)
+	}
+	ctx := context.Background()
+	obj, err := s.resource(ctx, s.namespace, positional[0])
+	if err != nil {
+		return fail(stderr, "explain", err)
+	}
+	profiles, err := s.profilesFor(ctx, obj)
+	if err != nil {
+		return fail(stderr, "explain", err)
+	}
+	var exceptions v1alpha1.PolicyExceptionList
+	if err := s.List(ctx, &exceptions, client.InNamespace(obj.Namespace)); err != nil {
+		return fail(stderr, "explain", fmt.Errorf("failed listing PolicyExceptions: %w", err))
+	}
+
+	_, _ = fmt.Fprintf(stdout, "%s %s/%s\n", obj.Kind, obj.Namespace, obj.Name)
+	if len(profiles) == 0 {
+		_, _ = fmt.Fprintln(stdout, "\nNo PolicyProfile evaluates this resource.")
+		return 0
+	}
+	now := metav1.NewTime(s.now())
+	var whole client.Object
+	for i := range profiles {
+		p := &profiles[i]
+		severity := p.Spec.Severity
+		if severity == "" {
+			severity = v1alpha1.SeverityMedium
+		}
+		_, _ = fmt.Fprintf(stdout, "\nProfile %s/%s (%s): ", p.Namespace, p.Name, severity)
+		resolved, err := policy.ResolveTemplate(ctx, s, p)
+		if err != nil {
+			_, _ = fmt.Fprintf(stdout, "template does not resolve: %v\n", err)
+			continue
+		}
+		target := client.Object(obj)
+		if len(resolved.Spec.Checks) > 0 {
+			if whole == nil {
+				if whole, err = s.whole(ctx, obj); err != nil {
+					return fail(stderr, "explain", err)
+				}
+			}
+			target = whole
+		}
+		outcome, err := policy.Evaluate(ctx, s, resolved, target, exceptions.Items, now)
+		if err != nil {
+			_, _ = fmt.Fprintf(stdout, "evaluation failed: %v\n", err)
+			continue
+		}
+		drift := outcome.Drift
+		if len(drift) == 0 {
+			_, _ = fmt.Fprintln(stdout, "passing")
+			continue
+		}
+		if exc := outcome.Exception; exc != nil {
+			_, _ = fmt.Fprintf(stdout, "excepted by %s: %s", exc.Name, exc.Spec.Reason)
+			if exc.Spec.ExpiresAt != nil {
+				_, _ = fmt.Fprintf(stdout, " (expires %s)", exc.Spec.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"))
+			}
+			_, _ = fmt.Fprintln(stdout)
+			continue
+		}
+		_, _ = fmt.Fprintln(stdout, "failing")
+		for _, key := range sortedKeys(drift) {
+			_, _ = fmt.Fprintf(stdout, "  %s: %s\n", key, drift[key])
+		}
+		if resolved.Spec.Remediation != "" {
+			_, _ = fmt.Fprintf(stdout, "  Remediation: %s\n", resolved.Spec.Remediation)
+		}
+		rep, err := s.reportFor(ctx, p, obj)
+		if err != nil {
+			return fail(stderr, "explain", err)
+		}
+		if rep == nil {
+			_, _ = fmt.Fprintln(stdout, "  Report: none yet")
+			continue
+		}
+		_, _ = fmt.Fprintf(stdout, "  Report: %s\n", rep.Name)
+		if o := rep.Spec.Ownership; o != nil {
+			_, _ = fmt.Fprintf(stdout, "  Owner: %s\n", describeOwner(o))
+		}
+		if ack := rep.Spec.Acknowledgement; ack.Active(s.now()) {
+			_, _ = fmt.Fprintf(stdout, "  Acknowledged by %s", ack.By)
+			if ack.SnoozeUntil != nil {
+				_, _ = fmt.Fprintf(stdout, " until %s", ack.SnoozeUntil.UTC().Format("2006-01-02T15:04:05Z"))
+			}
+			if ack.Reason != "" {
+				_, _ = fmt.Fprintf(stdout, ": %s", ack.Reason)
+			}
+			_, _ = fmt.Fprintln(stdout)
+		}
+	}
+	return 0
+}
+
+func diff(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("diff", stderr)
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		_, _ = fmt.Fprintln(stderr, "Usage: kubectl gokubedog diff <kind>/<name>|<report> [flags]")
+		return 2
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "diff", err)
+	}
+	ctx := context.Background()
+
+	var obj *metav1.PartialObjectMetadata
+	var profiles []v1alpha1.PolicyProfile
+	if strings.Contains(positional[0], "/") && !strings.HasPrefix(positional[0], "policyviolationreport/") {
+		if obj, err = s.resource(ctx, s.namespace, positional[0]); err != nil {
+			return fail(stderr, "diff", err)
+		}
+		if profiles, err = s.profilesFor(ctx, obj); err != nil {
+			return fail(stderr, "diff", err)
+		}
+	} else {
+		rep, err := s.report(ctx, positional[0])
+		if err != nil {
+			return fail(stderr, "diff", err)
+		}
+		vr := rep.Spec.ViolatedResource
+		if obj, err = s.resource(ctx, vr.Namespace, vr.Kind+"/"+vr.Name); err != nil {
+			return fail(stderr, "diff", err)
+		}
+		profile := v1alpha1.PolicyProfile{}
+		key := types.NamespacedName{Namespace: rep.Spec.ProfileNamespace, Name: rep.Spec.ProfileName}
+		if err := s.Get(ctx, key, &profile); err != nil {
+			return fail(stderr, "diff", err)
+		}
+		profiles = []v1alpha1.PolicyProfile{profile}
+	}
+
+	differs := false
+	for i := range profiles {
+		p, err := policy.ResolveTemplate(ctx, s, &profiles[i])
+		if err != nil {
+			return fail(stderr, "diff", err)
+		}
+		if len(p.Drift(obj.Labels)) == 0 {
+			continue
+		}
+		differs = true
+		_, _ = fmt.Fprintf(stdout, "--- PolicyProfile %s/%s (desired)\n", p.Namespace, p.Name)
+		_, _ = fmt.Fprintf(stdout, "+++ %s %s/%s (actual)\n", obj.Kind, obj.Namespace, obj.Name)
+		desired := maps.Clone(p.Spec.Policy)
+		if desired == nil {
+			desired = map[string]string{}
+		}
+		for _, key := range p.Spec.RequiredLabels {
+			if _, ok := desired[key]; !ok {
+				desired[key] = "<" + v1alpha1.AnyValue + ">"
+			}
+		}
+		for _, key := range sortedKeys(desired) {
+			actual, ok := obj.Labels[key]
+			_, exact := p.Spec.Policy[key]
+			if ok && (actual == desired[key] || !exact) {
+				_, _ = fmt.Fprintf(stdout, " %s: %s\n", key, actual)
+				continue
+			}
+			_, _ = fmt.Fprintf(stdout, "-%s: %s\n", key, desired[key])
+			if ok {
+				_, _ = fmt.Fprintf(stdout, "+%s: %s\n", key, actual)
+			}
+		}
+	}
+	if differs {
+		return 1
+	}
+	return 0
+}
+
+// reportFor returns the report of the profile about obj, nil if there is
+// none.
+func (s *session) reportFor(
+	ctx context.Context, p *v1alpha1.PolicyProfile, obj *metav1.PartialObjectMetadata,
+) (*v1alpha1.PolicyViolationReport, error) {
+	var list v1alpha1.PolicyViolationReportList
+	namespace := policy.HomeNamespace(p.Spec.Match.Kind, obj)
+	if err := s.List(ctx, &list, client.InNamespace(namespace), client.MatchingLabels{
+		v1alpha1.ProfileNameLabel:      p.Name,
+		v1alpha1.ProfileNamespaceLabel: p.Namespace,
+	}); err != nil {
+		return nil, fmt.Errorf("failed listing PolicyViolationReports: %w", err)
+	}
+	for i := range list.Items {
+		if list.Items[i].Spec.ViolatedResource.Name == obj.Name {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func describeOwner(o *v1alpha1.Ownership) string {
+	var parts []string
+	if o.Team != "" {
+		parts = append(parts, "team "+o.Team)
+	}
+	if o.Contact != "" {
+		parts = append(parts, "contact "+o.Contact)
+	}
+	if o.OnCall != "" {
+		parts = append(parts, "on-call "+o.OnCall)
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
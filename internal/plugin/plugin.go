@@ -0,0 +1,263 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin implements kubectl-gokubedog, the kubectl plugin inspecting
+// and handling the violations of the cluster of the current kubeconfig
+// context.
+package plugin
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+// command is a plugin subcommand. It returns the process exit code.
+type command struct {
+	summary string
+	run     func(args []string, stdout, stderr io.Writer, connect connector) int
+}
+
+var commands = map[string]command{
+	"violations": {"List violations by namespace and severity", violations},
+	"explain":    {"Show which profiles apply to a resource and why it fails them", explain},
+	"diff":       {"Show the desired and actual values of a violating resource", diff},
+	"ack":        {"Acknowledge or snooze a violation", ack},
+	"except":     {"Create a PolicyException for a violation", except},
+	"evaluate":   {"Request an immediate evaluation of a profile", evaluate},
+	"what-if":    {"Show what a profile would report before applying it", whatIf},
+}
+
+// Main runs the subcommand named by args[0] against the cluster of the
+// kubeconfig and returns the exit code.
+func Main(args []string, stdout, stderr io.Writer) int {
+	return run(args, stdout, stderr, connectCluster)
+}
+
+func run(args []string, stdout, stderr io.Writer, connect connector) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "kubectl gokubedog: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	return cmd.run(args[1:], stdout, stderr, connect)
+}
+
+func usage(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Usage: kubectl gokubedog <command> [flags]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+}
+
+// connection holds the flags selecting the cluster and namespace.
+type connection struct {
+	kubeconfig string
+	context    string
+	namespace  string
+}
+
+// newFlagSet returns a flag set reporting errors to stderr, with the
+// connection flags every command accepts.
+func newFlagSet(name string, stderr io.Writer) (*flag.FlagSet, *connection) {
+	fs := flag.NewFlagSet("kubectl gokubedog "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	c := &connection{}
+	fs.StringVar(&c.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file.")
+	fs.StringVar(&c.context, "context", "", "The kubeconfig context to use.")
+	fs.StringVar(&c.namespace, "namespace", "", "The namespace to use, the one of the context if unset.")
+	fs.StringVar(&c.namespace, "n", "", "Shorthand for -namespace.")
+	return fs, c
+}
+
+// parse parses args, allowing flags after positional arguments as kubectl
+// does, and returns the positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// session is a connection to a cluster.
+type session struct {
+	client.Client
+	mapper meta.RESTMapper
+	// namespace is the namespace commands work in.
+	namespace string
+	now       func() time.Time
+}
+
+// connector opens a session for the connection flags.
+type connector func(c *connection) (*session, error)
+
+func connectCluster(c *connection) (*session, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = c.kubeconfig
+	cc := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: c.context})
+	cfg, err := cc.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	namespace := c.namespace
+	if namespace == "" {
+		if namespace, _, err = cc.Namespace(); err != nil {
+			return nil, err
+		}
+	}
+
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cached := memory.NewMemCacheClient(dc)
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(cached), cached, nil)
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	cl, err := client.New(cfg, client.Options{Scheme: scheme, Mapper: mapper})
+	if err != nil {
+		return nil, err
+	}
+	return &session{Client: cl, mapper: mapper, namespace: namespace, now: time.Now}, nil
+}
+
+// resource fetches the metadata of the resource of namespace named by ref,
+// given as kind/name as in kubectl, e.g. netpol/allow-web or
+// deployments.apps/web.
+func (s *session) resource(ctx context.Context, namespace, ref string) (*metav1.PartialObjectMetadata, error) {
+	kind, name, ok := strings.Cut(ref, "/")
+	if !ok || kind == "" || name == "" {
+		return nil, fmt.Errorf("invalid resource %q, expected <kind>/<name>", ref)
+	}
+	gvk, err := s.mapper.KindFor(schema.ParseGroupResource(kind).WithVersion(""))
+	if err != nil {
+		return nil, fmt.Errorf("unknown kind %q: %w", kind, err)
+	}
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// whole fetches the whole resource obj is the metadata of, for the checks of
+// the profiles.
+func (s *session) whole(ctx context.Context, obj *metav1.PartialObjectMetadata) (*unstructured.Unstructured, error) {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(obj.GroupVersionKind())
+	if err := s.Get(ctx, client.ObjectKeyFromObject(obj), u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// report fetches the PolicyViolationReport named name.
+func (s *session) report(ctx context.Context, name string) (*v1alpha1.PolicyViolationReport, error) {
+	name = strings.TrimPrefix(name, "policyviolationreport/")
+	rep := &v1alpha1.PolicyViolationReport{}
+	if err := s.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, rep); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// profilesFor returns the profiles evaluating obj, sorted by namespace and
+// name.
+func (s *session) profilesFor(ctx context.Context, obj *metav1.PartialObjectMetadata) ([]v1alpha1.PolicyProfile, error) {
+	var list v1alpha1.PolicyProfileList
+	if err := s.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed listing PolicyProfiles: %w", err)
+	}
+	gvk := obj.GroupVersionKind()
+	var profiles []v1alpha1.PolicyProfile
+	for _, p := range list.Items {
+		if !policy.MatchesKind(&p, gvk) || !p.MatchesNamespace(policy.HomeNamespace(p.Spec.Match.Kind, obj)) {
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Namespace != profiles[j].Namespace {
+			return profiles[i].Namespace < profiles[j].Namespace
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles, nil
+}
+
+// fail reports err for the named command and returns the exit code.
+func fail(stderr io.Writer, name string, err error) int {
+	_, _ = fmt.Fprintf(stderr, "kubectl gokubedog %s: %v\n", name, err)
+	return 1
+}
+
+// severityRank orders severities from the least to the most serious.
+func severityRank(s v1alpha1.Severity) int {
+	switch s {
+	case v1alpha1.SeverityLow:
+		return 1
+	case v1alpha1.SeverityHigh:
+		return 3
+	case v1alpha1.SeverityCritical:
+		return 4
+	default:
+		return 2
+	}
+}
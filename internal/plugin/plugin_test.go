@@ -0,0 +1,327 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("kubectl gokubedog", func() {
+	var (
+		cl             client.Client
+		stdout, stderr *bytes.Buffer
+		now            = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	report := func(name, namespace, profile, resource string, severity v1alpha1.Severity) *v1alpha1.PolicyViolationReport {
+		return &v1alpha1.PolicyViolationReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					v1alpha1.ProfileNameLabel:      profile,
+					v1alpha1.ProfileNamespaceLabel: "default",
+				},
+				CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour)),
+			},
+			Spec: v1alpha1.PolicyViolationReportSpec{
+				ViolatedResource: v1alpha1.ViolatedResourceSpec{Kind: "NetworkPolicy", Name: resource, Namespace: namespace},
+				ProfileName:      profile,
+				ProfileNamespace: "default",
+				Drift:            map[string]string{"team": "Expected: payments, Got: "},
+				Severity:         severity,
+			},
+			Status: v1alpha1.PolicyViolationReportStatus{Phase: v1alpha1.ReportPhaseOpen},
+		}
+	}
+	profile := func(name, namespace string, severity v1alpha1.Severity, policy map[string]string) *v1alpha1.PolicyProfile {
+		return &v1alpha1.PolicyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: v1alpha1.PolicyProfileSpec{
+				Match:    v1alpha1.MatchSpec{Kind: "NetworkPolicy", Namespace: namespace},
+				Policy:   policy,
+				Severity: severity,
+			},
+		}
+	}
+	run := func(args ...string) int {
+		connect := func(c *connection) (*session, error) {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"), meta.RESTScopeNamespace)
+			mapper.Add(corev1.SchemeGroupVersion.WithKind("Pod"), meta.RESTScopeNamespace)
+			namespace := c.namespace
+			if namespace == "" {
+				namespace = "team-a"
+			}
+			return &session{Client: cl, mapper: mapper, namespace: namespace, now: func() time.Time { return now }}, nil
+		}
+		return run(args, stdout, stderr, connect)
+	}
+
+	BeforeEach(func() {
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+
+		resolved := report("violation-resolved", "team-a", "require-team", "old", v1alpha1.SeverityCritical)
+		resolved.Status.Phase = v1alpha1.ReportPhaseResolved
+		acked := report("violation-acked", "team-b", "require-team", "db", v1alpha1.SeverityLow)
+		acked.Spec.Ownership = &v1alpha1.Ownership{Team: "storage"}
+		acked.Spec.Acknowledgement = &v1alpha1.Acknowledgement{By: "alice", At: metav1.NewTime(now)}
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			report("violation-web", "team-a", "require-team", "web", v1alpha1.SeverityHigh),
+			report("violation-api", "team-a", "require-tier", "api", v1alpha1.SeverityCritical),
+			resolved, acked,
+			profile("require-team", "team-*", v1alpha1.SeverityHigh, map[string]string{"team": "payments"}),
+			profile("require-tier", "team-a", v1alpha1.SeverityCritical, map[string]string{"tier": "backend"}),
+			profile("require-app", "team-a", "", map[string]string{"app": "web"}),
+			profile("other-namespace", "team-c", "", map[string]string{"team": "payments"}),
+			&networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{
+				Name: "web", Namespace: "team-a", Labels: map[string]string{"app": "web", "team": "legacy"},
+			}},
+			&v1alpha1.PolicyException{
+				ObjectMeta: metav1.ObjectMeta{Name: "tier-exception", Namespace: "team-a"},
+				Spec: v1alpha1.PolicyExceptionSpec{
+					ProfileName:      "require-tier",
+					ProfileNamespace: "default",
+					Resource:         v1alpha1.ExceptionResourceSpec{Kind: "NetworkPolicy", Name: "*"},
+					Reason:           "tiers are not rolled out yet",
+				},
+			},
+		).Build()
+	})
+
+	It("rejects unknown commands", func() {
+		Expect(run("frobnicate")).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring(`unknown command "frobnicate"`))
+	})
+
+	Describe("violations", func() {
+		It("lists the open violations of the namespace by severity", func() {
+			Expect(run("violations")).To(Equal(0))
+			lines := splitLines(stdout.String())
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(HavePrefix("NAMESPACE"))
+			Expect(lines[1]).To(MatchRegexp(`^team-a\s+critical\s+require-tier\s+NetworkPolicy/api\s+Open\s+<none>\s+no\s+120m\s+violation-api$`))
+			Expect(lines[2]).To(MatchRegexp(`^team-a\s+high\s+require-team\s+NetworkPolicy/web\s`))
+		})
+
+		It("filters by severity, team and phase across namespaces", func() {
+			Expect(run("violations", "-A", "-severity", "critical", "-all")).To(Equal(0))
+			Expect(stdout.String()).To(ContainSubstring("violation-api"))
+			Expect(stdout.String()).To(ContainSubstring("violation-resolved"))
+			Expect(stdout.String()).NotTo(ContainSubstring("violation-web"))
+
+			stdout.Reset()
+			Expect(run("violations", "-A", "-team", "storage")).To(Equal(0))
+			lines := splitLines(stdout.String())
+			Expect(lines).To(HaveLen(2))
+			Expect(lines[1]).To(MatchRegexp(`^team-b\s+low\s+require-team\s+NetworkPolicy/db\s+Open\s+storage\s+yes\s`))
+		})
+
+		It("counts the violations per namespace and severity", func() {
+			Expect(run("violations", "-A", "-summary")).To(Equal(0))
+			lines := splitLines(stdout.String())
+			Expect(lines).To(HaveLen(3))
+			Expect(lines[0]).To(MatchRegexp(`^NAMESPACE\s+critical\s+high\s+medium\s+low\s+total$`))
+			Expect(lines[1]).To(MatchRegexp(`^team-a\s+1\s+1\s+0\s+0\s+2$`))
+			Expect(lines[2]).To(MatchRegexp(`^team-b\s+0\s+0\s+0\s+1\s+1$`))
+		})
+
+		It("reports when there is nothing to list", func() {
+			Expect(run("violations", "-n", "team-c")).To(Equal(0))
+			Expect(stdout.String()).To(BeEmpty())
+			Expect(stderr.String()).To(Equal("No violations found in team-c namespace.\n"))
+		})
+
+		It("rejects unknown severities", func() {
+			Expect(run("violations", "-severity", "urgent")).To(Equal(2))
+		})
+	})
+
+	It("explains why a resource fails its profiles", func() {
+		Expect(run("explain", "netpol/web")).To(Equal(1))
+		Expect(stderr.String()).To(ContainSubstring(`unknown kind "netpol"`))
+
+		Expect(run("explain", "networkpolicy/web")).To(Equal(0))
+		Expect(stdout.String()).To(Equal(`NetworkPolicy team-a/web
+
+Profile default/require-app (medium): passing
+
+Profile default/require-team (high): failing
+  team: Expected: payments, Got: legacy
+  Report: violation-web
+
+Profile default/require-tier (critical): excepted by tier-exception: tiers are not rolled out yet
+`))
+	})
+
+	It("runs the checks of the profiles on the whole resource", func() {
+		hostPath := &v1alpha1.PolicyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-host-path", Namespace: "default"},
+			Spec: v1alpha1.PolicyProfileSpec{
+				Match:       v1alpha1.MatchSpec{Kind: "Pod", Namespace: "*"},
+				Checks:      []v1alpha1.Check{v1alpha1.CheckNoHostPath},
+				Remediation: "Use a persistent volume instead.",
+			},
+		}
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "team-a"},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name:         "logs",
+				VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}},
+			}}},
+		}
+		Expect(cl.Create(context.Background(), hostPath)).To(Succeed())
+		Expect(cl.Create(context.Background(), pod)).To(Succeed())
+
+		Expect(run("explain", "pod/agent")).To(Equal(0), stderr.String())
+		Expect(stdout.String()).To(Equal(`Pod team-a/agent
+
+Profile default/no-host-path (medium): failing
+  NoHostPath: volume logs mounts host path /var/log
+  Remediation: Use a persistent volume instead.
+  Report: none yet
+`))
+	})
+
+	It("diffs the desired and actual labels of a resource", func() {
+		Expect(run("diff", "violation-web")).To(Equal(1))
+		Expect(stdout.String()).To(Equal(`--- PolicyProfile default/require-team (desired)
++++ NetworkPolicy team-a/web (actual)
+-team: payments
++team: legacy
+`))
+
+		stdout.Reset()
+		Expect(run("diff", "NetworkPolicy/web")).To(Equal(1))
+		Expect(stdout.String()).To(ContainSubstring("-tier: backend\n"))
+		Expect(stdout.String()).NotTo(ContainSubstring("require-app"))
+	})
+
+	It("acknowledges and snoozes a violation", func() {
+		Expect(run("ack", "violation-web", "-by", "bob", "-reason", "fixing next sprint", "-snooze", "24h")).To(Equal(0))
+		Expect(stdout.String()).To(Equal("policyviolationreport/violation-web snoozed until 2025-06-02T12:00:00Z\n"))
+
+		rep := &v1alpha1.PolicyViolationReport{}
+		Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "violation-web"}, rep)).To(Succeed())
+		Expect(rep.Spec.Acknowledgement).NotTo(BeNil())
+		Expect(rep.Spec.Acknowledgement.By).To(Equal("bob"))
+		Expect(rep.Spec.Acknowledgement.Reason).To(Equal("fixing next sprint"))
+		Expect(rep.Spec.Acknowledgement.SnoozeUntil.Time).To(BeTemporally("==", now.Add(24*time.Hour)))
+
+		Expect(run("ack", "violation-web", "-clear")).To(Equal(0))
+		Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "violation-web"}, rep)).To(Succeed())
+		Expect(rep.Spec.Acknowledgement).To(BeNil())
+	})
+
+	It("creates an exception for a violation", func() {
+		Expect(run("except", "violation-web")).To(Equal(2))
+		Expect(stderr.String()).To(ContainSubstring("-reason is required"))
+
+		Expect(run("except", "violation-web", "-reason", "legacy app", "-expires", "168h", "-by", "bob")).To(Equal(0))
+		var list v1alpha1.PolicyExceptionList
+		Expect(cl.List(context.Background(), &list, client.InNamespace("team-a"))).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+		var exc *v1alpha1.PolicyException
+		for i := range list.Items {
+			if list.Items[i].Name != "tier-exception" {
+				exc = &list.Items[i]
+			}
+		}
+		Expect(exc).NotTo(BeNil())
+		Expect(exc.Name).To(HavePrefix("require-team-"))
+		Expect(stdout.String()).To(Equal("policyexception/" + exc.Name + " created\n"))
+		Expect(exc.Profile()).To(Equal(types.NamespacedName{Namespace: "default", Name: "require-team"}))
+		Expect(exc.Spec.Resource).To(Equal(v1alpha1.ExceptionResourceSpec{Kind: "NetworkPolicy", Name: "web"}))
+		Expect(exc.Spec.Reason).To(Equal("legacy app"))
+		Expect(exc.Spec.CreatedBy).To(Equal("bob"))
+		Expect(exc.Spec.ExpiresAt.Time).To(BeTemporally("==", now.Add(168*time.Hour)))
+	})
+
+	It("previews what a candidate profile would report", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "profile.yaml")
+		Expect(os.WriteFile(path, []byte(`apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyProfile
+metadata:
+  name: require-tier
+spec:
+  match: {kind: NetworkPolicy, namespace: team-*}
+  policy: {app: api}
+`), 0o600)).To(Succeed())
+
+		Expect(run("what-if", "-f", path, "-n", "default")).To(Equal(0))
+		Expect(stdout.String()).To(Equal(`PolicyProfile default/require-tier matches 1 NetworkPolicy resources
+Would violate: 0
+Excepted: 1
+`))
+
+		stdout.Reset()
+		Expect(os.WriteFile(path, []byte(`apiVersion: watchdog.bizaikube.io/v1alpha1
+kind: PolicyProfile
+metadata:
+  name: require-app
+  namespace: default
+spec:
+  match: {kind: NetworkPolicy, namespace: team-a}
+  policy: {app: api, tier: backend}
+`), 0o600)).To(Succeed())
+		Expect(run("what-if", "-f", path)).To(Equal(0))
+		lines := splitLines(stdout.String())
+		Expect(lines[:3]).To(Equal([]string{
+			"PolicyProfile default/require-app matches 1 NetworkPolicy resources",
+			"Would violate: 1",
+			"Excepted: 0",
+		}))
+		Expect(lines[5]).To(MatchRegexp(`^team-a\s+web\s+app: Expected: api, Got: web; tier: Expected: backend, Got: $`))
+		existing := &v1alpha1.PolicyProfile{}
+		Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "require-app"}, existing)).To(Succeed())
+		Expect(existing.Spec.Policy).To(Equal(map[string]string{"app": "web"}), "the candidate is never applied")
+	})
+
+	It("requests the evaluation of a profile", func() {
+		Expect(run("evaluate", "require-team", "-n", "default")).To(Equal(0))
+		p := &v1alpha1.PolicyProfile{}
+		Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "require-team"}, p)).To(Succeed())
+		Expect(p.Annotations).To(HaveKey(v1alpha1.EvaluateNowAnnotation))
+	})
+})
+
+func splitLines(s string) []string {
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// severities lists the severities from the most to the least serious.
+var severities = []v1alpha1.Severity{
+	v1alpha1.SeverityCritical, v1alpha1.SeverityHigh, v1alpha1.SeverityMedium, v1alpha1.SeverityLow,
+}
+
+func violations(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("violations", stderr)
+	allNamespaces := fs.Bool("A", false, "List the violations of every namespace.")
+	minSeverity := fs.String("severity", "", "Only list violations at least this severe: low, medium, high or critical.")
+	team := fs.String("team", "", "Only list violations of resources owned by this team.")
+	all := fs.Bool("all", false, "Also list resolved violations.")
+	summary := fs.Bool("summary", false, "Count the violations per namespace and severity instead of listing them.")
+	if _, err := parse(fs, args); err != nil {
+		return 2
+	}
+	if *minSeverity != "" && !validSeverity(v1alpha1.Severity(*minSeverity)) {
+		_, _ = fmt.Fprintf(stderr, "kubectl gokubedog violations: invalid severity %q\n", *minSeverity)
+		return 2
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "violations", err)
+	}
+
+	var opts []client.ListOption
+	if !*allNamespaces {
+		opts = append(opts, client.InNamespace(s.namespace))
+	}
+	var list v1alpha1.PolicyViolationReportList
+	if err := s.List(context.Background(), &list, opts...); err != nil {
+		return fail(stderr, "violations", fmt.Errorf("failed listing PolicyViolationReports: %w", err))
+	}
+	var reports []v1alpha1.PolicyViolationReport
+	for _, rep := range list.Items {
+		if rep.Spec.Severity == "" {
+			rep.Spec.Severity = v1alpha1.SeverityMedium
+		}
+		if !*all && rep.Status.IsResolved() {
+			continue
+		}
+		if *minSeverity != "" && severityRank(rep.Spec.Severity) < severityRank(v1alpha1.Severity(*minSeverity)) {
+			continue
+		}
+		if *team != "" && (rep.Spec.Ownership == nil || rep.Spec.Ownership.Team != *team) {
+			continue
+		}
+		reports = append(reports, rep)
+	}
+	if len(reports) == 0 {
+		if *allNamespaces {
+			_, _ = fmt.Fprintln(stderr, "No violations found.")
+		} else {
+			_, _ = fmt.Fprintf(stderr, "No violations found in %s namespace.\n", s.namespace)
+		}
+		return 0
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		a, b := &reports[i], &reports[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if ra, rb := severityRank(a.Spec.Severity), severityRank(b.Spec.Severity); ra != rb {
+			return ra > rb
+		}
+		if a.Spec.ProfileName != b.Spec.ProfileName {
+			return a.Spec.ProfileName < b.Spec.ProfileName
+		}
+		return a.Spec.ViolatedResource.Name < b.Spec.ViolatedResource.Name
+	})
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 3, ' ', 0)
+	if *summary {
+		printSummary(w, reports)
+	} else {
+		printViolations(w, s, reports)
+	}
+	if err := w.Flush(); err != nil {
+		return fail(stderr, "violations", err)
+	}
+	return 0
+}
+
+func printViolations(w io.Writer, s *session, reports []v1alpha1.PolicyViolationReport) {
+	_, _ = fmt.Fprintln(w, "NAMESPACE\tSEVERITY\tPROFILE\tRESOURCE\tPHASE\tTEAM\tACK\tAGE\tNAME")
+	now := s.now()
+	for _, rep := range reports {
+		team := "<none>"
+		if rep.Spec.Ownership != nil && rep.Spec.Ownership.Team != "" {
+			team = rep.Spec.Ownership.Team
+		}
+		acked := "no"
+		if ack := rep.Spec.Acknowledgement; ack.Active(now) {
+			acked = "yes"
+			if ack.SnoozeUntil != nil {
+				acked = "snoozed " + duration.HumanDuration(ack.SnoozeUntil.Sub(now))
+			}
+		}
+		phase := rep.Status.Phase
+		if phase == "" {
+			phase = v1alpha1.ReportPhaseOpen
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\t%s\t%s\t%s\t%s\n",
+			rep.Namespace, rep.Spec.Severity, rep.Spec.ProfileName,
+			rep.Spec.ViolatedResource.Kind, rep.Spec.ViolatedResource.Name,
+			phase, team, acked, duration.HumanDuration(now.Sub(rep.Opened())), rep.Name)
+	}
+}
+
+func printSummary(w io.Writer, reports []v1alpha1.PolicyViolationReport) {
+	_, _ = fmt.Fprint(w, "NAMESPACE")
+	for _, sev := range severities {
+		_, _ = fmt.Fprintf(w, "\t%s", sev)
+	}
+	_, _ = fmt.Fprintln(w, "\ttotal")
+
+	for i := 0; i < len(reports); {
+		namespace := reports[i].Namespace
+		counts := map[v1alpha1.Severity]int{}
+		total := 0
+		for ; i < len(reports) && reports[i].Namespace == namespace; i++ {
+			counts[reports[i].Spec.Severity]++
+			total++
+		}
+		_, _ = fmt.Fprint(w, namespace)
+		for _, sev := range severities {
+			_, _ = fmt.Fprintf(w, "\t%d", counts[sev])
+		}
+		_, _ = fmt.Fprintf(w, "\t%d\n", total)
+	}
+}
+
+func validSeverity(s v1alpha1.Severity) bool {
+	for _, sev := range severities {
+		if s == sev {
+			return true
+		}
+	}
+	return false
+}
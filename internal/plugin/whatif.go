@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+// whatIf evaluates a candidate profile against the cluster without creating
+// it, listing the resources it would report.
+func whatIf(args []string, stdout, stderr io.Writer, connect connector) int {
+	fs, conn := newFlagSet("what-if", stderr)
+	file := fs.String("f", "", "The PolicyProfile manifest to evaluate, - for the standard input. Required.")
+	if _, err := parse(fs, args); err != nil {
+		return 2
+	}
+	if *file == "" {
+		_, _ = fmt.Fprintln(stderr, "kubectl gokubedog what-if: -f is required")
+		return 2
+	}
+	profile := &v1alpha1.PolicyProfile{}
+	if err := readProfile(*file, profile); err != nil {
+		return fail(stderr, "what-if", err)
+	}
+	s, err := connect(conn)
+	if err != nil {
+		return fail(stderr, "what-if", err)
+	}
+	if profile.Namespace == "" {
+		profile.Namespace = s.namespace
+	}
+	ctx := context.Background()
+
+	resolved, err := policy.ResolveTemplate(ctx, s, profile)
+	if err != nil {
+		return fail(stderr, "what-if", err)
+	}
+	gvk, err := s.mapper.KindFor(schema.ParseGroupResource(profile.Spec.Match.Kind).WithVersion(""))
+	if err != nil {
+		return fail(stderr, "what-if", fmt.Errorf("unknown kind %q: %w", profile.Spec.Match.Kind, err))
+	}
+	items, err := s.listTargets(ctx, resolved, gvk)
+	if err != nil {
+		return fail(stderr, "what-if", fmt.Errorf("failed listing %s: %w", gvk.Kind, err))
+	}
+	var exceptions v1alpha1.PolicyExceptionList
+	if err := s.List(ctx, &exceptions); err != nil {
+		return fail(stderr, "what-if", fmt.Errorf("failed listing PolicyExceptions: %w", err))
+	}
+
+	now := metav1.NewTime(s.now())
+	summary := v1alpha1.PreviewSummary{}
+	var violating []v1alpha1.PreviewSample
+	for _, item := range items {
+		outcome, err := policy.Evaluate(ctx, s, resolved, item, exceptions.Items, now)
+		if err != nil {
+			return fail(stderr, "what-if", err)
+		}
+		switch {
+		case !outcome.Matched:
+			continue
+		case outcome.Violates():
+			summary.WouldViolate++
+			violating = append(violating, v1alpha1.PreviewSample{
+				Namespace: policy.HomeNamespace(resolved.Spec.Match.Kind, item), Name: item.GetName(), Drift: outcome.Drift,
+			})
+		case outcome.Exception != nil:
+			summary.Excepted++
+		}
+		summary.Matched++
+	}
+
+	_, _ = fmt.Fprintf(stdout, "PolicyProfile %s/%s matches %d %s resources\n",
+		profile.Namespace, profile.Name, summary.Matched, gvk.Kind)
+	_, _ = fmt.Fprintf(stdout, "Would violate: %d\nExcepted: %d\n", summary.WouldViolate, summary.Excepted)
+	if len(violating) == 0 {
+		return 0
+	}
+	_, _ = fmt.Fprintln(stdout)
+	w := tabwriter.NewWriter(stdout, 0, 4, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAMESPACE\tNAME\tDRIFT")
+	for _, v := range violating {
+		drift := make([]string, 0, len(v.Drift))
+		for _, key := range sortedKeys(v.Drift) {
+			drift = append(drift, key+": "+v.Drift[key])
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", v.Namespace, v.Name, strings.Join(drift, "; "))
+	}
+	if err := w.Flush(); err != nil {
+		return fail(stderr, "what-if", err)
+	}
+	return 0
+}
+
+// listTargets lists the resources of kind gvk the profile may match, whole if
+// it runs checks and as metadata otherwise.
+func (s *session) listTargets(
+	ctx context.Context, profile *v1alpha1.PolicyProfile, gvk schema.GroupVersionKind,
+) ([]client.Object, error) {
+	var opts []client.ListOption
+	if !strings.HasSuffix(profile.Spec.Match.Namespace, "*") && gvk.Kind != "Namespace" {
+		opts = append(opts, client.InNamespace(profile.Spec.Match.Namespace))
+	}
+	listKind := gvk.GroupVersion().WithKind(gvk.Kind + "List")
+	var items []client.Object
+	if len(profile.Spec.Checks) > 0 {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(listKind)
+		if err := s.List(ctx, list, opts...); err != nil {
+			return nil, err
+		}
+		for i := range list.Items {
+			items = append(items, &list.Items[i])
+		}
+		return items, nil
+	}
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(listKind)
+	if err := s.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	for i := range list.Items {
+		items = append(items, &list.Items[i])
+	}
+	return items, nil
+}
+
+// readProfile decodes the PolicyProfile manifest at path, or on the standard
+// input if path is -.
+func readProfile(path string, profile *v1alpha1.PolicyProfile) error {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	if err := yaml.UnmarshalStrict(data, profile); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if profile.Spec.Match.Kind == "" {
+		return fmt.Errorf("%s: spec.match.kind is required", path)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// checkFunc returns the findings of a check on obj, none if it passes.
+type checkFunc func(ctx context.Context, r client.Reader, obj *unstructured.Unstructured) ([]string, error)
+
+var checks = map[v1alpha1.Check]checkFunc{
+	v1alpha1.CheckPodSecurityBaseline:   podCheck(podSecurityBaseline),
+	v1alpha1.CheckPodSecurityRestricted: podCheck(podSecurityRestricted),
+	v1alpha1.CheckImageTagNotLatest:     podCheck(imageTagNotLatest),
+	v1alpha1.CheckResourceLimits:        podCheck(resourceLimits),
+	v1alpha1.CheckNoHostPath:            podCheck(noHostPath),
+	v1alpha1.CheckDefaultDenyIngress:    defaultDenyIngress,
+}
+
+// RunChecks runs the built-in checks on obj and returns their findings as
+// drift keyed by check name, nil if all pass. r serves the checks looking at
+// other objects.
+func RunChecks(
+	ctx context.Context, r client.Reader, names []v1alpha1.Check, obj *unstructured.Unstructured,
+) (map[string]string, error) {
+	var drift map[string]string
+	for _, name := range names {
+		check, ok := checks[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown check %q", name)
+		}
+		findings, err := check(ctx, r, obj)
+		if err != nil {
+			return nil, fmt.Errorf("check %s: %w", name, err)
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		if drift == nil {
+			drift = map[string]string{}
+		}
+		drift[string(name)] = strings.Join(findings, "; ")
+	}
+	return drift, nil
+}
+
+// podSpecPaths locates the pod spec of the kinds pod checks apply to.
+var podSpecPaths = map[string][]string{
+	"Pod":         {"spec"},
+	"Deployment":  {"spec", "template", "spec"},
+	"ReplicaSet":  {"spec", "template", "spec"},
+	"StatefulSet": {"spec", "template", "spec"},
+	"DaemonSet":   {"spec", "template", "spec"},
+	"Job":         {"spec", "template", "spec"},
+	"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// podCheck adapts a check of pod specs to the kinds holding one. Other kinds
+// always pass.
+func podCheck(fn func(spec *corev1.PodSpec) []string) checkFunc {
+	return func(_ context.Context, _ client.Reader, obj *unstructured.Unstructured) ([]string, error) {
+		path, ok := podSpecPaths[obj.GetKind()]
+		if !ok {
+			return nil, nil
+		}
+		raw, found, err := unstructured.NestedMap(obj.Object, path...)
+		if err != nil || !found {
+			return nil, err
+		}
+		spec := &corev1.PodSpec{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, spec); err != nil {
+			return nil, fmt.Errorf("invalid pod spec: %w", err)
+		}
+		return fn(spec), nil
+	}
+}
+
+// container is a container of any type along with how findings refer to it.
+type container struct {
+	name string
+	*corev1.Container
+}
+
+func containers(spec *corev1.PodSpec) []container {
+	var all []container
+	for i := range spec.InitContainers {
+		all = append(all, container{"init container " + spec.InitContainers[i].Name, &spec.InitContainers[i]})
+	}
+	for i := range spec.Containers {
+		all = append(all, container{"container " + spec.Containers[i].Name, &spec.Containers[i]})
+	}
+	for i := range spec.EphemeralContainers {
+		c := corev1.Container(spec.EphemeralContainers[i].EphemeralContainerCommon)
+		all = append(all, container{"ephemeral container " + c.Name, &c})
+	}
+	return all
+}
+
+// baselineCapabilities are the capabilities the baseline profile allows to
+// add.
+var baselineCapabilities = []corev1.Capability{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE",
+	"SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+func podSecurityBaseline(spec *corev1.PodSpec) []string {
+	var findings []string
+	if spec.HostNetwork {
+		findings = append(findings, "uses the host network")
+	}
+	if spec.HostPID {
+		findings = append(findings, "uses the host PID namespace")
+	}
+	if spec.HostIPC {
+		findings = append(findings, "uses the host IPC namespace")
+	}
+	findings = append(findings, noHostPath(spec)...)
+	if sc := spec.SecurityContext; sc != nil && sc.SeccompProfile != nil &&
+		sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		findings = append(findings, "runs with an unconfined seccomp profile")
+	}
+	for _, c := range containers(spec) {
+		if sc := c.SecurityContext; sc != nil {
+			if sc.Privileged != nil && *sc.Privileged {
+				findings = append(findings, c.name+" is privileged")
+			}
+			if sc.Capabilities != nil {
+				for _, capability := range sc.Capabilities.Add {
+					if !slices.Contains(baselineCapabilities, capability) {
+						findings = append(findings, fmt.Sprintf("%s adds capability %s", c.name, capability))
+					}
+				}
+			}
+			if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+				findings = append(findings, c.name+" does not use the default proc mount")
+			}
+			if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+				findings = append(findings, c.name+" runs with an unconfined seccomp profile")
+			}
+		}
+		for _, port := range c.Ports {
+			if port.HostPort != 0 {
+				findings = append(findings, fmt.Sprintf("%s uses host port %d", c.name, port.HostPort))
+			}
+		}
+	}
+	return findings
+}
+
+func podSecurityRestricted(spec *corev1.PodSpec) []string {
+	findings := podSecurityBaseline(spec)
+	pod := spec.SecurityContext
+	if pod == nil {
+		pod = &corev1.PodSecurityContext{}
+	}
+	podNonRoot := pod.RunAsNonRoot != nil && *pod.RunAsNonRoot
+	podSeccomp := pod.SeccompProfile != nil && pod.SeccompProfile.Type != corev1.SeccompProfileTypeUnconfined
+	for _, c := range containers(spec) {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		if sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot || sc.RunAsNonRoot == nil && !podNonRoot {
+			findings = append(findings, c.name+" may run as root")
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			findings = append(findings, c.name+" allows privilege escalation")
+		}
+		if sc.Capabilities == nil || !slices.Contains(sc.Capabilities.Drop, "ALL") {
+			findings = append(findings, c.name+" does not drop all capabilities")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" && slices.Contains(baselineCapabilities, capability) {
+					findings = append(findings, fmt.Sprintf("%s adds capability %s", c.name, capability))
+				}
+			}
+		}
+		if sc.SeccompProfile == nil && !podSeccomp {
+			findings = append(findings, c.name+" has no seccomp profile")
+		}
+	}
+	return findings
+}
+
+func imageTagNotLatest(spec *corev1.PodSpec) []string {
+	var findings []string
+	for _, c := range containers(spec) {
+		if strings.Contains(c.Image, "@") {
+			continue
+		}
+		name := c.Image[strings.LastIndex(c.Image, "/")+1:]
+		if _, tag, ok := strings.Cut(name, ":"); !ok || tag == "latest" {
+			findings = append(findings, fmt.Sprintf("%s uses image %s without a pinned tag", c.name, c.Image))
+		}
+	}
+	return findings
+}
+
+func resourceLimits(spec *corev1.PodSpec) []string {
+	var findings []string
+	for _, c := range containers(spec) {
+		if strings.HasPrefix(c.name, "ephemeral ") {
+			continue // ephemeral containers cannot set resources
+		}
+		for _, resource := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := c.Resources.Limits[resource]; !ok {
+				findings = append(findings, fmt.Sprintf("%s has no %s limit", c.name, resource))
+			}
+		}
+	}
+	return findings
+}
+
+func noHostPath(spec *corev1.PodSpec) []string {
+	var findings []string
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			findings = append(findings, fmt.Sprintf("volume %s mounts host path %s", v.Name, v.HostPath.Path))
+		}
+	}
+	return findings
+}
+
+// defaultDenyIngress checks that a Namespace has a NetworkPolicy selecting
+// all its pods without allowing any ingress.
+func defaultDenyIngress(ctx context.Context, r client.Reader, obj *unstructured.Unstructured) ([]string, error) {
+	if obj.GetKind() != "Namespace" {
+		return nil, nil
+	}
+	var policies networkingv1.NetworkPolicyList
+	if err := r.List(ctx, &policies, client.InNamespace(obj.GetName())); err != nil {
+		return nil, fmt.Errorf("failed listing NetworkPolicies: %w", err)
+	}
+	for _, np := range policies.Items {
+		deniesIngress := len(np.Spec.PolicyTypes) == 0 ||
+			slices.Contains(np.Spec.PolicyTypes, networkingv1.PolicyTypeIngress)
+		if len(np.Spec.PodSelector.MatchLabels) == 0 && len(np.Spec.PodSelector.MatchExpressions) == 0 &&
+			deniesIngress && len(np.Spec.Ingress) == 0 {
+			return nil, nil
+		}
+	}
+	return []string{"no NetworkPolicy denies ingress by default"}, nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+func decode(manifest string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	ExpectWithOffset(1, yaml.Unmarshal([]byte(manifest), &obj.Object)).To(Succeed())
+	return obj
+}
+
+var _ = Describe("RunChecks", func() {
+	const compliant = `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web, namespace: team-a}
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        seccompProfile: {type: RuntimeDefault}
+      containers:
+      - name: web
+        image: registry.example.com/web:1.4.2
+        securityContext:
+          allowPrivilegeEscalation: false
+          capabilities: {drop: [ALL]}
+        resources:
+          limits: {cpu: 500m, memory: 256Mi}
+`
+	const careless = `
+apiVersion: batch/v1
+kind: CronJob
+metadata: {name: backup, namespace: team-a}
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          hostNetwork: true
+          volumes:
+          - name: data
+            hostPath: {path: /var/lib/data}
+          containers:
+          - name: backup
+            image: backup:latest
+            securityContext:
+              privileged: true
+              capabilities: {add: [SYS_ADMIN]}
+`
+	all := []v1alpha1.Check{
+		v1alpha1.CheckPodSecurityBaseline, v1alpha1.CheckPodSecurityRestricted, v1alpha1.CheckImageTagNotLatest,
+		v1alpha1.CheckResourceLimits, v1alpha1.CheckNoHostPath,
+	}
+
+	It("passes compliant workloads", func() {
+		drift, err := RunChecks(context.Background(), nil, all, decode(compliant))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).To(BeNil())
+	})
+
+	It("reports the findings of each check on the pod template", func() {
+		drift, err := RunChecks(context.Background(), nil, all, decode(careless))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).To(HaveKeyWithValue("PodSecurityBaseline",
+			"uses the host network; volume data mounts host path /var/lib/data; "+
+				"container backup is privileged; container backup adds capability SYS_ADMIN"))
+		Expect(drift).To(HaveKeyWithValue("PodSecurityRestricted", ContainSubstring(
+			"container backup may run as root; container backup allows privilege escalation; "+
+				"container backup does not drop all capabilities; container backup has no seccomp profile")))
+		Expect(drift).To(HaveKeyWithValue("ImageTagNotLatest",
+			"container backup uses image backup:latest without a pinned tag"))
+		Expect(drift).To(HaveKeyWithValue("ResourceLimits",
+			"container backup has no cpu limit; container backup has no memory limit"))
+		Expect(drift).To(HaveKeyWithValue("NoHostPath", "volume data mounts host path /var/lib/data"))
+	})
+
+	It("accepts images pinned by digest or tag only", func() {
+		spec := func(image string) *unstructured.Unstructured {
+			return decode(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "p"},
+				"spec": {"containers": [{"name": "c", "image": "` + image + `"}]}}`)
+		}
+		for image, pinned := range map[string]bool{
+			"nginx":                           false,
+			"nginx:latest":                    false,
+			"localhost:5000/nginx":            false,
+			"localhost:5000/nginx:1.27":       true,
+			"nginx@sha256:0123456789abcdef":   true,
+			"ghcr.io/org/app:v2.1.0":          true,
+			"ghcr.io/org/app:latest@sha256:0": true,
+		} {
+			drift, err := RunChecks(context.Background(), nil, []v1alpha1.Check{v1alpha1.CheckImageTagNotLatest}, spec(image))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift == nil).To(Equal(pinned), image)
+		}
+	})
+
+	It("ignores the kinds without a pod spec", func() {
+		drift, err := RunChecks(context.Background(), nil, all, decode(`{"apiVersion": "v1", "kind": "ConfigMap"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).To(BeNil())
+	})
+
+	It("rejects unknown checks", func() {
+		_, err := RunChecks(context.Background(), nil, []v1alpha1.Check{"Everything"}, decode(compliant))
+		Expect(err).To(MatchError(`unknown check "Everything"`))
+	})
+
+	It("looks for a default deny NetworkPolicy in Namespaces", func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		allowWeb := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow-web", Namespace: "team-a"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+				Ingress:     []networkingv1.NetworkPolicyIngressRule{{}},
+			},
+		}
+		denyAll := &networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "default-deny", Namespace: "team-b"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+		r := fake.NewClientBuilder().WithScheme(scheme).WithObjects(allowWeb, denyAll).Build()
+		checks := []v1alpha1.Check{v1alpha1.CheckDefaultDenyIngress}
+
+		drift, err := RunChecks(context.Background(), r, checks, decode(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "team-a"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).To(Equal(map[string]string{"DefaultDenyIngress": "no NetworkPolicy denies ingress by default"}))
+
+		drift, err = RunChecks(context.Background(), r, checks, decode(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "team-b"}}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(drift).To(BeNil())
+	})
+})
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates resources against PolicyProfiles. It is shared by
+// the controller, the kubectl plugin and the offline policy tests, so that
+// all of them reach the same verdict.
+package policy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// Outcome is the verdict of a profile on a resource.
+type Outcome struct {
+	// Matched reports whether the profile evaluates the resource at all.
+	Matched bool
+	// Drift describes the policy keys the resource drifts on, nil if none.
+	Drift map[string]string
+	// Exception is the PolicyException covering the drift, if any.
+	Exception *v1alpha1.PolicyException
+}
+
+// Violates reports whether the profile reports the resource.
+func (o Outcome) Violates() bool {
+	return o.Matched && len(o.Drift) > 0 && o.Exception == nil
+}
+
+// Evaluate compares obj, a resource of the kind the profile matches, with the
+// policy of the profile. obj must be unstructured for the profile to run its
+// checks, which read other objects from r. Exceptions apply to the resources
+// of their own namespace as long as they have not expired at now.
+func Evaluate(
+	ctx context.Context, r client.Reader, profile *v1alpha1.PolicyProfile, obj client.Object,
+	exceptions []v1alpha1.PolicyException, now metav1.Time,
+) (Outcome, error) {
+	namespace := HomeNamespace(profile.Spec.Match.Kind, obj)
+	if !profile.MatchesNamespace(namespace) {
+		return Outcome{}, nil
+	}
+	out := Outcome{Matched: true, Drift: profile.Drift(obj.GetLabels())}
+	if len(profile.Spec.Checks) > 0 {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return Outcome{}, fmt.Errorf("checks of profile %s need the whole %s", profile.Name, profile.Spec.Match.Kind)
+		}
+		findings, err := RunChecks(ctx, r, profile.Spec.Checks, u)
+		if err != nil {
+			return Outcome{}, err
+		}
+		if len(findings) > 0 && out.Drift == nil {
+			out.Drift = map[string]string{}
+		}
+		maps.Copy(out.Drift, findings)
+	}
+	if len(out.Drift) == 0 {
+		return out, nil
+	}
+	for i := range exceptions {
+		exc := &exceptions[i]
+		if exc.Namespace == namespace &&
+			exc.Covers(client.ObjectKeyFromObject(profile), profile.Spec.Match.Kind, obj.GetName(), now) {
+			out.Exception = exc
+			break
+		}
+	}
+	return out, nil
+}
+
+// HomeNamespace returns the namespace a resource of the given match kind is
+// matched, excepted and reported in: its own, or itself for a Namespace.
+func HomeNamespace(kind string, obj metav1.Object) string {
+	if kind == "Namespace" && obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace()
+}
+
+// MatchesKind reports whether the profile targets resources of kind gvk. The
+// match kind is either a bare kind or a kind qualified by its group, as in
+// Ingress.networking.k8s.io.
+func MatchesKind(profile *v1alpha1.PolicyProfile, gvk schema.GroupVersionKind) bool {
+	kind, group, qualified := strings.Cut(profile.Spec.Match.Kind, ".")
+	return kind == gvk.Kind && (!qualified || group == gvk.Group)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Evaluate", func() {
+	now := metav1.NewTime(time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+	profile := &v1alpha1.PolicyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "require-team", Namespace: "default"},
+		Spec: v1alpha1.PolicyProfileSpec{
+			Match:  v1alpha1.MatchSpec{Kind: "NetworkPolicy", Namespace: "team-*"},
+			Policy: map[string]string{"team": "payments"},
+		},
+	}
+	resource := func(namespace, name, team string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace, Name: name, Labels: map[string]string{"team": team},
+		}}
+	}
+	evaluate := func(obj client.Object, exceptions []v1alpha1.PolicyException, now metav1.Time) Outcome {
+		out, err := Evaluate(context.Background(), nil, profile, obj, exceptions, now)
+		Expect(err).NotTo(HaveOccurred())
+		return out
+	}
+	exception := func(namespace, name string, expiresAt *metav1.Time) v1alpha1.PolicyException {
+		return v1alpha1.PolicyException{
+			ObjectMeta: metav1.ObjectMeta{Name: "exc", Namespace: namespace},
+			Spec: v1alpha1.PolicyExceptionSpec{
+				ProfileName:      "require-team",
+				ProfileNamespace: "default",
+				Resource:         v1alpha1.ExceptionResourceSpec{Kind: "NetworkPolicy", Name: name},
+				ExpiresAt:        expiresAt,
+			},
+		}
+	}
+
+	It("ignores the resources of other namespaces", func() {
+		out := evaluate(resource("default", "web", "legacy"), nil, now)
+		Expect(out).To(Equal(Outcome{}))
+		Expect(out.Violates()).To(BeFalse())
+	})
+
+	It("passes resources that follow the policy", func() {
+		out := evaluate(resource("team-a", "web", "payments"), nil, now)
+		Expect(out.Matched).To(BeTrue())
+		Expect(out.Drift).To(BeNil())
+		Expect(out.Violates()).To(BeFalse())
+	})
+
+	It("reports the drift of violating resources", func() {
+		out := evaluate(resource("team-a", "web", "legacy"), nil, now)
+		Expect(out.Drift).To(Equal(map[string]string{"team": "Expected: payments, Got: legacy"}))
+		Expect(out.Violates()).To(BeTrue())
+	})
+
+	It("applies the unexpired exceptions of the resource namespace", func() {
+		expired := metav1.NewTime(now.Add(-time.Hour))
+		exceptions := []v1alpha1.PolicyException{
+			exception("team-b", "web", nil),
+			exception("team-a", "web", &expired),
+			exception("team-a", "*", nil),
+		}
+		out := evaluate(resource("team-a", "web", "legacy"), exceptions, now)
+		Expect(out.Exception).To(Equal(&exceptions[2]))
+		Expect(out.Drift).To(HaveKey("team"))
+		Expect(out.Violates()).To(BeFalse())
+	})
+
+	It("ignores the exceptions of a profile of the same name in another namespace", func() {
+		other := exception("team-a", "web", nil)
+		other.Spec.ProfileNamespace = ""
+		out := evaluate(resource("team-a", "web", "legacy"), []v1alpha1.PolicyException{other}, now)
+		Expect(out.Exception).To(BeNil())
+		Expect(out.Violates()).To(BeTrue())
+	})
+
+	It("runs the checks of the profile on unstructured resources", func() {
+		checked := profile.DeepCopy()
+		checked.Spec.Checks = []v1alpha1.Check{v1alpha1.CheckNoHostPath}
+		pod := decode(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web", "namespace": "team-a",
+			"labels": {"team": "payments"}}, "spec": {"volumes": [{"name": "logs", "hostPath": {"path": "/var/log"}}]}}`)
+		out, err := Evaluate(context.Background(), nil, checked, pod, nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Drift).To(Equal(map[string]string{"NoHostPath": "volume logs mounts host path /var/log"}))
+
+		_, err = Evaluate(context.Background(), nil, checked, resource("team-a", "web", "payments"), nil, now)
+		Expect(err).To(MatchError("checks of profile require-team need the whole NetworkPolicy"))
+	})
+
+	It("matches Namespaces in themselves", func() {
+		namespaces := profile.DeepCopy()
+		namespaces.Spec.Match.Kind = "Namespace"
+		exceptions := []v1alpha1.PolicyException{exception("team-a", "team-a", nil)}
+		exceptions[0].Spec.Resource.Kind = "Namespace"
+		out, err := Evaluate(context.Background(), nil, namespaces, resource("", "team-a", "legacy"), exceptions, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Matched).To(BeTrue())
+		Expect(out.Exception).To(Equal(&exceptions[0]))
+		out, err = Evaluate(context.Background(), nil, namespaces, resource("", "default", "legacy"), nil, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.Matched).To(BeFalse())
+	})
+})
+
+var _ = DescribeTable("MatchesKind",
+	func(kind string, gvk schema.GroupVersionKind, matches bool) {
+		profile := &v1alpha1.PolicyProfile{Spec: v1alpha1.PolicyProfileSpec{Match: v1alpha1.MatchSpec{Kind: kind}}}
+		Expect(MatchesKind(profile, gvk)).To(Equal(matches))
+	},
+	Entry("bare kind", "NetworkPolicy", schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "NetworkPolicy"}, true),
+	Entry("other kind", "NetworkPolicy", schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, false),
+	Entry("qualified kind", "Ingress.networking.k8s.io", schema.GroupVersionKind{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"}, true),
+	Entry("other group", "Ingress.networking.k8s.io", schema.GroupVersionKind{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}, false),
+)
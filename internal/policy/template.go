@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+// ResolveTemplate returns the profile resolved with its PolicyTemplate, read
+// from reader, or the profile itself if it uses no template.
+func ResolveTemplate(ctx context.Context, reader client.Reader, profile *v1alpha1.PolicyProfile) (*v1alpha1.PolicyProfile, error) {
+	if profile.Spec.Template == nil {
+		return profile, nil
+	}
+	template := &v1alpha1.PolicyTemplate{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: profile.Spec.Template.Name}, template); err != nil {
+		return nil, fmt.Errorf("failed fetching PolicyTemplate %s: %w", profile.Spec.Template.Name, err)
+	}
+	return Resolve(profile, template)
+}
+
+// Resolve returns a copy of the profile whose policy includes the label rules
+// of template, rendered with the parameters of the profile, and its checks.
+// The policy of the profile wins where both set a label, and so does its
+// remediation. It fails if the parameters do not match the ones the template
+// declares.
+func Resolve(profile *v1alpha1.PolicyProfile, template *v1alpha1.PolicyTemplate) (*v1alpha1.PolicyProfile, error) {
+	var values map[string]apiextensionsv1.JSON
+	if profile.Spec.Template != nil {
+		values = profile.Spec.Template.Parameters
+	}
+	params, err := Parameters(template, values)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := profile.DeepCopy()
+	if resolved.Spec.Policy == nil {
+		resolved.Spec.Policy = map[string]string{}
+	}
+	for i, rule := range template.Spec.Labels {
+		keys := []string{rule.Key}
+		if rule.KeysFrom != "" {
+			v, err := ruleParameter(template, params, i, rule.KeysFrom, v1alpha1.ParameterStringList)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				continue
+			}
+			keys = v.([]string)
+		}
+		value, required := rule.Value, rule.Value == ""
+		if rule.ValueFrom != "" {
+			v, err := ruleParameter(template, params, i, rule.ValueFrom, v1alpha1.ParameterString)
+			if err != nil {
+				return nil, err
+			}
+			if v == nil {
+				continue
+			}
+			value, required = v.(string), false
+		}
+		for _, key := range keys {
+			if _, ok := profile.Spec.Policy[key]; ok {
+				continue
+			}
+			if !required {
+				resolved.Spec.Policy[key] = value
+			} else if !slices.Contains(resolved.Spec.RequiredLabels, key) {
+				resolved.Spec.RequiredLabels = append(resolved.Spec.RequiredLabels, key)
+			}
+		}
+	}
+	for _, check := range template.Spec.Checks {
+		if !slices.Contains(resolved.Spec.Checks, check) {
+			resolved.Spec.Checks = append(resolved.Spec.Checks, check)
+		}
+	}
+	if resolved.Spec.Remediation == "" {
+		resolved.Spec.Remediation = template.Spec.Remediation
+	}
+	return resolved, nil
+}
+
+// ruleParameter returns the value of the parameter a rule refers to, nil if
+// it is optional and not passed.
+func ruleParameter(
+	template *v1alpha1.PolicyTemplate, params map[string]any, rule int, name string, typ v1alpha1.ParameterType,
+) (any, error) {
+	for _, p := range template.Spec.Parameters {
+		if p.Name != name {
+			continue
+		}
+		if parameterType(p) != typ {
+			return nil, fmt.Errorf("label rule %d: parameter %s is not a %s", rule, name, typ)
+		}
+		return params[name], nil
+	}
+	return nil, fmt.Errorf("label rule %d: undeclared parameter %s", rule, name)
+}
+
+// Parameters checks values against the parameters template declares and
+// returns them with the defaults applied, as strings or string slices.
+func Parameters(template *v1alpha1.PolicyTemplate, values map[string]apiextensionsv1.JSON) (map[string]any, error) {
+	var errs []error
+	declared := map[string]bool{}
+	params := map[string]any{}
+	for _, p := range template.Spec.Parameters {
+		declared[p.Name] = true
+		raw, ok := values[p.Name]
+		if !ok {
+			if p.Required {
+				errs = append(errs, fmt.Errorf("parameter %s is required", p.Name))
+				continue
+			}
+			if p.Default == nil {
+				continue
+			}
+			raw = *p.Default
+		}
+		v, err := parameterValue(p, raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("parameter %s: %w", p.Name, err))
+			continue
+		}
+		params[p.Name] = v
+	}
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if !declared[name] {
+			errs = append(errs, fmt.Errorf("parameter %s is not declared by template %s", name, template.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return params, nil
+}
+
+func parameterType(p v1alpha1.TemplateParameter) v1alpha1.ParameterType {
+	if p.Type == "" {
+		return v1alpha1.ParameterString
+	}
+	return p.Type
+}
+
+// parameterValue decodes raw according to the type of p and checks it
+// against its enum and pattern.
+func parameterValue(p v1alpha1.TemplateParameter, raw apiextensionsv1.JSON) (any, error) {
+	var items []string
+	var value any
+	switch parameterType(p) {
+	case v1alpha1.ParameterStringList:
+		if err := json.Unmarshal(raw.Raw, &items); err != nil {
+			return nil, fmt.Errorf("expected a list of strings, got %s", raw.Raw)
+		}
+		value = items
+	default:
+		var s string
+		if err := json.Unmarshal(raw.Raw, &s); err != nil {
+			return nil, fmt.Errorf("expected a string, got %s", raw.Raw)
+		}
+		items, value = []string{s}, s
+	}
+
+	var pattern *regexp.Regexp
+	if p.Pattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(p.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	for _, item := range items {
+		if len(p.Enum) > 0 && !slices.Contains(p.Enum, item) {
+			return nil, fmt.Errorf("%q is not one of %q", item, p.Enum)
+		}
+		if pattern != nil && !pattern.MatchString(item) {
+			return nil, fmt.Errorf("%q does not match %s", item, p.Pattern)
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Resolve", func() {
+	var template *v1alpha1.PolicyTemplate
+
+	raw := func(value string) apiextensionsv1.JSON {
+		return apiextensionsv1.JSON{Raw: []byte(value)}
+	}
+	profile := func(policy map[string]string, params map[string]apiextensionsv1.JSON) *v1alpha1.PolicyProfile {
+		return &v1alpha1.PolicyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "labels", Namespace: "team-a"},
+			Spec: v1alpha1.PolicyProfileSpec{
+				Match:    v1alpha1.MatchSpec{Kind: "Deployment", Namespace: "team-a"},
+				Policy:   policy,
+				Template: &v1alpha1.TemplateReference{Name: "required-labels", Parameters: params},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		def := raw(`"low"`)
+		template = &v1alpha1.PolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "required-labels"},
+			Spec: v1alpha1.PolicyTemplateSpec{
+				Parameters: []v1alpha1.TemplateParameter{
+					{Name: "requiredLabels", Type: v1alpha1.ParameterStringList, Required: true, Pattern: `^[a-z./-]+$`},
+					{Name: "team"},
+					{Name: "tier", Enum: []string{"low", "high"}, Default: &def},
+				},
+				Labels: []v1alpha1.LabelRule{
+					{KeysFrom: "requiredLabels"},
+					{Key: "team", ValueFrom: "team"},
+					{Key: "tier", ValueFrom: "tier"},
+					{Key: "managed-by", Value: "gokubedog"},
+				},
+			},
+		}
+	})
+
+	It("adds the rules of the template to the policy of the profile", func() {
+		p := profile(map[string]string{"managed-by": "helm"}, map[string]apiextensionsv1.JSON{
+			"requiredLabels": raw(`["app", "owner"]`),
+			"team":           raw(`"payments"`),
+		})
+		resolved, err := Resolve(p, template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Spec.RequiredLabels).To(Equal([]string{"app", "owner"}))
+		Expect(resolved.Spec.Policy).To(Equal(map[string]string{
+			"team":       "payments",
+			"tier":       "low",
+			"managed-by": "helm",
+		}))
+		Expect(p.Spec.Policy).To(HaveLen(1), "the profile is left untouched")
+
+		Expect(resolved.Drift(map[string]string{"app": "web", "team": "payments", "tier": "low", "managed-by": "helm"})).
+			To(Equal(map[string]string{"owner": "Expected: any value, Got: "}))
+	})
+
+	It("adds the checks of the template and its remediation by default", func() {
+		template.Spec.Checks = []v1alpha1.Check{v1alpha1.CheckNoHostPath, v1alpha1.CheckResourceLimits}
+		template.Spec.Remediation = "Fix the pod template."
+		p := profile(nil, map[string]apiextensionsv1.JSON{"requiredLabels": raw(`["app"]`)})
+		p.Spec.Checks = []v1alpha1.Check{v1alpha1.CheckResourceLimits}
+		resolved, err := Resolve(p, template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Spec.Checks).To(Equal([]v1alpha1.Check{v1alpha1.CheckResourceLimits, v1alpha1.CheckNoHostPath}))
+		Expect(resolved.Spec.Remediation).To(Equal("Fix the pod template."))
+
+		p.Spec.Remediation = "Ask the platform team."
+		resolved, err = Resolve(p, template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Spec.Remediation).To(Equal("Ask the platform team."))
+	})
+
+	It("skips the rules of optional parameters that are not passed", func() {
+		template.Spec.Parameters[2].Default = nil
+		resolved, err := Resolve(profile(nil, map[string]apiextensionsv1.JSON{
+			"requiredLabels": raw(`["app"]`),
+		}), template)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Spec.Policy).To(Equal(map[string]string{"managed-by": "gokubedog"}))
+	})
+
+	DescribeTable("rejects invalid parameters",
+		func(params map[string]string, message string) {
+			values := map[string]apiextensionsv1.JSON{}
+			for k, v := range params {
+				values[k] = raw(v)
+			}
+			_, err := Resolve(profile(nil, values), template)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("missing", map[string]string{}, "parameter requiredLabels is required"),
+		Entry("undeclared", map[string]string{"requiredLabels": `[]`, "owner": `"me"`},
+			"parameter owner is not declared by template required-labels"),
+		Entry("wrong type", map[string]string{"requiredLabels": `"app"`}, "expected a list of strings"),
+		Entry("not in enum", map[string]string{"requiredLabels": `[]`, "tier": `"mid"`}, `"mid" is not one of`),
+		Entry("not matching", map[string]string{"requiredLabels": `["App"]`}, `"App" does not match`),
+	)
+
+	It("rejects rules referring to undeclared or mistyped parameters", func() {
+		template.Spec.Labels = append(template.Spec.Labels, v1alpha1.LabelRule{KeysFrom: "team"})
+		_, err := Resolve(profile(nil, map[string]apiextensionsv1.JSON{
+			"requiredLabels": raw(`[]`), "team": raw(`"payments"`),
+		}), template)
+		Expect(err).To(MatchError("label rule 4: parameter team is not a StringList"))
+	})
+
+	It("reads the template of the profile", func() {
+		scheme := runtime.NewScheme()
+		Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+		p := profile(nil, map[string]apiextensionsv1.JSON{"requiredLabels": raw(`["app"]`)})
+
+		_, err := ResolveTemplate(context.Background(), fake.NewClientBuilder().WithScheme(scheme).Build(), p)
+		Expect(err).To(MatchError(ContainSubstring("failed fetching PolicyTemplate required-labels")))
+
+		r := fake.NewClientBuilder().WithScheme(scheme).WithObjects(template).Build()
+		resolved, err := ResolveTemplate(context.Background(), r, p)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved.Spec.RequiredLabels).To(Equal([]string{"app"}))
+
+		p.Spec.Template = nil
+		Expect(ResolveTemplate(context.Background(), r, p)).To(BeIdenticalTo(p))
+	})
+})
@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retention removes PolicyViolationReports that are no longer worth
+// keeping so the number of reports in etcd stays bounded.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+const (
+	reasonTTL = "ttl"
+	reasonCap = "cap"
+)
+
+// Policy is the retention policy applied to the reports of a single profile.
+// Zero values disable the corresponding rule.
+type Policy struct {
+	// ResolvedTTL is how long Resolved reports are kept.
+	ResolvedTTL time.Duration
+	// MaxReportsPerNamespace caps the reports of all profiles together in
+	// one namespace. It is manager-wide only.
+	MaxReportsPerNamespace int
+	// MaxProfileReportsPerNamespace caps the reports of a single profile in
+	// one namespace, as set by spec.retention.maxReportsPerNamespace.
+	MaxProfileReportsPerNamespace int
+}
+
+// ForProfile returns the policy with the overrides from the profile applied.
+func (p Policy) ForProfile(profile *watchdogv1alpha1.PolicyProfile) Policy {
+	if profile == nil || profile.Spec.Retention == nil {
+		return p
+	}
+	if ttl := profile.Spec.Retention.ResolvedReportTTL; ttl != nil {
+		p.ResolvedTTL = ttl.Duration
+	}
+	if maxReports := profile.Spec.Retention.MaxReportsPerNamespace; maxReports != nil {
+		p.MaxProfileReportsPerNamespace = int(*maxReports)
+	}
+	return p
+}
+
+// Janitor periodically deletes PolicyViolationReports according to the
+// retention policy of the profile that produced them.
+type Janitor struct {
+	client.Client
+	Recorder record.EventRecorder
+	// Defaults is the manager-wide policy, overridden per profile by spec.retention.
+	Defaults Policy
+	// Interval is the time between two sweeps.
+	Interval time.Duration
+
+	now func() time.Time
+}
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Start runs sweeps until the context is cancelled. It implements manager.Runnable.
+func (j *Janitor) Start(ctx context.Context) error {
+	l := logf.FromContext(ctx).WithName("retention")
+
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Sweep(ctx); err != nil {
+			l.Error(err, "report retention sweep failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only the leading manager deletes reports.
+func (j *Janitor) NeedLeaderElection() bool {
+	return true
+}
+
+// Sweep runs a single retention pass over all PolicyViolationReports.
+func (j *Janitor) Sweep(ctx context.Context) error {
+	err := j.sweep(ctx)
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	sweepsTotal.WithLabelValues(result).Inc()
+	return err
+}
+
+func (j *Janitor) sweep(ctx context.Context) error {
+	reports := &watchdogv1alpha1.PolicyViolationReportList{}
+	if err := j.List(ctx, reports); err != nil {
+		return fmt.Errorf("failed listing reports: %w", err)
+	}
+
+	// Group reports by the profile that produced them, then by namespace
+	groups := map[types.NamespacedName]map[string][]*watchdogv1alpha1.PolicyViolationReport{}
+	for i := range reports.Items {
+		rep := &reports.Items[i]
+		key := profileKey(rep)
+		if groups[key] == nil {
+			groups[key] = map[string][]*watchdogv1alpha1.PolicyViolationReport{}
+		}
+		groups[key][rep.Namespace] = append(groups[key][rep.Namespace], rep)
+	}
+
+	// Apply the policy of each profile, then the namespace-wide cap to the
+	// reports all profiles kept
+	profiles := map[types.NamespacedName]*watchdogv1alpha1.PolicyProfile{}
+	kept := map[string][]*watchdogv1alpha1.PolicyViolationReport{}
+	for key, byNamespace := range groups {
+		profile, err := j.getProfile(ctx, key)
+		if err != nil {
+			return err
+		}
+		profiles[key] = profile
+		policy := j.Defaults.ForProfile(profile)
+
+		for namespace, reps := range byNamespace {
+			remaining, deleted, err := j.prune(ctx, policy, reps)
+			for reason, count := range deleted {
+				j.recordPruned(profile, namespace, reason, count)
+			}
+			if err != nil {
+				return err
+			}
+			kept[namespace] = append(kept[namespace], remaining...)
+		}
+	}
+
+	for namespace, reps := range kept {
+		_, evicted, err := j.evict(ctx, reps, j.Defaults.MaxReportsPerNamespace)
+		perProfile := map[types.NamespacedName]int{}
+		for _, rep := range evicted {
+			perProfile[profileKey(rep)]++
+		}
+		for key, count := range perProfile {
+			j.recordPruned(profiles[key], namespace, reasonCap, count)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordPruned counts the reports of a profile deleted in a namespace for a
+// reason, and tells about it on the profile if it still exists.
+func (j *Janitor) recordPruned(profile *watchdogv1alpha1.PolicyProfile, namespace, reason string, count int) {
+	if count == 0 {
+		return
+	}
+	deletedReportsTotal.WithLabelValues(reason).Add(float64(count))
+	if profile != nil && j.Recorder != nil {
+		j.Recorder.Eventf(profile, corev1.EventTypeNormal, "ReportsPruned",
+			"Deleted %d PolicyViolationReports in namespace %s (%s)", count, namespace, reason)
+	}
+}
+
+// prune deletes the reports of one profile in one namespace that fall
+// outside the policy of the profile. It returns the reports it kept and how
+// many were deleted per reason.
+func (j *Janitor) prune(
+	ctx context.Context, policy Policy, reps []*watchdogv1alpha1.PolicyViolationReport,
+) ([]*watchdogv1alpha1.PolicyViolationReport, map[string]int, error) {
+	deleted := map[string]int{}
+	now := j.clock()
+
+	kept := make([]*watchdogv1alpha1.PolicyViolationReport, 0, len(reps))
+	for _, rep := range reps {
+		if policy.ResolvedTTL > 0 && rep.Status.IsResolved() && now.Sub(resolvedAt(rep)) > policy.ResolvedTTL {
+			if err := j.deleteReport(ctx, rep); err != nil {
+				return nil, deleted, err
+			}
+			deleted[reasonTTL]++
+			continue
+		}
+		kept = append(kept, rep)
+	}
+
+	kept, evicted, err := j.evict(ctx, kept, policy.MaxProfileReportsPerNamespace)
+	deleted[reasonCap] = len(evicted)
+	return kept, deleted, err
+}
+
+// evict deletes the oldest Resolved reports until at most maxReports
+// remain, or only Open ones. It returns the reports it kept and the ones it
+// deleted. A zero maxReports keeps them all.
+func (j *Janitor) evict(
+	ctx context.Context, reps []*watchdogv1alpha1.PolicyViolationReport, maxReports int,
+) (kept, evicted []*watchdogv1alpha1.PolicyViolationReport, err error) {
+	if maxReports <= 0 || len(reps) <= maxReports {
+		return reps, nil, nil
+	}
+	// Oldest resolved reports first, they are the first to go
+	sort.Slice(reps, func(a, b int) bool {
+		return resolvedAt(reps[a]).Before(resolvedAt(reps[b]))
+	})
+	excess := len(reps) - maxReports
+	kept = make([]*watchdogv1alpha1.PolicyViolationReport, 0, maxReports)
+	for i, rep := range reps {
+		if excess <= 0 || !rep.Status.IsResolved() {
+			kept = append(kept, rep)
+			continue
+		}
+		if err := j.deleteReport(ctx, rep); err != nil {
+			return append(kept, reps[i:]...), evicted, err
+		}
+		evicted = append(evicted, rep)
+		excess--
+	}
+	return kept, evicted, nil
+}
+
+func (j *Janitor) deleteReport(ctx context.Context, rep *watchdogv1alpha1.PolicyViolationReport) error {
+	if err := j.Delete(ctx, rep); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed deleting PolicyViolationReport %s/%s: %w", rep.Namespace, rep.Name, err)
+	}
+	logf.FromContext(ctx).V(1).Info("Deleted PolicyViolationReport", "name", rep.Name, "namespace", rep.Namespace)
+	return nil
+}
+
+func (j *Janitor) getProfile(ctx context.Context, key types.NamespacedName) (*watchdogv1alpha1.PolicyProfile, error) {
+	if key.Name == "" {
+		return nil, nil
+	}
+	profile := &watchdogv1alpha1.PolicyProfile{}
+	if err := j.Get(ctx, key, profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed fetching PolicyProfile %s: %w", key, err)
+	}
+	return profile, nil
+}
+
+func (j *Janitor) clock() time.Time {
+	if j.now != nil {
+		return j.now()
+	}
+	return time.Now()
+}
+
+func profileKey(rep *watchdogv1alpha1.PolicyViolationReport) types.NamespacedName {
+	if name, ok := rep.Labels[watchdogv1alpha1.ProfileNameLabel]; ok {
+		return types.NamespacedName{Namespace: rep.Labels[watchdogv1alpha1.ProfileNamespaceLabel], Name: name}
+	}
+	return types.NamespacedName{Namespace: rep.Spec.ProfileNamespace, Name: rep.Spec.ProfileName}
+}
+
+// resolvedAt falls back to the creation time for reports resolved before
+// ResolvedAt was recorded.
+func resolvedAt(rep *watchdogv1alpha1.PolicyViolationReport) time.Time {
+	if rep.Status.ResolvedAt != nil {
+		return rep.Status.ResolvedAt.Time
+	}
+	return rep.CreationTimestamp.Time
+}
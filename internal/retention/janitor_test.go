@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+)
+
+var _ = Describe("Janitor", func() {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var testScheme *runtime.Scheme
+	BeforeEach(func() {
+		testScheme = runtime.NewScheme()
+		Expect(watchdogv1alpha1.AddToScheme(testScheme)).To(Succeed())
+	})
+
+	newReport := func(name string, phase watchdogv1alpha1.ReportPhase, resolvedAgo time.Duration) *watchdogv1alpha1.PolicyViolationReport {
+		rep := &watchdogv1alpha1.PolicyViolationReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					watchdogv1alpha1.ProfileNameLabel:      "profile",
+					watchdogv1alpha1.ProfileNamespaceLabel: "default",
+				},
+			},
+			Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+				ProfileName: "profile",
+				ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{
+					Kind: "NetworkPolicy", Name: name, Namespace: "default",
+				},
+			},
+			Status: watchdogv1alpha1.PolicyViolationReportStatus{Phase: phase},
+		}
+		if phase == watchdogv1alpha1.ReportPhaseResolved {
+			at := metav1.NewTime(now.Add(-resolvedAgo))
+			rep.Status.ResolvedAt = &at
+		}
+		return rep
+	}
+
+	remaining := func(cl client.Client) []string {
+		var reports watchdogv1alpha1.PolicyViolationReportList
+		Expect(cl.List(ctx, &reports)).To(Succeed())
+		names := []string{}
+		for _, rep := range reports.Items {
+			names = append(names, rep.Name)
+		}
+		return names
+	}
+
+	It("should delete resolved reports older than the TTL", func() {
+		cl := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			newReport("open", watchdogv1alpha1.ReportPhaseOpen, 0),
+			newReport("resolved-old", watchdogv1alpha1.ReportPhaseResolved, 48*time.Hour),
+			newReport("resolved-new", watchdogv1alpha1.ReportPhaseResolved, time.Hour),
+		).Build()
+		j := &Janitor{Client: cl, Defaults: Policy{ResolvedTTL: 24 * time.Hour}, now: func() time.Time { return now }}
+
+		Expect(j.Sweep(ctx)).To(Succeed())
+		Expect(remaining(cl)).To(ConsistOf("open", "resolved-new"))
+	})
+
+	It("should evict the oldest resolved reports over the namespace cap and keep open ones", func() {
+		cl := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			newReport("open-1", watchdogv1alpha1.ReportPhaseOpen, 0),
+			newReport("open-2", watchdogv1alpha1.ReportPhaseOpen, 0),
+			newReport("resolved-old", watchdogv1alpha1.ReportPhaseResolved, 3*time.Hour),
+			newReport("resolved-new", watchdogv1alpha1.ReportPhaseResolved, time.Hour),
+		).Build()
+		j := &Janitor{Client: cl, Defaults: Policy{MaxReportsPerNamespace: 1}, now: func() time.Time { return now }}
+
+		Expect(j.Sweep(ctx)).To(Succeed())
+		Expect(remaining(cl)).To(ConsistOf("open-1", "open-2"))
+	})
+
+	It("should cap the reports of all profiles together in a namespace", func() {
+		other := newReport("other-resolved", watchdogv1alpha1.ReportPhaseResolved, 2*time.Hour)
+		other.Labels[watchdogv1alpha1.ProfileNameLabel] = "other-profile"
+		elsewhere := newReport("elsewhere-resolved", watchdogv1alpha1.ReportPhaseResolved, 5*time.Hour)
+		elsewhere.Namespace = "team-a"
+		cl := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			newReport("open", watchdogv1alpha1.ReportPhaseOpen, 0),
+			newReport("resolved-old", watchdogv1alpha1.ReportPhaseResolved, 3*time.Hour),
+			newReport("resolved-new", watchdogv1alpha1.ReportPhaseResolved, time.Hour),
+			other, elsewhere,
+		).Build()
+		j := &Janitor{Client: cl, Defaults: Policy{MaxReportsPerNamespace: 2}, now: func() time.Time { return now }}
+
+		Expect(j.Sweep(ctx)).To(Succeed())
+		Expect(remaining(cl)).To(ConsistOf("open", "resolved-new", "elsewhere-resolved"))
+	})
+
+	It("should apply the cap of a profile to its own reports only", func() {
+		maxReports := int32(1)
+		profile := &watchdogv1alpha1.PolicyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+			Spec: watchdogv1alpha1.PolicyProfileSpec{
+				Retention: &watchdogv1alpha1.RetentionSpec{MaxReportsPerNamespace: &maxReports},
+			},
+		}
+		other := newReport("other-resolved", watchdogv1alpha1.ReportPhaseResolved, 5*time.Hour)
+		other.Labels[watchdogv1alpha1.ProfileNameLabel] = "other-profile"
+		cl := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			profile, other,
+			newReport("resolved-old", watchdogv1alpha1.ReportPhaseResolved, 3*time.Hour),
+			newReport("resolved-new", watchdogv1alpha1.ReportPhaseResolved, time.Hour),
+		).Build()
+		j := &Janitor{Client: cl, now: func() time.Time { return now }}
+
+		Expect(j.Sweep(ctx)).To(Succeed())
+		Expect(remaining(cl)).To(ConsistOf("resolved-new", "other-resolved"))
+	})
+
+	It("should apply the profile override and record an event on the profile", func() {
+		ttl := metav1.Duration{Duration: 30 * time.Minute}
+		profile := &watchdogv1alpha1.PolicyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+			Spec: watchdogv1alpha1.PolicyProfileSpec{
+				Retention: &watchdogv1alpha1.RetentionSpec{ResolvedReportTTL: &ttl},
+			},
+		}
+		cl := fake.NewClientBuilder().WithScheme(testScheme).WithObjects(
+			profile,
+			newReport("resolved", watchdogv1alpha1.ReportPhaseResolved, time.Hour),
+		).Build()
+		recorder := record.NewFakeRecorder(10)
+		j := &Janitor{
+			Client:   cl,
+			Recorder: recorder,
+			Defaults: Policy{ResolvedTTL: 24 * time.Hour},
+			now:      func() time.Time { return now },
+		}
+
+		Expect(j.Sweep(ctx)).To(Succeed())
+		Expect(remaining(cl)).To(BeEmpty())
+		Expect(recorder.Events).To(Receive(ContainSubstring("ReportsPruned")))
+	})
+})
@@ -0,0 +1,38 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retention
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	deletedReportsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gokubedog_retention_deleted_reports_total",
+		Help: "Number of PolicyViolationReports deleted by the retention janitor, by reason (ttl, cap).",
+	}, []string{"reason"})
+
+	sweepsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gokubedog_retention_sweeps_total",
+		Help: "Number of retention sweeps run, by result (success, error).",
+	}, []string{"result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(deletedReportsTotal, sweepsTotal)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/target"
+)
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=patch
+// +kubebuilder:rbac:groups="",resources=pods;configmaps,verbs=patch
+
+// Remediator resets the drifted labels of a violated resource.
+type Remediator interface {
+	Remediate(ctx context.Context, res watchdogv1alpha1.ViolatedResourceSpec, labels map[string]string) error
+}
+
+// LabelRemediator merge-patches the labels of resources through the metadata
+// API, so that it works for any kind a profile can target.
+type LabelRemediator struct {
+	Targets *target.Lister
+}
+
+// Remediate implements Remediator.
+func (r *LabelRemediator) Remediate(ctx context.Context, res watchdogv1alpha1.ViolatedResourceSpec, labels map[string]string) error {
+	gvr, err := r.Targets.Resolve(res.Kind)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]any{"metadata": map[string]any{"labels": labels}})
+	if err != nil {
+		return err
+	}
+	_, err = r.Targets.Metadata.Resource(gvr).Namespace(res.Namespace).
+		Patch(ctx, res.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed patching %s %s/%s: %w", res.Kind, res.Namespace, res.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slackapp serves the Slack interactivity endpoint, applying the
+// actions of the buttons of Block Kit notifications to the reports.
+package slackapp
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/httpapi"
+	"github.com/madmmas/gokubedog/internal/notify"
+	"github.com/madmmas/gokubedog/internal/policy"
+)
+
+const (
+	// Path is where the endpoint is served, the request URL of the Slack app.
+	Path = "/slack/interactions"
+
+	maxRequestBytes = 64 * 1024
+	// maxClockSkew bounds the age of a signed request, limiting replays.
+	maxClockSkew = 5 * time.Minute
+)
+
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyviolationreports,verbs=get;update
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyprofiles,verbs=get
+// +kubebuilder:rbac:groups=watchdog.bizaikube.io,resources=policyexceptions,verbs=create
+
+// Handler serves the Slack interactivity endpoint. Requests are authenticated
+// with the signing secret of the Slack app; anyone in a channel receiving the
+// notifications may therefore act on them.
+type Handler struct {
+	Client        client.Client
+	SigningSecret []byte
+	// Remediator resets drifted resources, remediation is disabled if nil.
+	Remediator Remediator
+	// HTTPClient posts the outcome of actions to the response URL of the
+	// interaction, http.DefaultClient if nil.
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+// Routes returns the endpoint keyed by path. It is public to the API server
+// filter since Slack authenticates with request signatures instead.
+func (h *Handler) Routes() map[string]http.Handler {
+	return map[string]http.Handler{Path: httpapi.Public(h)}
+}
+
+func (h *Handler) clock() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
+
+// interaction is the part of a block_actions payload the handler uses.
+type interaction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	ResponseURL string `json:"response_url"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	log := logf.FromContext(req.Context())
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxRequestBytes))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := h.verify(req.Header, body); err != nil {
+		log.Info("rejected Slack request", "reason", err.Error())
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var in interaction
+	if err := json.Unmarshal([]byte(form.Get("payload")), &in); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	if in.Type != "block_actions" || len(in.Actions) == 0 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	// Slack expects an answer within 3 seconds, actions are fast enough to
+	// complete before replying.
+	action := in.Actions[0]
+	user := "slack:" + in.User.Username
+	if in.User.Username == "" {
+		user = "slack:" + in.User.ID
+	}
+	text, err := h.apply(req.Context(), action.ActionID, action.Value, user)
+	if err != nil {
+		log.Error(err, "failed applying Slack action", "action", action.ActionID, "report", action.Value, "user", user)
+		text = fmt.Sprintf("⚠️ %s failed: %v", actionName(action.ActionID), err)
+	} else {
+		log.Info("applied Slack action", "action", action.ActionID, "report", action.Value, "user", user)
+	}
+	w.WriteHeader(http.StatusOK)
+	if in.ResponseURL != "" {
+		if err := h.reply(req.Context(), in.ResponseURL, text); err != nil {
+			log.Error(err, "failed replying to Slack action")
+		}
+	}
+}
+
+// verify checks the Slack request signature, an HMAC-SHA256 of the version,
+// timestamp and body keyed by the signing secret.
+func (h *Handler) verify(header http.Header, body []byte) error {
+	if len(h.SigningSecret) == 0 {
+		return errors.New("no signing secret configured")
+	}
+	ts := header.Get("X-Slack-Request-Timestamp")
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("missing or malformed timestamp")
+	}
+	if d := h.clock().Sub(time.Unix(secs, 0)); d > maxClockSkew || d < -maxClockSkew {
+		return errors.New("stale timestamp")
+	}
+	want := Sign(h.SigningSecret, ts, body)
+	if !hmac.Equal([]byte(header.Get("X-Slack-Signature")), []byte(want)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// Sign returns the X-Slack-Signature of a request.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = fmt.Fprintf(mac, "v0:%s:", timestamp)
+	_, _ = mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func actionName(id string) string {
+	switch id {
+	case notify.SlackActionAcknowledge:
+		return "Acknowledge"
+	case notify.SlackActionException:
+		return "Create exception"
+	case notify.SlackActionRemediate:
+		return "Auto-remediate"
+	default:
+		return id
+	}
+}
+
+// apply performs an action on the report named by value and returns the
+// message posted back to Slack.
+func (h *Handler) apply(ctx context.Context, actionID, value, user string) (string, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" {
+		return "", fmt.Errorf("invalid report %q", value)
+	}
+	rep := &watchdogv1alpha1.PolicyViolationReport{}
+	if err := h.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, rep); err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", fmt.Errorf("report %s no longer exists", value)
+		}
+		return "", err
+	}
+	res := rep.Spec.ViolatedResource
+	subject := fmt.Sprintf("%s %s/%s", res.Kind, res.Namespace, res.Name)
+
+	switch actionID {
+	case notify.SlackActionAcknowledge:
+		if ack := rep.Spec.Acknowledgement; ack.Active(h.clock()) {
+			return fmt.Sprintf("ℹ️ The violation of %s was already acknowledged by %s.", subject, ack.By), nil
+		}
+		rep.Spec.Acknowledgement = &watchdogv1alpha1.Acknowledgement{
+			By: user,
+			At: metav1.NewTime(h.clock()),
+		}
+		if err := h.Client.Update(ctx, rep); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ %s acknowledged the violation of %s.", user, subject), nil
+
+	case notify.SlackActionException:
+		exc := &watchdogv1alpha1.PolicyException{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: rep.Spec.ProfileName + "-",
+				Namespace:    res.Namespace,
+			},
+			Spec: watchdogv1alpha1.PolicyExceptionSpec{
+				ProfileName:      rep.Spec.ProfileName,
+				ProfileNamespace: rep.Spec.ProfileNamespace,
+				Resource:         watchdogv1alpha1.ExceptionResourceSpec{Kind: res.Kind, Name: res.Name},
+				Reason:           "Created from Slack",
+				CreatedBy:        user,
+			},
+		}
+		if err := h.Client.Create(ctx, exc); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ %s created PolicyException %s/%s for %s.", user, exc.Namespace, exc.Name, subject), nil
+
+	case notify.SlackActionRemediate:
+		if h.Remediator == nil {
+			return "", errors.New("auto-remediation is disabled on this cluster")
+		}
+		profile := &watchdogv1alpha1.PolicyProfile{}
+		key := types.NamespacedName{Namespace: rep.Spec.ProfileNamespace, Name: rep.Spec.ProfileName}
+		if key.Namespace == "" {
+			key.Namespace = rep.Namespace
+		}
+		if err := h.Client.Get(ctx, key, profile); err != nil {
+			return "", fmt.Errorf("failed fetching PolicyProfile %s: %w", key, err)
+		}
+		profile, err := policy.ResolveTemplate(ctx, h.Client, profile)
+		if err != nil {
+			return "", err
+		}
+		labels := map[string]string{}
+		for k := range rep.Spec.Drift {
+			if v, ok := profile.Spec.Policy[k]; ok {
+				labels[k] = v
+			}
+		}
+		if len(labels) == 0 {
+			return "", errors.New("the profile no longer requires the drifted labels")
+		}
+		if err := h.Remediator.Remediate(ctx, res, labels); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ %s reset the drifted labels of %s.", user, subject), nil
+
+	default:
+		return "", fmt.Errorf("unknown action %q", actionID)
+	}
+}
+
+// reply posts text to the response URL of an interaction, without replacing
+// the original message so that its buttons stay usable.
+func (h *Handler) reply(ctx context.Context, responseURL, text string) error {
+	body, err := json.Marshal(map[string]any{
+		"response_type":    "in_channel",
+		"replace_original": false,
+		"text":             text,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c := h.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack response URL error: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slackapp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
+	"github.com/madmmas/gokubedog/internal/notify"
+)
+
+type fakeRemediator struct {
+	res    watchdogv1alpha1.ViolatedResourceSpec
+	labels map[string]string
+}
+
+func (r *fakeRemediator) Remediate(_ context.Context, res watchdogv1alpha1.ViolatedResourceSpec, labels map[string]string) error {
+	r.res, r.labels = res, labels
+	return nil
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		h       *Handler
+		cl      client.Client
+		now     time.Time
+		replies []string
+		slack   *httptest.Server
+	)
+	secret := []byte("signing-secret")
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(watchdogv1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&watchdogv1alpha1.PolicyViolationReport{
+				ObjectMeta: metav1.ObjectMeta{Name: "violation-1", Namespace: "team-a"},
+				Spec: watchdogv1alpha1.PolicyViolationReportSpec{
+					ViolatedResource: watchdogv1alpha1.ViolatedResourceSpec{Kind: "Pod", Namespace: "team-a", Name: "web"},
+					ProfileName:      "require-owner",
+					ProfileNamespace: "ops",
+					Drift:            map[string]string{"owner": "Expected: team-a, Got: "},
+				},
+			},
+			&watchdogv1alpha1.PolicyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "require-owner", Namespace: "ops"},
+				Spec:       watchdogv1alpha1.PolicyProfileSpec{Policy: map[string]string{"owner": "team-a", "tier": "web"}},
+			},
+		).Build()
+		now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		replies = nil
+		slack = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct{ Text string }
+			Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+			replies = append(replies, body.Text)
+		}))
+		DeferCleanup(slack.Close)
+		h = &Handler{Client: cl, SigningSecret: secret, now: func() time.Time { return now }}
+	})
+
+	request := func(action string, sign func(ts string, body []byte) string) *httptest.ResponseRecorder {
+		payload, err := json.Marshal(map[string]any{
+			"type":         "block_actions",
+			"user":         map[string]string{"id": "U1", "username": "alice"},
+			"actions":      []map[string]string{{"action_id": action, "value": "team-a/violation-1"}},
+			"response_url": slack.URL,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		body := []byte(url.Values{"payload": {string(payload)}}.Encode())
+		ts := strconv.FormatInt(now.Unix(), 10)
+
+		req := httptest.NewRequest(http.MethodPost, Path, strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Slack-Request-Timestamp", ts)
+		req.Header.Set("X-Slack-Signature", sign(ts, body))
+		rec := httptest.NewRecorder()
+		h.Routes()[Path].ServeHTTP(rec, req)
+		return rec
+	}
+	signed := func(ts string, body []byte) string { return Sign(secret, ts, body) }
+
+	report := func() *watchdogv1alpha1.PolicyViolationReport {
+		rep := &watchdogv1alpha1.PolicyViolationReport{}
+		Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "violation-1"}, rep)).To(Succeed())
+		return rep
+	}
+
+	It("rejects requests with a bad signature", func() {
+		rec := request(notify.SlackActionAcknowledge, func(ts string, body []byte) string {
+			return Sign([]byte("other-secret"), ts, body)
+		})
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+		Expect(report().Spec.Acknowledgement).To(BeNil())
+	})
+
+	It("rejects stale requests", func() {
+		rec := request(notify.SlackActionAcknowledge, func(ts string, body []byte) string {
+			now = now.Add(10 * time.Minute)
+			return Sign(secret, ts, body)
+		})
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("acknowledges the report", func() {
+		Expect(request(notify.SlackActionAcknowledge, signed).Code).To(Equal(http.StatusOK))
+		ack := report().Spec.Acknowledgement
+		Expect(ack).NotTo(BeNil())
+		Expect(ack.By).To(Equal("slack:alice"))
+		Expect(ack.At.Time).To(BeTemporally("==", now))
+		Expect(replies).To(ConsistOf(ContainSubstring("acknowledged the violation of Pod team-a/web")))
+
+		request(notify.SlackActionAcknowledge, signed)
+		Expect(replies[1]).To(ContainSubstring("already acknowledged by slack:alice"))
+	})
+
+	It("creates an exception for the violated resource", func() {
+		Expect(request(notify.SlackActionException, signed).Code).To(Equal(http.StatusOK))
+		var list watchdogv1alpha1.PolicyExceptionList
+		Expect(cl.List(context.Background(), &list, client.InNamespace("team-a"))).To(Succeed())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Profile()).To(Equal(types.NamespacedName{Namespace: "ops", Name: "require-owner"}))
+		Expect(list.Items[0].Spec.Resource).To(Equal(watchdogv1alpha1.ExceptionResourceSpec{Kind: "Pod", Name: "web"}))
+		Expect(list.Items[0].Spec.CreatedBy).To(Equal("slack:alice"))
+	})
+
+	It("refuses to remediate unless enabled", func() {
+		Expect(request(notify.SlackActionRemediate, signed).Code).To(Equal(http.StatusOK))
+		Expect(replies).To(ConsistOf(ContainSubstring("auto-remediation is disabled")))
+	})
+
+	It("resets the drifted labels to the policy", func() {
+		remediator := &fakeRemediator{}
+		h.Remediator = remediator
+		request(notify.SlackActionRemediate, signed)
+		Expect(remediator.res.Name).To(Equal("web"))
+		Expect(remediator.labels).To(Equal(map[string]string{"owner": "team-a"}))
+		Expect(replies).To(ConsistOf(ContainSubstring("reset the drifted labels of Pod team-a/web")))
+	})
+
+	It("resets the labels the template of the profile requires", func() {
+		profile := &watchdogv1alpha1.PolicyProfile{}
+		Expect(cl.Get(context.Background(), types.NamespacedName{Namespace: "ops", Name: "require-owner"}, profile)).To(Succeed())
+		profile.Spec.Policy = nil
+		profile.Spec.Template = &watchdogv1alpha1.TemplateReference{Name: "owned"}
+		Expect(cl.Update(context.Background(), profile)).To(Succeed())
+		Expect(cl.Create(context.Background(), &watchdogv1alpha1.PolicyTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "owned"},
+			Spec: watchdogv1alpha1.PolicyTemplateSpec{
+				Labels: []watchdogv1alpha1.LabelRule{{Key: "owner", Value: "team-a"}},
+			},
+		})).To(Succeed())
+
+		remediator := &fakeRemediator{}
+		h.Remediator = remediator
+		request(notify.SlackActionRemediate, signed)
+		Expect(remediator.labels).To(Equal(map[string]string{"owner": "team-a"}))
+		Expect(replies).To(ConsistOf(ContainSubstring("reset the drifted labels of Pod team-a/web")))
+	})
+})
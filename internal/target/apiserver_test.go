@@ -0,0 +1,194 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package target
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// fakeAPIServer serves discovery and paginated lists of NetworkPolicies in
+// namespaces ns-0 to ns-9, as full objects or as metadata depending on the
+// Accept header, and counts the requests and bytes it answers.
+type fakeAPIServer struct {
+	*httptest.Server
+	objects  int
+	requests atomic.Int64
+	bytes    atomic.Int64
+	// expire is the number of continue tokens still to be answered with
+	// 410 Gone, as when etcd compacted their revision.
+	expire atomic.Int64
+}
+
+func newFakeAPIServer(objects int) *fakeAPIServer {
+	s := &fakeAPIServer{objects: objects}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+func (s *fakeAPIServer) config() *rest.Config {
+	return &rest.Config{Host: s.URL, QPS: -1}
+}
+
+var discoveryDocs = map[string]any{
+	"/api": metav1.APIVersions{Versions: []string{"v1"}},
+	"/apis": metav1.APIGroupList{Groups: []metav1.APIGroup{
+		{
+			Name:             "networking.k8s.io",
+			Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "networking.k8s.io/v1", Version: "v1"}},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "networking.k8s.io/v1", Version: "v1"},
+		},
+		{
+			Name:             "example.com",
+			Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "example.com/v1", Version: "v1"}},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "example.com/v1", Version: "v1"},
+		},
+	}},
+	"/api/v1": metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+		{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"get", "list", "watch"}},
+		{Name: "pods/status", Namespaced: true, Kind: "Pod", Verbs: metav1.Verbs{"get"}},
+		{Name: "bindings", Namespaced: true, Kind: "Binding", Verbs: metav1.Verbs{"create"}},
+	}},
+	"/apis/networking.k8s.io/v1": metav1.APIResourceList{GroupVersion: "networking.k8s.io/v1", APIResources: []metav1.APIResource{
+		{Name: "networkpolicies", Namespaced: true, Kind: "NetworkPolicy", Verbs: metav1.Verbs{"get", "list", "watch"}},
+	}},
+	"/apis/example.com/v1": metav1.APIResourceList{GroupVersion: "example.com/v1", APIResources: []metav1.APIResource{
+		{Name: "networkpolicies", Namespaced: true, Kind: "NetworkPolicy", Verbs: metav1.Verbs{"get", "list"}},
+	}},
+}
+
+func (s *fakeAPIServer) serve(w http.ResponseWriter, req *http.Request) {
+	s.requests.Add(1)
+	if doc, ok := discoveryDocs[req.URL.Path]; ok {
+		s.write(w, doc)
+		return
+	}
+
+	namespace := ""
+	path := strings.TrimPrefix(req.URL.Path, "/apis/networking.k8s.io/v1/")
+	if rest, ok := strings.CutPrefix(path, "namespaces/"); ok {
+		namespace, path, _ = strings.Cut(rest, "/")
+	}
+	if path != "networkpolicies" {
+		http.NotFound(w, req)
+		return
+	}
+
+	var names []int
+	for i := 0; i < s.objects; i++ {
+		if namespace == "" || namespace == objectNamespace(i) {
+			names = append(names, i)
+		}
+	}
+	if req.URL.Query().Get("continue") != "" && s.expire.Add(-1) >= 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusGone)
+		body, _ := json.Marshal(metav1.Status{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Status"},
+			Status:   metav1.StatusFailure,
+			Reason:   metav1.StatusReasonExpired,
+			Code:     http.StatusGone,
+			Message:  "The provided continue parameter is too old",
+		})
+		_, _ = w.Write(body)
+		return
+	}
+	start, _ := strconv.Atoi(req.URL.Query().Get("continue"))
+	end := len(names)
+	if limit, _ := strconv.Atoi(req.URL.Query().Get("limit")); limit > 0 {
+		end = min(start+limit, len(names))
+	}
+	next := ""
+	if end < len(names) {
+		next = strconv.Itoa(end)
+	}
+
+	metadataOnly := strings.Contains(req.Header.Get("Accept"), "as=PartialObjectMetadataList")
+	items := make([]map[string]any, 0, end-start)
+	for _, i := range names[start:end] {
+		items = append(items, object(i, metadataOnly))
+	}
+	list := map[string]any{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "NetworkPolicyList",
+		"metadata":   map[string]any{"continue": next, "resourceVersion": "1"},
+		"items":      items,
+	}
+	if metadataOnly {
+		list["apiVersion"] = "meta.k8s.io/v1"
+		list["kind"] = "PartialObjectMetadataList"
+	}
+	s.write(w, list)
+}
+
+func (s *fakeAPIServer) write(w http.ResponseWriter, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.bytes.Add(int64(len(body)))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func objectNamespace(i int) string {
+	return fmt.Sprintf("ns-%d", i%10)
+}
+
+// object returns a NetworkPolicy of realistic size, or just its metadata.
+func object(i int, metadataOnly bool) map[string]any {
+	meta := map[string]any{
+		"name":              fmt.Sprintf("np-%05d", i),
+		"namespace":         objectNamespace(i),
+		"uid":               fmt.Sprintf("00000000-0000-0000-0000-%012d", i),
+		"resourceVersion":   strconv.Itoa(i + 1),
+		"creationTimestamp": "2025-01-01T00:00:00Z",
+		"labels":            map[string]any{"team": "payments", "tier": "backend", "index": strconv.Itoa(i)},
+	}
+	if metadataOnly {
+		return map[string]any{"apiVersion": "meta.k8s.io/v1", "kind": "PartialObjectMetadata", "metadata": meta}
+	}
+	var ingress []any
+	for port := 8000; port < 8010; port++ {
+		ingress = append(ingress, map[string]any{
+			"from": []any{
+				map[string]any{"podSelector": map[string]any{"matchLabels": map[string]any{"app": "frontend"}}},
+				map[string]any{"ipBlock": map[string]any{"cidr": "10.0.0.0/8", "except": []any{"10.1.0.0/16"}}},
+			},
+			"ports": []any{map[string]any{"protocol": "TCP", "port": port}},
+		})
+	}
+	return map[string]any{
+		"apiVersion": "networking.k8s.io/v1",
+		"kind":       "NetworkPolicy",
+		"metadata":   meta,
+		"spec": map[string]any{
+			"podSelector": map[string]any{"matchLabels": map[string]any{"app": "api"}},
+			"policyTypes": []any{"Ingress"},
+			"ingress":     ingress,
+		},
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package target enumerates the resources a PolicyProfile evaluates. Objects
+// are fetched one page at a time, as metadata unless the whole objects are
+// needed, so memory use is bounded by the page size rather than by the number
+// of objects in the cluster.
+package target
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// DefaultPageSize is the number of objects requested per list call.
+	DefaultPageSize = 500
+	// minRefreshInterval limits discovery refreshes triggered by unknown kinds.
+	minRefreshInterval = 30 * time.Second
+)
+
+// Lister resolves kinds to resources and pages through their metadata or
+// whole objects.
+type Lister struct {
+	Metadata  metadata.Interface
+	Dynamic   dynamic.Interface
+	Discovery discovery.DiscoveryInterface
+	// PageSize is the Limit of every list call, DefaultPageSize if zero.
+	PageSize int64
+
+	mu        sync.Mutex
+	kinds     map[string][]schema.GroupVersionResource
+	refreshed time.Time
+}
+
+// NewForConfig returns a Lister talking to the cluster described by cfg.
+func NewForConfig(cfg *rest.Config) (*Lister, error) {
+	md, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating metadata client: %w", err)
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating dynamic client: %w", err)
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating discovery client: %w", err)
+	}
+	return &Lister{Metadata: md, Dynamic: dyn, Discovery: dc}, nil
+}
+
+// NewForConfigOrDie is like NewForConfig but panics on error.
+func NewForConfigOrDie(cfg *rest.Config) *Lister {
+	l, err := NewForConfig(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return l
+}
+
+// Resolve returns the resource serving kind. The kind may be qualified with
+// its group as in kubectl, e.g. "Ingress.networking.k8s.io". An unqualified
+// kind served by several groups resolves to the group the API server prefers.
+func (l *Lister) Resolve(kind string) (schema.GroupVersionResource, error) {
+	name, group, qualified := strings.Cut(kind, ".")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for attempt := 0; attempt < 2; attempt++ {
+		for _, gvr := range l.kinds[name] {
+			if !qualified || gvr.Group == group {
+				return gvr, nil
+			}
+		}
+		// Unknown kinds may belong to a CRD installed since the last refresh
+		if attempt == 0 && time.Since(l.refreshed) >= minRefreshInterval {
+			if err := l.refresh(); err != nil {
+				return schema.GroupVersionResource{}, err
+			}
+			continue
+		}
+		break
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("no listable resource found for kind %q", kind)
+}
+
+func (l *Lister) refresh() error {
+	lists, err := discovery.ServerPreferredResources(l.Discovery)
+	if err != nil && len(lists) == 0 {
+		return fmt.Errorf("failed discovering API resources: %w", err)
+	}
+	// Partial discovery failures, typically an unavailable aggregated API,
+	// still leave the other groups usable.
+	kinds := map[string][]schema.GroupVersionResource{}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, res := range list.APIResources {
+			if strings.Contains(res.Name, "/") || !slices.Contains(res.Verbs, "list") {
+				continue
+			}
+			kinds[res.Kind] = append(kinds[res.Kind], gv.WithResource(res.Name))
+		}
+	}
+	l.kinds = kinds
+	l.refreshed = time.Now()
+	return nil
+}
+
+// Each calls fn for the metadata of every object of gvr in namespace, all
+// namespaces if empty. Pages are requested lazily and dropped once processed.
+// A non-nil error from fn stops the iteration and is returned. The list starts
+// over if its continue token expires, calling fn again for the objects of the
+// pages already processed.
+func (l *Lister) Each(
+	ctx context.Context, gvr schema.GroupVersionResource, namespace string,
+	fn func(*metav1.PartialObjectMetadata) error,
+) error {
+	opts := metav1.ListOptions{Limit: l.pageSize()}
+	for {
+		page, err := l.Metadata.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if apierrors.IsResourceExpired(err) && opts.Continue != "" {
+			// The revision of the first page was compacted away
+			opts.Continue = ""
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed listing %s: %w", gvr.String(), err)
+		}
+		for i := range page.Items {
+			if err := fn(&page.Items[i]); err != nil {
+				return err
+			}
+		}
+		if page.Continue == "" {
+			return nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+// EachObject is like Each but calls fn for every whole object, for the
+// evaluations that look past the metadata.
+func (l *Lister) EachObject(
+	ctx context.Context, gvr schema.GroupVersionResource, namespace string,
+	fn func(*unstructured.Unstructured) error,
+) error {
+	opts := metav1.ListOptions{Limit: l.pageSize()}
+	for {
+		page, err := l.Dynamic.Resource(gvr).Namespace(namespace).List(ctx, opts)
+		if apierrors.IsResourceExpired(err) && opts.Continue != "" {
+			opts.Continue = ""
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed listing %s: %w", gvr.String(), err)
+		}
+		for i := range page.Items {
+			if err := fn(&page.Items[i]); err != nil {
+				return err
+			}
+		}
+		if page.GetContinue() == "" {
+			return nil
+		}
+		opts.Continue = page.GetContinue()
+	}
+}
+
+func (l *Lister) pageSize() int64 {
+	if l.PageSize <= 0 {
+		return DefaultPageSize
+	}
+	return l.PageSize
+}
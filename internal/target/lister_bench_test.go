@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package target
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// The benchmarks compare the former unpaginated list of full objects with the
+// paged listings of metadata and of full objects, the latter for profiles
+// running checks, on a 20k object cluster. Besides the usual
+// allocation figures they report the requests and bytes served per
+// evaluation and the peak heap in use while walking the objects, e.g.
+//
+//	go test ./internal/target -run '^$' -bench . -benchtime 5x
+const benchmarkObjects = 20000
+
+var benchmarkGVR = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+
+// heapSampler records the highest heap usage seen across samples.
+type heapSampler struct {
+	peak uint64
+}
+
+func (h *heapSampler) sample() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	h.peak = max(h.peak, stats.HeapInuse)
+}
+
+func reportServer(b *testing.B, server *fakeAPIServer, heap *heapSampler) {
+	b.ReportMetric(float64(server.requests.Load())/float64(b.N), "requests/op")
+	b.ReportMetric(float64(server.bytes.Load())/float64(b.N)/(1<<20), "MB-served/op")
+	b.ReportMetric(float64(heap.peak)/(1<<20), "peak-heap-MB")
+}
+
+func BenchmarkListFullObjects(b *testing.B) {
+	server := newFakeAPIServer(benchmarkObjects)
+	defer server.Close()
+	client := dynamic.NewForConfigOrDie(server.config())
+	heap := &heapSampler{}
+
+	runtime.GC()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list, err := client.Resource(benchmarkGVR).Namespace("").List(context.Background(), metav1.ListOptions{})
+		if err != nil {
+			b.Fatal(err)
+		}
+		matched := 0
+		for j := range list.Items {
+			if list.Items[j].GetLabels()["team"] == "payments" {
+				matched++
+			}
+			if j%1000 == 0 {
+				heap.sample()
+			}
+		}
+		if matched != benchmarkObjects {
+			b.Fatalf("matched %d objects", matched)
+		}
+	}
+	b.StopTimer()
+	reportServer(b, server, heap)
+}
+
+func BenchmarkEachMetadata(b *testing.B) {
+	for _, pageSize := range []int64{100, DefaultPageSize, 2000} {
+		b.Run("page="+strconv.FormatInt(pageSize, 10), func(b *testing.B) {
+			server := newFakeAPIServer(benchmarkObjects)
+			defer server.Close()
+			lister := NewForConfigOrDie(server.config())
+			lister.PageSize = pageSize
+			heap := &heapSampler{}
+
+			runtime.GC()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				matched, seen := 0, 0
+				err := lister.Each(context.Background(), benchmarkGVR, "", func(obj *metav1.PartialObjectMetadata) error {
+					if obj.Labels["team"] == "payments" {
+						matched++
+					}
+					if seen++; seen%1000 == 0 {
+						heap.sample()
+					}
+					return nil
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if matched != benchmarkObjects {
+					b.Fatalf("matched %d objects", matched)
+				}
+			}
+			b.StopTimer()
+			reportServer(b, server, heap)
+		})
+	}
+}
+
+func BenchmarkEachObject(b *testing.B) {
+	server := newFakeAPIServer(benchmarkObjects)
+	defer server.Close()
+	lister := NewForConfigOrDie(server.config())
+	heap := &heapSampler{}
+
+	runtime.GC()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matched, seen := 0, 0
+		err := lister.EachObject(context.Background(), benchmarkGVR, "", func(obj *unstructured.Unstructured) error {
+			if obj.GetLabels()["team"] == "payments" {
+				matched++
+			}
+			if seen++; seen%1000 == 0 {
+				heap.sample()
+			}
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if matched != benchmarkObjects {
+			b.Fatalf("matched %d objects", matched)
+		}
+	}
+	b.StopTimer()
+	reportServer(b, server, heap)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package target
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var _ = Describe("Lister", func() {
+	var (
+		server *fakeAPIServer
+		lister *Lister
+		ctx    = context.Background()
+		gvr    = schema.GroupVersionResource{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies"}
+	)
+
+	BeforeEach(func() {
+		server = newFakeAPIServer(25)
+		DeferCleanup(server.Close)
+		lister = NewForConfigOrDie(server.config())
+		lister.PageSize = 10
+	})
+
+	It("should resolve kinds through discovery", func() {
+		Expect(lister.Resolve("Pod")).To(Equal(schema.GroupVersionResource{Version: "v1", Resource: "pods"}))
+		Expect(lister.Resolve("NetworkPolicy")).To(Equal(gvr))
+		Expect(lister.Resolve("NetworkPolicy.example.com")).To(Equal(
+			schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "networkpolicies"}))
+
+		_, err := lister.Resolve("Binding")
+		Expect(err).To(HaveOccurred())
+		_, err = lister.Resolve("Unknown")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should not refresh discovery on every unknown kind", func() {
+		_, err := lister.Resolve("Pod")
+		Expect(err).NotTo(HaveOccurred())
+		requests := server.requests.Load()
+		_, err = lister.Resolve("Unknown")
+		Expect(err).To(HaveOccurred())
+		Expect(server.requests.Load()).To(Equal(requests))
+	})
+
+	It("should page through the metadata of all objects", func() {
+		var names []string
+		Expect(lister.Each(ctx, gvr, "", func(obj *metav1.PartialObjectMetadata) error {
+			names = append(names, obj.Name)
+			return nil
+		})).To(Succeed())
+		Expect(names).To(HaveLen(25))
+		Expect(names[24]).To(Equal("np-00024"))
+		Expect(server.requests.Load()).To(BeEquivalentTo(3))
+	})
+
+	It("should page through whole objects in as many requests", func() {
+		var names []string
+		Expect(lister.EachObject(ctx, gvr, "", func(obj *unstructured.Unstructured) error {
+			Expect(obj.GetKind()).To(Equal("NetworkPolicy"))
+			Expect(obj.Object).To(HaveKey("spec"))
+			names = append(names, obj.GetName())
+			return nil
+		})).To(Succeed())
+		Expect(names).To(HaveLen(25))
+		Expect(names[24]).To(Equal("np-00024"))
+		Expect(server.requests.Load()).To(BeEquivalentTo(3))
+
+		count := 0
+		Expect(lister.EachObject(ctx, gvr, "ns-3", func(obj *unstructured.Unstructured) error {
+			Expect(obj.GetNamespace()).To(Equal("ns-3"))
+			count++
+			return nil
+		})).To(Succeed())
+		Expect(count).To(Equal(3))
+		Expect(server.requests.Load()).To(BeEquivalentTo(4))
+	})
+
+	It("should start over when the continue token expires", func() {
+		server.expire.Store(1)
+		var names []string
+		Expect(lister.Each(ctx, gvr, "", func(obj *metav1.PartialObjectMetadata) error {
+			names = append(names, obj.Name)
+			return nil
+		})).To(Succeed())
+		Expect(names).To(HaveLen(35), "the first page is seen twice")
+		Expect(names[34]).To(Equal("np-00024"))
+
+		server.expire.Store(1)
+		count := 0
+		Expect(lister.EachObject(ctx, gvr, "", func(*unstructured.Unstructured) error {
+			count++
+			return nil
+		})).To(Succeed())
+		Expect(count).To(Equal(35))
+	})
+
+	It("should list a single namespace and stop on callback errors", func() {
+		count := 0
+		Expect(lister.Each(ctx, gvr, "ns-3", func(obj *metav1.PartialObjectMetadata) error {
+			Expect(obj.Namespace).To(Equal("ns-3"))
+			Expect(obj.Labels).To(HaveKeyWithValue("team", "payments"))
+			count++
+			return nil
+		})).To(Succeed())
+		Expect(count).To(Equal(3))
+
+		stop := errors.New("stop")
+		count = 0
+		Expect(lister.Each(ctx, gvr, "", func(*metav1.PartialObjectMetadata) error {
+			count++
+			return stop
+		})).To(MatchError(stop))
+		Expect(count).To(Equal(1))
+	})
+})
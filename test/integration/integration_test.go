@@ -25,6 +25,7 @@ import (
 	watchdogv1alpha1 "github.com/madmmas/gokubedog/api/v1alpha1"
 	"github.com/madmmas/gokubedog/internal/controller"
 	"github.com/madmmas/gokubedog/internal/controller/watchdog"
+	"github.com/madmmas/gokubedog/internal/target"
 )
 
 type roundTripperFunc func(*http.Request) (*http.Response, error)
@@ -94,7 +95,7 @@ var _ = BeforeSuite(func() {
 	policyProfileReconciler := &controller.PolicyProfileReconciler{
 		Client:  mgr.GetClient(),
 		Scheme:  mgr.GetScheme(),
-		DynClnt: dynamic.NewForConfigOrDie(cfg),
+		Targets: target.NewForConfigOrDie(cfg),
 	}
 	Expect(policyProfileReconciler.SetupWithManager(mgr)).To(Succeed())
 
@@ -177,7 +178,7 @@ var _ = Describe("Controller Integration", func() {
 		policyProfileReconciler := &controller.PolicyProfileReconciler{
 			Client:  k8sClient,
 			Scheme:  scheme.Scheme,
-			DynClnt: dynClnt,
+			Targets: target.NewForConfigOrDie(cfg),
 		}
 		_, err = policyProfileReconciler.Reconcile(context.Background(), reconcile.Request{
 			NamespacedName: client.ObjectKeyFromObject(profile),
@@ -222,8 +223,7 @@ var _ = Describe("Controller Integration", func() {
 			if len(reports.Items) == 0 {
 				return false
 			}
-			fmt.Printf("Report annotations: %v\n", reports.Items[0].Annotations)
-			return reports.Items[0].Annotations["notified"] == "true"
+			return reports.Items[0].Status.Delivery(watchdog.DefaultChannelName) != nil
 		}, 10*time.Second, 1*time.Second).Should(BeTrue())
 	})
 })